@@ -0,0 +1,85 @@
+// fakeshowpass sends signed Showpass purchase webhooks to a local server,
+// so the webhook can be tried without a Showpass account:
+//
+//	go run ./cmd/fakeshowpass -student 12345678 -first Jane -last Smith
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ubcesports/echo-base/config"
+	"github.com/ubcesports/echo-base/internal/models"
+	"github.com/ubcesports/echo-base/internal/services"
+)
+
+func main() {
+	config.LoadEnv(".env")
+
+	url := flag.String("url", "http://localhost:8080/webhooks/showpass", "webhook endpoint to deliver to")
+	secret := flag.String("secret", os.Getenv("EB_SHOWPASS_WEBHOOK_SECRET"), "webhook secret, defaults to EB_SHOWPASS_WEBHOOK_SECRET")
+	event := flag.String("event", models.ShowpassEventPurchase, "webhook event")
+	id := flag.String("id", "", "purchase id, random by default")
+	ticket := flag.String("ticket", "Tier 1 Membership", "ticket type bought")
+	student := flag.String("student", "", "student number of the buyer")
+	first := flag.String("first", "Test", "first name of the buyer")
+	last := flag.String("last", "Member", "last name of the buyer")
+	times := flag.Int("times", 1, "how many times to deliver the purchase, to check redeliveries are ignored")
+	flag.Parse()
+
+	if *student == "" {
+		println("please specify the student number with -student")
+		os.Exit(1)
+	}
+
+	if *id == "" {
+		b := make([]byte, 8)
+		rand.Read(b)
+		*id = "fake-" + hex.EncodeToString(b)
+	}
+
+	now := time.Now()
+	body, err := json.Marshal(models.ShowpassWebhook{
+		Event: *event,
+		Purchase: models.ShowpassPurchase{
+			ID:            *id,
+			TicketType:    *ticket,
+			StudentNumber: *student,
+			FirstName:     *first,
+			LastName:      *last,
+			PurchasedAt:   &now,
+		},
+	})
+	if err != nil {
+		println("error while encoding webhook:", err.Error())
+		os.Exit(1)
+	}
+
+	for i := 0; i < *times; i++ {
+		req, err := http.NewRequest(http.MethodPost, *url, bytes.NewReader(body))
+		if err != nil {
+			println("error while creating request:", err.Error())
+			os.Exit(1)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(services.ShowpassSignatureHeader, services.SignShowpassPayload([]byte(*secret), body))
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			println("error while delivering webhook:", err.Error())
+			os.Exit(1)
+		}
+		response, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		fmt.Printf("delivery %d of purchase %s: %s %s\n", i+1, *id, resp.Status, bytes.TrimSpace(response))
+	}
+}
@@ -2,10 +2,20 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ubcesports/echo-base/config"
 	"github.com/ubcesports/echo-base/internal/database"
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
+	"github.com/ubcesports/echo-base/internal/models"
 	"github.com/ubcesports/echo-base/internal/services"
 )
 
@@ -19,15 +29,79 @@ func main() {
 		os.Exit(1)
 	}
 
-	if os.Args[1] != "apikey" {
+	auditService := services.NewAuditService(database.NewAuditRepository(database.DB))
+	ctx := context.Background()
+
+	switch os.Args[1] {
+	case "apikey":
+		authConfig := services.DefaultAuthServiceConfig()
+		authConfig.SigningPepper = []byte(os.Getenv("EB_HMAC_PEPPER"))
+		authService := services.NewAuthServiceWithConfig(database.NewAuthRepository(database.DB), auditService, authConfig)
+		runAPIKey(ctx, authService, os.Args[2:])
+	case "exec":
+		// Token settings are irrelevant here since no tokens are issued
+		execService := services.NewExecService(database.NewExecRepository(database.DB), auditService, services.ExecServiceConfig{
+			TokenSecret: []byte("unused"),
+		})
+		runExec(ctx, execService, os.Args[2:])
+	case "import-members":
+		profileService := services.NewGamerProfileService(database.NewGamerProfileRepository(database.DB), auditService)
+		importMembers(ctx, profileService, os.Args[2:])
+	case "export-member":
+		profileRepo := database.NewGamerProfileRepository(database.DB)
+		activityService := services.NewGamerActivityService(database.NewGamerActivityRepository(database.DB), profileRepo, database.NewStationRepository(database.DB), database.NewReservationRepository(database.DB), auditService)
+		exportMember(ctx, services.NewMemberExportService(profileRepo, activityService, auditService), os.Args[2:])
+	default:
 		println("operation not supported")
 		os.Exit(1)
 	}
+}
+
+func runAPIKey(ctx context.Context, authService services.AuthService, args []string) {
+	switch args[0] {
+	case "list":
+		listKeys(ctx, authService)
+	case "revoke":
+		revokeKey(ctx, authService, args[1:])
+	case "rotate":
+		rotateKey(ctx, authService, args[1:])
+	case "cidrs":
+		updateAllowedCIDRs(ctx, authService, args[1:])
+	default:
+		generateKey(ctx, authService, args)
+	}
+}
+
+func runExec(ctx context.Context, execService services.ExecService, args []string) {
+	switch args[0] {
+	case "list":
+		listExecs(ctx, execService)
+	case "add":
+		addExec(ctx, execService, args[1:])
+	default:
+		println("exec operation not supported")
+		os.Exit(1)
+	}
+}
+
+// generateKey handles `apikey <app_name> [scope,scope,...] [bearer|hmac]`
+func generateKey(ctx context.Context, authService services.AuthService, args []string) {
+	// Keys created from the command line default to admin so that the first
+	// key can be used to issue narrower keys through the API
+	scopes := []auth.Scope{auth.ScopeAdmin}
+	if len(args) > 1 {
+		scopes = nil
+		for _, s := range strings.Split(args[1], ",") {
+			scopes = append(scopes, auth.Scope(strings.TrimSpace(s)))
+		}
+	}
 
-	authRepo := database.NewAuthRepository(database.DB)
-	authService := services.NewAuthService(authRepo)
+	opts := auth.KeyOptions{Scopes: scopes}
+	if len(args) > 2 {
+		opts.AuthMode = auth.AuthMode(args[2])
+	}
 
-	response, err := authService.GenerateAPIKey(context.Background(), os.Args[2])
+	response, err := authService.GenerateAPIKey(ctx, args[0], opts)
 
 	if err != nil {
 		println("error while generating api key:", err.Error())
@@ -37,4 +111,314 @@ func main() {
 	println("generated api key!")
 	println("key id:", response.KeyId)
 	println("token:", response.APIKey)
+	if response.SigningKey != "" {
+		println("signing key:", response.SigningKey)
+	}
+}
+
+// listKeys handles `apikey list`
+func listKeys(ctx context.Context, authService services.AuthService) {
+	keys, err := authService.ListAPIKeys(ctx)
+	if err != nil {
+		println("error while listing api keys:", err.Error())
+		os.Exit(1)
+	}
+
+	fmt.Printf("%-12s %-24s %-8s %-20s %-20s %-20s %-20s %s\n", "KEY ID", "APP", "MODE", "CREATED", "LAST USED", "EXPIRES", "REVOKED", "ALLOWED CIDRS")
+	for _, key := range keys {
+		cidrs := "any"
+		if len(key.AllowedCIDRs) > 0 {
+			values := make([]string, len(key.AllowedCIDRs))
+			for i, c := range key.AllowedCIDRs {
+				values[i] = c.String()
+			}
+			cidrs = strings.Join(values, ",")
+		}
+
+		fmt.Printf("%-12s %-24s %-8s %-20s %-20s %-20s %-20s %s\n",
+			key.KeyId,
+			key.AppName,
+			key.AuthMode,
+			formatTime(&key.CreatedAt),
+			formatTime(key.LastUsedAt),
+			formatTime(key.ExpiresAt),
+			formatTime(key.RevokedAt),
+			cidrs,
+		)
+	}
+}
+
+// revokeKey handles `apikey revoke <key_id>`
+func revokeKey(ctx context.Context, authService services.AuthService, args []string) {
+	if len(args) < 1 {
+		println("please specify the key id to revoke")
+		os.Exit(1)
+	}
+
+	if err := authService.RevokeAPIKey(ctx, args[0]); err != nil {
+		println("error while revoking api key:", err.Error())
+		os.Exit(1)
+	}
+
+	println("revoked api key", args[0])
+}
+
+// rotateKey handles `apikey rotate <key_id> [overlap_hours]`
+func rotateKey(ctx context.Context, authService services.AuthService, args []string) {
+	if len(args) < 1 {
+		println("please specify the key id to rotate")
+		os.Exit(1)
+	}
+
+	overlap := services.DefaultRotationOverlap
+	if len(args) > 1 {
+		hours, err := strconv.Atoi(args[1])
+		if err != nil {
+			println("overlap must be a whole number of hours")
+			os.Exit(1)
+		}
+		overlap = time.Duration(hours) * time.Hour
+	}
+
+	response, err := authService.RotateAPIKey(ctx, args[0], overlap)
+	if err != nil {
+		println("error while rotating api key:", err.Error())
+		os.Exit(1)
+	}
+
+	println("rotated api key!")
+	println("key id:", response.KeyId)
+	println("token:", response.APIKey)
+	if response.SigningKey != "" {
+		println("signing key:", response.SigningKey)
+	}
+	println("previous token valid until:", time.Now().Add(overlap).Format(time.RFC3339))
+}
+
+// updateAllowedCIDRs handles `apikey cidrs <key_id> [cidr,cidr,...]`. Leaving
+// out the ranges lets the key be used from anywhere again.
+func updateAllowedCIDRs(ctx context.Context, authService services.AuthService, args []string) {
+	if len(args) < 1 {
+		println("please specify the key id to restrict")
+		os.Exit(1)
+	}
+
+	var cidrs []string
+	if len(args) > 1 {
+		cidrs = strings.Split(args[1], ",")
+	}
+
+	if err := authService.UpdateAllowedCIDRs(ctx, args[0], cidrs); err != nil {
+		println("error while updating allowed cidrs:", err.Error())
+		os.Exit(1)
+	}
+
+	if len(cidrs) == 0 {
+		println("api key", args[0], "can be used from any address")
+		return
+	}
+	println("api key", args[0], "restricted to", strings.Join(cidrs, ", "))
+}
+
+// addExec handles `exec add <email> <name> [role]` and prints a generated
+// password for the exec to log in with
+func addExec(ctx context.Context, execService services.ExecService, args []string) {
+	if len(args) < 2 {
+		println("please specify the exec's email and name")
+		os.Exit(1)
+	}
+
+	req := &models.CreateExecRequest{Email: args[0], Name: args[1]}
+	if len(args) > 2 {
+		req.Role = models.ExecRole(args[2])
+	}
+
+	password, err := generatePassword()
+	if err != nil {
+		println("error while generating password:", err.Error())
+		os.Exit(1)
+	}
+	req.Password = password
+
+	exec, err := execService.CreateExec(ctx, req)
+	if err != nil {
+		println("error while creating exec:", err.Error())
+		os.Exit(1)
+	}
+
+	println("created exec!")
+	println("id:", exec.ID)
+	println("email:", exec.Email)
+	println("password:", password)
+}
+
+// listExecs handles `exec list`
+func listExecs(ctx context.Context, execService services.ExecService) {
+	execs, err := execService.ListExecs(ctx)
+	if err != nil {
+		println("error while listing execs:", err.Error())
+		os.Exit(1)
+	}
+
+	fmt.Printf("%-36s %-24s %-32s %-10s %-6s\n", "ID", "NAME", "EMAIL", "ROLE", "ACTIVE")
+	for _, e := range execs {
+		fmt.Printf("%-36s %-24s %-32s %-10s %-6t\n", e.ID, e.Name, e.Email, e.Role, e.Active)
+	}
+}
+
+// importMembers handles `import-members [-dry-run] [-atomic]
+// [-columns field=Header,...] [-tiers "Ticket Type=tier,..."] <file.csv>`
+func importMembers(ctx context.Context, profileService services.GamerProfileService, args []string) {
+	flags := flag.NewFlagSet("import-members", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "report what would change without writing anything")
+	atomic := flags.Bool("atomic", false, "write every row or none of them")
+	columns := flags.String("columns", "", "headers to read fields from, as field=Header pairs")
+	tiers := flags.String("tiers", "", "membership tiers of ticket types, as Ticket Type=tier pairs")
+	flags.Parse(args)
+
+	if flags.NArg() < 1 {
+		println("please specify the csv file to import")
+		os.Exit(1)
+	}
+
+	opts := models.ImportOptions{DryRun: *dryRun, Atomic: *atomic}
+	for field, header := range parsePairs(*columns) {
+		switch field {
+		case "student_number":
+			opts.Columns.StudentNumber = header
+		case "first_name":
+			opts.Columns.FirstName = header
+		case "last_name":
+			opts.Columns.LastName = header
+		case "membership_tier":
+			opts.Columns.MembershipTier = header
+		case "notes":
+			opts.Columns.Notes = header
+		case "email":
+			opts.Columns.Email = header
+		case "discord_handle":
+			opts.Columns.DiscordHandle = header
+		default:
+			println("unknown column field:", field)
+			os.Exit(1)
+		}
+	}
+
+	if *tiers != "" {
+		opts.Tiers = make(map[string]int)
+		for name, value := range parsePairs(*tiers) {
+			tier, err := strconv.Atoi(value)
+			if err != nil {
+				println("tier for", name, "must be a number")
+				os.Exit(1)
+			}
+			opts.Tiers[name] = tier
+		}
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		println("error while opening file:", err.Error())
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	report, err := profileService.ImportProfiles(ctx, file, opts)
+	if err != nil {
+		println("error while importing members:", err.Error())
+		os.Exit(1)
+	}
+
+	fmt.Printf("%-5s %-14s %-10s %s\n", "ROW", "STUDENT", "STATUS", "PROBLEM")
+	for _, row := range report.Rows {
+		problem := row.Message
+		if row.Field != "" {
+			problem = row.Field + " " + row.Message
+		}
+		fmt.Printf("%-5d %-14s %-10s %s\n", row.Row, row.StudentNumber, row.Status, problem)
+	}
+
+	fmt.Printf("\n%d created, %d updated, %d skipped, %d conflicts, %d invalid, %d failed\n",
+		report.Created, report.Updated, report.Skipped, report.Conflicts, report.Invalid, report.Failed)
+	switch {
+	case report.DryRun:
+		println("dry run, nothing was written")
+	case !report.Committed:
+		println("atomic import abandoned, nothing was written")
+		os.Exit(1)
+	}
+}
+
+// exportMember handles `export-member [-format json|zip] [-o file]
+// <student_number>`, writing to stdout unless a file is given
+func exportMember(ctx context.Context, exportService services.MemberExportService, args []string) {
+	flags := flag.NewFlagSet("export-member", flag.ExitOnError)
+	format := flags.String("format", "json", "json, or zip for a zip of CSV files")
+	output := flags.String("o", "", "file to write the export to")
+	flags.Parse(args)
+
+	if flags.NArg() < 1 {
+		println("please specify the student number to export")
+		os.Exit(1)
+	}
+	if *format != "json" && *format != "zip" {
+		println("format must be json or zip")
+		os.Exit(1)
+	}
+
+	export, err := exportService.ExportMember(ctx, flags.Arg(0))
+	if err != nil {
+		println("error while exporting member:", err.Error())
+		os.Exit(1)
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		out, err = os.Create(*output)
+		if err != nil {
+			println("error while creating file:", err.Error())
+			os.Exit(1)
+		}
+		defer out.Close()
+	}
+
+	if *format == "zip" {
+		err = services.WriteMemberExportZip(out, export)
+	} else {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(export)
+	}
+	if err != nil {
+		println("error while writing export:", err.Error())
+		os.Exit(1)
+	}
+}
+
+// parsePairs splits "key=value,key=value" into a map
+func parsePairs(s string) map[string]string {
+	pairs := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		pairs[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return pairs
+}
+
+func generatePassword() (string, error) {
+	b := make([]byte, 18)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04")
 }
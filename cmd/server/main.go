@@ -6,12 +6,15 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/ubcesports/echo-base/config"
 	"github.com/ubcesports/echo-base/internal"
 	"github.com/ubcesports/echo-base/internal/database"
+	"github.com/ubcesports/echo-base/internal/middleware"
 	"github.com/ubcesports/echo-base/internal/services"
 )
 
@@ -25,6 +28,9 @@ func main() {
 }
 
 func bootstrap(ctx context.Context, args []string) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	config.LoadEnv(".env")
 	database.Init()
 
@@ -32,20 +38,67 @@ func bootstrap(ctx context.Context, args []string) error {
 	authRepo := database.NewAuthRepository(database.DB)
 	gamerProfileRepo := database.NewGamerProfileRepository(database.DB)
 	gamerActivityRepo := database.NewGamerActivityRepository(database.DB)
+	stationRepo := database.NewStationRepository(database.DB)
+	reservationRepo := database.NewReservationRepository(database.DB)
+	auditRepo := database.NewAuditRepository(database.DB)
+	execRepo := database.NewExecRepository(database.DB)
 
 	// Initialize services
-	authService := services.NewAuthService(authRepo)
-	gamerProfileService := services.NewGamerProfileService(gamerProfileRepo)
-	gamerActivityService := services.NewGamerActivityService(gamerActivityRepo, gamerProfileRepo)
+	auditService := services.NewAuditService(auditRepo)
+	authConfig := services.DefaultAuthServiceConfig()
+	authConfig.CacheTTL = config.GetDuration("EB_AUTH_CACHE_TTL", authConfig.CacheTTL)
+	authConfig.CacheSize = config.GetInt("EB_AUTH_CACHE_SIZE", authConfig.CacheSize)
+	authConfig.FlushInterval = config.GetDuration("EB_LAST_USED_FLUSH_INTERVAL", authConfig.FlushInterval)
+	authConfig.MaxFailedAttempts = config.GetInt("EB_AUTH_MAX_FAILED_ATTEMPTS", authConfig.MaxFailedAttempts)
+	authConfig.LockoutDuration = config.GetDuration("EB_AUTH_LOCKOUT_DURATION", authConfig.LockoutDuration)
+	authConfig.MaxClockSkew = config.GetDuration("EB_AUTH_MAX_CLOCK_SKEW", authConfig.MaxClockSkew)
+	authConfig.SigningPepper = []byte(os.Getenv("EB_HMAC_PEPPER"))
+	authService := services.NewAuthServiceWithConfig(authRepo, auditService, authConfig)
+	gamerProfileService := services.NewGamerProfileService(gamerProfileRepo, auditService)
+	activityConfig := services.DefaultGamerActivityServiceConfig()
+	activityConfig.OvertimeGrace = config.GetDuration("EB_SESSION_OVERTIME_GRACE", activityConfig.OvertimeGrace)
+	activityConfig.ReapInterval = config.GetDuration("EB_SESSION_REAP_INTERVAL", activityConfig.ReapInterval)
+	activityConfig.AutoEndOverdue = config.GetBool("EB_SESSION_AUTO_END", activityConfig.AutoEndOverdue)
+	gamerActivityService := services.NewGamerActivityServiceWithConfig(gamerActivityRepo, gamerProfileRepo, stationRepo, reservationRepo, auditService, activityConfig)
+	execConfig := services.DefaultExecServiceConfig()
+	execConfig.TokenSecret = []byte(os.Getenv("EB_JWT_SECRET"))
+	execConfig.TokenTTL = config.GetDuration("EB_EXEC_TOKEN_TTL", execConfig.TokenTTL)
+	execConfig.MaxFailedLogins = authConfig.MaxFailedAttempts
+	execConfig.LockoutDuration = authConfig.LockoutDuration
+	execService := services.NewExecService(execRepo, auditService, execConfig)
+	showpassConfig := services.DefaultShowpassServiceConfig()
+	showpassConfig.WebhookSecret = []byte(os.Getenv("EB_SHOWPASS_WEBHOOK_SECRET"))
+	showpassService := services.NewShowpassService(gamerProfileService, showpassConfig)
+	memberExportService := services.NewMemberExportService(gamerProfileRepo, gamerActivityService, auditService)
+	stationService := services.NewStationService(stationRepo, gamerActivityService, auditService)
+	reservationConfig := services.DefaultReservationServiceConfig()
+	reservationConfig.NoShowGrace = config.GetDuration("EB_RESERVATION_NO_SHOW_GRACE", reservationConfig.NoShowGrace)
+	reservationConfig.ReleaseInterval = config.GetDuration("EB_RESERVATION_RELEASE_INTERVAL", reservationConfig.ReleaseInterval)
+	reservationConfig.MaxDuration = config.GetDuration("EB_RESERVATION_MAX_DURATION", reservationConfig.MaxDuration)
+	reservationService := services.NewReservationServiceWithConfig(reservationRepo, stationRepo, gamerProfileRepo, auditService, reservationConfig)
+
+	trustedProxies, err := middleware.ParseTrustedProxies(os.Getenv("EB_TRUSTED_PROXIES"))
+	if err != nil {
+		return err
+	}
 
 	// Initialize server
-	srv := internal.NewServer(authService, gamerProfileService, gamerActivityService)
+	srv := internal.NewServer(authService, gamerProfileService, gamerActivityService, auditService, execService, showpassService, memberExportService, stationService, reservationService, trustedProxies)
 
 	httpServer := &http.Server{
 		Addr:    ":" + os.Getenv("EB_PORT"),
 		Handler: srv,
 	}
 
+	// Batch API key last_used_at writes in the background
+	go authService.RunLastUsedFlusher(ctx)
+
+	// Flag or end sessions that run past their tier's allowance
+	go gamerActivityService.RunOvertimeReaper(ctx)
+
+	// Give reserved stations back to walk-ins when nobody shows up
+	go reservationService.RunNoShowReleaser(ctx)
+
 	// Run server in its own goroutine
 	go func() {
 		log.Printf("listening on %s\n", httpServer.Addr)
@@ -66,6 +119,9 @@ func bootstrap(ctx context.Context, args []string) error {
 		if err := httpServer.Shutdown(shutdownCtx); err != nil {
 			fmt.Fprintf(os.Stderr, "error shutting down http server: %s\n", err)
 		}
+		if err := authService.FlushLastUsed(shutdownCtx); err != nil {
+			fmt.Fprintf(os.Stderr, "error flushing api key usage: %s\n", err)
+		}
 		if err := database.Close(); err != nil {
 			fmt.Fprintf(os.Stderr, "error closing database: %s\n", err)
 		}
@@ -2,8 +2,11 @@ package config
 
 import (
 	"bufio"
+	"log"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 func LoadEnv(path string) error {
@@ -34,3 +37,51 @@ func LoadEnv(path string) error {
 	}
 	return nil
 }
+
+// GetDuration reads a duration such as "30s" or "5m" from the environment,
+// returning fallback when the variable is unset or malformed
+func GetDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("invalid duration %q for %s, using %s", value, key, fallback)
+		return fallback
+	}
+	return d
+}
+
+// GetInt reads an integer from the environment, returning fallback when the
+// variable is unset or malformed
+func GetInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("invalid integer %q for %s, using %d", value, key, fallback)
+		return fallback
+	}
+	return n
+}
+
+// GetBool reads a boolean such as "true" or "0" from the environment,
+// returning fallback when the variable is unset or malformed
+func GetBool(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("invalid boolean %q for %s, using %t", value, key, fallback)
+		return fallback
+	}
+	return b
+}
@@ -0,0 +1,116 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/ubcesports/echo-base/internal/database/sqlc"
+	"github.com/ubcesports/echo-base/internal/interfaces/audit"
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+type AuditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) audit.AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (r *AuditRepository) Create(ctx context.Context, entry *models.AuditEntry) error {
+	queries := sqlc.New(r.db)
+	err := queries.CreateAuditLog(ctx, sqlc.CreateAuditLogParams{
+		ActorApp:    nullString(entry.ActorApp),
+		ActorExecID: nullUUID(entry.ActorExecID),
+		Method:      entry.Method,
+		Route:       entry.Route,
+		Action:      entry.Action,
+		EntityType:  entry.EntityType,
+		EntityID:    entry.EntityID,
+		Before:      entry.Before,
+		After:       entry.After,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create audit entry: %w", err)
+	}
+	return nil
+}
+
+func (r *AuditRepository) List(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	var actorExec *string
+	if filter.ActorExecID != "" {
+		actorExec = &filter.ActorExecID
+	}
+
+	queries := sqlc.New(r.db)
+	rows, err := queries.ListAuditLogs(ctx, sqlc.ListAuditLogsParams{
+		EntityType:  nullStringValue(filter.EntityType),
+		EntityID:    nullStringValue(filter.EntityID),
+		ActorApp:    nullStringValue(filter.ActorApp),
+		ActorExecID: nullUUID(actorExec),
+		CreatedFrom: nullTime(filter.From),
+		CreatedTo:   nullTime(filter.To),
+		Limit:       int64(filter.Limit),
+		Offset:      int64((filter.Page - 1) * filter.Limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	return toAuditEntries(rows), nil
+}
+
+// ListForStudent returns every entry about a member, oldest first
+func (r *AuditRepository) ListForStudent(ctx context.Context, studentNumber string) ([]models.AuditEntry, error) {
+	queries := sqlc.New(r.db)
+	rows, err := queries.ListAuditLogsForStudent(ctx, studentNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	return toAuditEntries(rows), nil
+}
+
+func toAuditEntries(rows []sqlc.AuditLog) []models.AuditEntry {
+	entries := make([]models.AuditEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = models.AuditEntry{
+			ID:         row.ID.String(),
+			Method:     row.Method,
+			Route:      row.Route,
+			Action:     row.Action,
+			EntityType: row.EntityType,
+			EntityID:   row.EntityID,
+			Before:     row.Before,
+			After:      row.After,
+			CreatedAt:  row.CreatedAt,
+		}
+		if row.ActorApp.Valid {
+			entries[i].ActorApp = &row.ActorApp.String
+		}
+		if row.ActorExecID.Valid {
+			actorExec := row.ActorExecID.UUID.String()
+			entries[i].ActorExecID = &actorExec
+		}
+	}
+	return entries
+}
+
+// nullUUID treats nil and unparseable ids as NULL
+func nullUUID(v *string) uuid.NullUUID {
+	if v == nil {
+		return uuid.NullUUID{}
+	}
+	id, err := uuid.Parse(*v)
+	if err != nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: id, Valid: true}
+}
+
+// nullStringValue treats "" as NULL, for optional filters
+func nullStringValue(v string) sql.NullString {
+	return sql.NullString{Valid: v != "", String: v}
+}
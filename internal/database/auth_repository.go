@@ -3,10 +3,19 @@ package database
 import (
 	"context"
 	"database/sql"
+	"net/netip"
+	"time"
 
+	"github.com/lib/pq"
+	"github.com/ubcesports/echo-base/internal/errors"
 	"github.com/ubcesports/echo-base/internal/interfaces/auth"
 )
 
+const applicationColumns = `
+        app_name, key_id, hashed_key, scopes, created_at, last_used_at,
+        expires_at, revoked_at, previous_hashed_key, previous_key_expires_at,
+        rate_limit_per_minute, rate_limit_burst, auth_mode, allowed_cidrs::TEXT[]`
+
 type AuthRepository struct {
 	db *sql.DB
 }
@@ -17,36 +26,242 @@ func NewAuthRepository(db *sql.DB) *AuthRepository {
 
 func (r *AuthRepository) Store(ctx context.Context, app *auth.Application) error {
 	query := `
-        INSERT INTO application (app_name, key_id, hashed_key)
-        VALUES ($1, $2, $3)
+        INSERT INTO application (
+            app_name, key_id, hashed_key, scopes, expires_at,
+            rate_limit_per_minute, rate_limit_burst, auth_mode, allowed_cidrs
+        )
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9::CIDR[])
     `
+	perMinute, burst := fromRateLimit(app.RateLimit)
 	_, err := r.db.ExecContext(ctx, query,
-		app.AppName, app.KeyId, app.HashedKey)
+		app.AppName, app.KeyId, app.HashedKey, pq.Array(fromScopes(app.Scopes)), nullTime(app.ExpiresAt),
+		perMinute, burst, app.AuthMode, pq.Array(fromCIDRs(app.AllowedCIDRs)))
 	return err
 }
 
 func (r *AuthRepository) FindKeyById(ctx context.Context, keyId string) (*auth.Application, error) {
 	query := `
-        SELECT app_name, key_id, hashed_key
+        SELECT` + applicationColumns + `
         FROM application 
         WHERE key_id = $1
     `
 
+	app, err := scanApplication(r.db.QueryRowContext(ctx, query, keyId))
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("api key", keyId)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return app, nil
+}
+
+func (r *AuthRepository) UpdateLastUsed(ctx context.Context, lastUsed map[string]time.Time) error {
+	query := `
+        UPDATE application AS a
+        SET last_used_at = GREATEST(a.last_used_at, u.used_at)
+        FROM unnest($1::TEXT[], $2::TIMESTAMPTZ[]) AS u(key_id, used_at)
+        WHERE a.key_id = u.key_id
+    `
+
+	keyIds := make([]string, 0, len(lastUsed))
+	usedAt := make([]string, 0, len(lastUsed))
+	for keyId, t := range lastUsed {
+		keyIds = append(keyIds, keyId)
+		usedAt = append(usedAt, t.UTC().Format(time.RFC3339Nano))
+	}
+
+	_, err := r.db.ExecContext(ctx, query, pq.Array(keyIds), pq.Array(usedAt))
+	return err
+}
+
+func (r *AuthRepository) List(ctx context.Context) ([]auth.Application, error) {
+	query := `
+        SELECT` + applicationColumns + `
+        FROM application
+        ORDER BY created_at DESC
+    `
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var apps []auth.Application
+	for rows.Next() {
+		app, err := scanApplication(rows)
+		if err != nil {
+			return nil, err
+		}
+		apps = append(apps, *app)
+	}
+
+	return apps, rows.Err()
+}
+
+func (r *AuthRepository) Revoke(ctx context.Context, keyId string) error {
+	query := `UPDATE application SET revoked_at = COALESCE(revoked_at, NOW()) WHERE key_id = $1`
+	result, err := r.db.ExecContext(ctx, query, keyId)
+	if err != nil {
+		return err
+	}
+
+	return requireRow(result, "api key", keyId)
+}
+
+func (r *AuthRepository) Rotate(ctx context.Context, keyId string, hashedKey []byte, previousValidUntil time.Time) error {
+	query := `
+        UPDATE application
+        SET previous_hashed_key = hashed_key,
+            previous_key_expires_at = $3,
+            hashed_key = $2
+        WHERE key_id = $1
+        AND revoked_at IS NULL
+    `
+	result, err := r.db.ExecContext(ctx, query, keyId, hashedKey, previousValidUntil)
+	if err != nil {
+		return err
+	}
+
+	return requireRow(result, "api key", keyId)
+}
+
+func (r *AuthRepository) UpdateRateLimit(ctx context.Context, keyId string, limit *auth.RateLimit) error {
+	query := `
+        UPDATE application
+        SET rate_limit_per_minute = $2,
+            rate_limit_burst = $3
+        WHERE key_id = $1
+    `
+	perMinute, burst := fromRateLimit(limit)
+	result, err := r.db.ExecContext(ctx, query, keyId, perMinute, burst)
+	if err != nil {
+		return err
+	}
+
+	return requireRow(result, "api key", keyId)
+}
+
+func (r *AuthRepository) UpdateAuthMode(ctx context.Context, keyId string, mode auth.AuthMode) error {
+	query := `UPDATE application SET auth_mode = $2 WHERE key_id = $1`
+	result, err := r.db.ExecContext(ctx, query, keyId, mode)
+	if err != nil {
+		return err
+	}
+
+	return requireRow(result, "api key", keyId)
+}
+
+func (r *AuthRepository) UpdateAllowedCIDRs(ctx context.Context, keyId string, cidrs []netip.Prefix) error {
+	query := `UPDATE application SET allowed_cidrs = $2::CIDR[] WHERE key_id = $1`
+	result, err := r.db.ExecContext(ctx, query, keyId, pq.Array(fromCIDRs(cidrs)))
+	if err != nil {
+		return err
+	}
+
+	return requireRow(result, "api key", keyId)
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanApplication(row rowScanner) (*auth.Application, error) {
 	app := &auth.Application{}
+	var scopes, allowedCIDRs []string
+	var lastUsedAt, expiresAt, revokedAt, previousKeyExpiresAt sql.NullTime
+	var rateLimitPerMinute, rateLimitBurst sql.NullInt32
 
-	err := r.db.QueryRowContext(ctx, query, keyId).Scan(
-		&app.KeyId, &app.AppName, &app.HashedKey,
+	err := row.Scan(
+		&app.AppName, &app.KeyId, &app.HashedKey, pq.Array(&scopes), &app.CreatedAt, &lastUsedAt,
+		&expiresAt, &revokedAt, &app.PreviousHashedKey, &previousKeyExpiresAt,
+		&rateLimitPerMinute, &rateLimitBurst, &app.AuthMode, pq.Array(&allowedCIDRs),
 	)
-
 	if err != nil {
 		return nil, err
 	}
 
+	app.Scopes = toScopes(scopes)
+	app.AllowedCIDRs, err = toCIDRs(allowedCIDRs)
+	if err != nil {
+		return nil, err
+	}
+	app.LastUsedAt = timePtr(lastUsedAt)
+	app.ExpiresAt = timePtr(expiresAt)
+	app.RevokedAt = timePtr(revokedAt)
+	app.PreviousKeyExpiresAt = timePtr(previousKeyExpiresAt)
+	if rateLimitPerMinute.Valid && rateLimitBurst.Valid {
+		app.RateLimit = &auth.RateLimit{
+			PerMinute: int(rateLimitPerMinute.Int32),
+			Burst:     int(rateLimitBurst.Int32),
+		}
+	}
 	return app, nil
 }
 
-func (r *AuthRepository) UpdateLastUsed(ctx context.Context, keyId string) error {
-	query := `UPDATE application SET last_used_at = NOW() WHERE key_id = $1`
-	_, err := r.db.ExecContext(ctx, query, keyId)
-	return err
+func requireRow(result sql.Result, resource, id string) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.NewNotFoundError(resource, id)
+	}
+	return nil
+}
+
+func timePtr(v sql.NullTime) *time.Time {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Time
+}
+
+func fromRateLimit(limit *auth.RateLimit) (sql.NullInt32, sql.NullInt32) {
+	if limit == nil {
+		return sql.NullInt32{}, sql.NullInt32{}
+	}
+	return sql.NullInt32{Valid: true, Int32: int32(limit.PerMinute)},
+		sql.NullInt32{Valid: true, Int32: int32(limit.Burst)}
+}
+
+func fromScopes(scopes []auth.Scope) []string {
+	values := make([]string, len(scopes))
+	for i, s := range scopes {
+		values[i] = string(s)
+	}
+	return values
+}
+
+func toScopes(values []string) []auth.Scope {
+	scopes := make([]auth.Scope, len(values))
+	for i, v := range values {
+		scopes[i] = auth.Scope(v)
+	}
+	return scopes
+}
+
+func fromCIDRs(cidrs []netip.Prefix) []string {
+	values := make([]string, len(cidrs))
+	for i, c := range cidrs {
+		values[i] = c.String()
+	}
+	return values
+}
+
+func toCIDRs(values []string) ([]netip.Prefix, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	cidrs := make([]netip.Prefix, len(values))
+	for i, v := range values {
+		cidr, err := netip.ParsePrefix(v)
+		if err != nil {
+			return nil, err
+		}
+		cidrs[i] = cidr
+	}
+	return cidrs, nil
 }
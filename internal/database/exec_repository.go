@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/ubcesports/echo-base/internal/database/sqlc"
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/interfaces/exec"
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+type ExecRepository struct {
+	db *sql.DB
+}
+
+func NewExecRepository(db *sql.DB) exec.ExecRepository {
+	return &ExecRepository{db: db}
+}
+
+func (r *ExecRepository) Create(ctx context.Context, e *models.Exec) (*models.Exec, error) {
+	queries := sqlc.New(r.db)
+	row, err := queries.CreateExec(ctx, sqlc.CreateExecParams{
+		Name:         e.Name,
+		Email:        e.Email,
+		Role:         string(e.Role),
+		PasswordHash: e.PasswordHash,
+	})
+	if isUniqueViolation(err) {
+		return nil, errors.NewValidationError("email", "is already used by another exec")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exec: %w", err)
+	}
+
+	return toExec(row), nil
+}
+
+func (r *ExecRepository) GetByID(ctx context.Context, id string) (*models.Exec, error) {
+	execID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.NewNotFoundError("exec", id)
+	}
+
+	queries := sqlc.New(r.db)
+	row, err := queries.GetExec(ctx, execID)
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("exec", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exec: %w", err)
+	}
+
+	return toExec(row), nil
+}
+
+func (r *ExecRepository) GetByEmail(ctx context.Context, email string) (*models.Exec, error) {
+	queries := sqlc.New(r.db)
+	row, err := queries.GetExecByEmail(ctx, email)
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("exec", email)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exec: %w", err)
+	}
+
+	return toExec(row), nil
+}
+
+func (r *ExecRepository) List(ctx context.Context) ([]models.Exec, error) {
+	queries := sqlc.New(r.db)
+	rows, err := queries.ListExecs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list execs: %w", err)
+	}
+
+	execs := make([]models.Exec, len(rows))
+	for i, row := range rows {
+		execs[i] = *toExec(row)
+	}
+	return execs, nil
+}
+
+func (r *ExecRepository) SetActive(ctx context.Context, id string, active bool) (*models.Exec, error) {
+	execID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.NewNotFoundError("exec", id)
+	}
+
+	queries := sqlc.New(r.db)
+	row, err := queries.SetExecActive(ctx, sqlc.SetExecActiveParams{ID: execID, Active: active})
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("exec", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update exec: %w", err)
+	}
+
+	return toExec(row), nil
+}
+
+func toExec(row sqlc.Exec) *models.Exec {
+	return &models.Exec{
+		ID:           row.ID.String(),
+		Name:         row.Name,
+		Email:        row.Email,
+		Role:         models.ExecRole(row.Role),
+		Active:       row.Active,
+		CreatedAt:    row.CreatedAt,
+		PasswordHash: row.PasswordHash,
+	}
+}
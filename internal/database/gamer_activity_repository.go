@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/ubcesports/echo-base/internal/database/sqlc"
+	"github.com/ubcesports/echo-base/internal/errors"
 	"github.com/ubcesports/echo-base/internal/interfaces/gamer"
 	"github.com/ubcesports/echo-base/internal/models"
 )
@@ -38,6 +39,25 @@ func (r *GamerActivityRepository) GetTodayActivitiesByStudent(ctx context.Contex
 	return toGamerActivitiesBasic(rows), nil
 }
 
+// GetBetween returns a member's sessions that overlap the window
+func (r *GamerActivityRepository) GetBetween(ctx context.Context, studentNumber string, windowStart, windowEnd time.Time) ([]models.GamerActivity, error) {
+	queries := sqlc.New(r.db)
+	rows, err := queries.GetStudentActivityBetween(ctx, sqlc.GetStudentActivityBetweenParams{
+		StudentNumber: studentNumber,
+		WindowEnd:     sql.NullTime{Time: windowEnd, Valid: true},
+		WindowStart:   sql.NullTime{Time: windowStart, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query activities: %w", err)
+	}
+
+	activities := make([]sqlc.GetGamerActivityRow, len(rows))
+	for i, row := range rows {
+		activities[i] = sqlc.GetGamerActivityRow(row)
+	}
+	return toGamerActivities(activities), nil
+}
+
 func (r *GamerActivityRepository) GetRecentActivities(ctx context.Context, page, limit int, search string) ([]models.GamerActivity, error) {
 	queries := sqlc.New(r.db)
 	offset := (page - 1) * limit
@@ -77,9 +97,13 @@ func (r *GamerActivityRepository) GetExecLeaderboard(ctx context.Context, window
 	leaderboard := make([]models.ExecLeaderboardEntry, len(rows))
 	for i, row := range rows {
 		leaderboard[i] = models.ExecLeaderboardEntry{
-			ExecName:     row.ExecName.String,
+			ExecName:     row.ExecName,
 			SignoutCount: int(row.SignoutCount),
 		}
+		if row.ExecID.Valid {
+			execID := row.ExecID.UUID.String()
+			leaderboard[i].ExecID = &execID
+		}
 	}
 
 	return leaderboard, nil
@@ -98,26 +122,61 @@ func (r *GamerActivityRepository) Create(ctx context.Context, activity *models.G
 		}
 	}
 
-	queries := sqlc.New(r.db)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Checking first lets the conflict name who is on the PC. Starts that
+	// race past the check are stopped by the unique indexes on open sessions.
+	queries := sqlc.New(tx)
+	open, err := clashingOpenSession(ctx, queries, activity)
+	if err != nil {
+		return nil, err
+	}
+	if open != nil {
+		return nil, openSessionConflict(activity, open)
+	}
+
 	row, err := queries.CreateGamerActivity(ctx, sqlc.CreateGamerActivityParams{
 		ID:            activityID,
 		StudentNumber: activity.StudentNumber,
 		PcNumber:      sql.NullInt32{Int32: int32(activity.PCNumber), Valid: true},
 		Game:          sql.NullString{String: activity.Game, Valid: true},
 		StartedAt:     sql.NullTime{Time: activity.StartedAt, Valid: true},
+		ExpiresAt:     nullTime(activity.ExpiresAt),
 	})
+	if isUniqueViolation(err) {
+		tx.Rollback()
+		open, err := clashingOpenSession(ctx, sqlc.New(r.db), activity)
+		if err != nil {
+			return nil, err
+		}
+		return nil, openSessionConflict(activity, open)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to create activity: %w", err)
 	}
 
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
 	return toGamerActivityFromCreate(row), nil
 }
 
-func (r *GamerActivityRepository) UpdateEndTime(ctx context.Context, studentNumber string, pcNumber int, endedAt time.Time, execName string) (*models.GamerActivity, error) {
+func (r *GamerActivityRepository) UpdateEndTime(ctx context.Context, studentNumber string, pcNumber int, endedAt time.Time, execID, execName string) (*models.GamerActivity, error) {
+	parsedExecID, err := uuid.Parse(execID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exec id: %w", err)
+	}
+
 	queries := sqlc.New(r.db)
 	row, err := queries.UpdateActivityEndTime(ctx, sqlc.UpdateActivityEndTimeParams{
 		EndedAt:       sql.NullTime{Time: endedAt, Valid: true},
 		ExecName:      sql.NullString{String: execName, Valid: true},
+		ExecID:        uuid.NullUUID{UUID: parsedExecID, Valid: true},
 		StudentNumber: studentNumber,
 		PcNumber:      sql.NullInt32{Int32: int32(pcNumber), Valid: true},
 	})
@@ -141,6 +200,90 @@ func (r *GamerActivityRepository) GetActiveSessions(ctx context.Context) ([]mode
 	return toGamerActivitiesFromActiveSessions(rows), nil
 }
 
+// FlagOverdue marks an active session as reported for overtime. It returns
+// false when the session has ended or was already flagged.
+func (r *GamerActivityRepository) FlagOverdue(ctx context.Context, id string, flaggedAt time.Time) (bool, error) {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid session id: %w", err)
+	}
+
+	queries := sqlc.New(r.db)
+	rows, err := queries.FlagOverdueSession(ctx, sqlc.FlagOverdueSessionParams{
+		OverdueFlaggedAt: sql.NullTime{Time: flaggedAt, Valid: true},
+		ID:               parsedID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to flag overdue session: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// EndOverdue ends an active session on behalf of the server. It returns nil
+// when the session has already ended.
+func (r *GamerActivityRepository) EndOverdue(ctx context.Context, id string, endedAt time.Time) (*models.GamerActivity, error) {
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session id: %w", err)
+	}
+
+	queries := sqlc.New(r.db)
+	row, err := queries.EndOverdueSession(ctx, sqlc.EndOverdueSessionParams{
+		EndedAt: sql.NullTime{Time: endedAt, Valid: true},
+		ID:      parsedID,
+	})
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to end overdue session: %w", err)
+	}
+
+	activity := toGamerActivityFromUpdate(sqlc.UpdateActivityEndTimeRow{
+		ID:            row.ID,
+		StudentNumber: row.StudentNumber,
+		PcNumber:      row.PcNumber,
+		Game:          row.Game,
+		StartedAt:     row.StartedAt,
+		EndedAt:       row.EndedAt,
+		ExecName:      row.ExecName,
+		ExecID:        row.ExecID,
+	})
+	activity.EndedBySystem = row.EndedBySystem
+	return activity, nil
+}
+
+// clashingOpenSession returns the open session on the PC, or of the student,
+// that a new session would clash with
+func clashingOpenSession(ctx context.Context, queries *sqlc.Queries, activity *models.GamerActivity) (*sqlc.GetOpenSessionConflictsRow, error) {
+	rows, err := queries.GetOpenSessionConflicts(ctx, sqlc.GetOpenSessionConflictsParams{
+		PcNumber:      sql.NullInt32{Int32: int32(activity.PCNumber), Valid: true},
+		StudentNumber: activity.StudentNumber,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check open sessions: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return &rows[0], nil
+}
+
+// openSessionConflict names who is on the PC, or where the student is
+// already signed in. The session may have ended by the time a lost race is
+// looked into, in which case open is nil.
+func openSessionConflict(activity *models.GamerActivity, open *sqlc.GetOpenSessionConflictsRow) error {
+	if open == nil {
+		return errors.NewConflictError(fmt.Sprintf("PC %d or student %s already has an open session", activity.PCNumber, activity.StudentNumber))
+	}
+
+	occupant := fmt.Sprintf("%s %s (%s)", open.FirstName, open.LastName, open.StudentNumber)
+	if open.StudentNumber == activity.StudentNumber {
+		return errors.NewConflictError(fmt.Sprintf("%s is already signed in on PC %d", occupant, open.PcNumber.Int32))
+	}
+	return errors.NewConflictError(fmt.Sprintf("PC %d is in use by %s", open.PcNumber.Int32, occupant))
+}
+
 /*
 sqlc model conversion helpers
 */
@@ -158,6 +301,9 @@ func toGamerActivityFromCreate(row sqlc.CreateGamerActivityRow) *models.GamerAct
 	if row.ExecName.Valid {
 		activity.ExecName = &row.ExecName.String
 	}
+	if row.ExpiresAt.Valid {
+		activity.ExpiresAt = &row.ExpiresAt.Time
+	}
 	return activity
 }
 
@@ -175,6 +321,10 @@ func toGamerActivityFromUpdate(row sqlc.UpdateActivityEndTimeRow) *models.GamerA
 	if row.ExecName.Valid {
 		activity.ExecName = &row.ExecName.String
 	}
+	if row.ExecID.Valid {
+		execID := row.ExecID.UUID.String()
+		activity.ExecID = &execID
+	}
 	return activity
 }
 
@@ -281,6 +431,12 @@ func toGamerActivitiesFromActiveSessions(rows []sqlc.GetActiveSessionsRow) []mod
 		if row.ExecName.Valid {
 			activities[i].ExecName = &row.ExecName.String
 		}
+		if row.ExpiresAt.Valid {
+			activities[i].ExpiresAt = &row.ExpiresAt.Time
+		}
+		if row.OverdueFlaggedAt.Valid {
+			activities[i].OverdueFlaggedAt = &row.OverdueFlaggedAt.Time
+		}
 	}
 	return activities
 }
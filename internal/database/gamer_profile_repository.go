@@ -2,10 +2,14 @@ package database
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
+	"encoding/hex"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/ubcesports/echo-base/internal/database/sqlc"
 	"github.com/ubcesports/echo-base/internal/errors"
 	"github.com/ubcesports/echo-base/internal/interfaces/gamer"
@@ -14,14 +18,54 @@ import (
 
 type GamerProfileRepository struct {
 	db *sql.DB
+	// tx is set on repositories handed out by WithTx
+	tx *sql.Tx
 }
 
 func NewGamerProfileRepository(db *sql.DB) gamer.GamerProfileRepository {
 	return &GamerProfileRepository{db: db}
 }
 
+// WithTx runs fn with a repository whose writes all happen in one
+// transaction, committed only if fn returns nil
+func (r *GamerProfileRepository) WithTx(ctx context.Context, fn func(repo gamer.GamerProfileRepository) error) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		return fn(&GamerProfileRepository{db: r.db, tx: tx})
+	})
+}
+
+func (r *GamerProfileRepository) queries() *sqlc.Queries {
+	if r.tx != nil {
+		return sqlc.New(r.tx)
+	}
+	return sqlc.New(r.db)
+}
+
+// inTx runs fn in a new transaction, or in the repository's transaction if
+// it already has one
+func (r *GamerProfileRepository) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	if r.tx != nil {
+		return fn(r.tx)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
 func (r *GamerProfileRepository) GetByStudentNumber(ctx context.Context, studentNumber string) (*models.GamerProfile, error) {
-	queries := sqlc.New(r.db)
+	queries := r.queries()
 	row, err := queries.GetGamerProfile(ctx, studentNumber)
 
 	if err == sql.ErrNoRows {
@@ -31,13 +75,109 @@ func (r *GamerProfileRepository) GetByStudentNumber(ctx context.Context, student
 		return nil, fmt.Errorf("failed to get profile: %w", err)
 	}
 
+	profile := toGamerProfile(row.GamerProfile)
+	profile.Banned = &row.ActiveBan
+	return profile, nil
+}
+
+// List returns one page of profiles matching filter along with the number of
+// profiles matching it across all pages
+// GetByEmail finds the profile holding email, ignoring case. Deleted
+// profiles are included because their email stays taken.
+func (r *GamerProfileRepository) GetByEmail(ctx context.Context, email string) (*models.GamerProfile, error) {
+	queries := r.queries()
+	row, err := queries.GetGamerProfileByEmail(ctx, email)
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("profile", email)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+
 	return toGamerProfile(row), nil
 }
 
+// GetByStudentNumberForUpdate is GetByStudentNumber that also locks the
+// profile until the transaction ends. Use it inside WithTx.
+func (r *GamerProfileRepository) GetByStudentNumberForUpdate(ctx context.Context, studentNumber string) (*models.GamerProfile, error) {
+	queries := r.queries()
+	row, err := queries.GetGamerProfileForUpdate(ctx, studentNumber)
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("student", studentNumber)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	profile := toGamerProfile(row.GamerProfile)
+	profile.Banned = &row.ActiveBan
+	return profile, nil
+}
+
+// GetByStudentNumberIncludingDeleted is GetByStudentNumber that also finds
+// soft deleted profiles. Check DeletedAt to tell them apart.
+func (r *GamerProfileRepository) GetByStudentNumberIncludingDeleted(ctx context.Context, studentNumber string) (*models.GamerProfile, error) {
+	queries := r.queries()
+	row, err := queries.GetGamerProfileIncludingDeleted(ctx, studentNumber)
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("student", studentNumber)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	profile := toGamerProfile(row.GamerProfile)
+	profile.Banned = &row.ActiveBan
+	return profile, nil
+}
+
+func (r *GamerProfileRepository) List(ctx context.Context, filter models.ProfileFilter) ([]models.GamerProfile, int, error) {
+	queries := r.queries()
+	params := toProfileFilterParams(filter)
+	params.Limit = int64(filter.Limit)
+	params.Offset = int64((filter.Page - 1) * filter.Limit)
+
+	rows, err := queries.ListGamerProfiles(ctx, params)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list profiles: %w", err)
+	}
+
+	// A page past the end has no rows to carry the count, so fetch it from
+	// the first page instead
+	if len(rows) == 0 && params.Offset > 0 {
+		params.Limit, params.Offset = 1, 0
+		first, err := queries.ListGamerProfiles(ctx, params)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to count profiles: %w", err)
+		}
+		if len(first) > 0 {
+			return []models.GamerProfile{}, int(first[0].TotalCount), nil
+		}
+		return []models.GamerProfile{}, 0, nil
+	}
+
+	total := 0
+	profiles := make([]models.GamerProfile, len(rows))
+	for i, row := range rows {
+		profiles[i] = *toGamerProfile(row.GamerProfile)
+		profiles[i].Banned = &rows[i].ActiveBan
+		total = int(row.TotalCount)
+	}
+	return profiles, total, nil
+}
+
 func (r *GamerProfileRepository) Upsert(ctx context.Context, profile *models.GamerProfile) (*models.GamerProfile, error) {
-	queries := sqlc.New(r.db)
+	queries := r.queries()
 
 	row, err := queries.UpsertGamerProfile(ctx, toUpsertParams(profile))
+	if err == sql.ErrNoRows {
+		return nil, errors.NewConflictError(fmt.Sprintf("student %s was deleted; restore them with POST /v1/api/gamer/%s/restore", profile.StudentNumber, profile.StudentNumber))
+	}
+	if isUniqueViolation(err) {
+		return nil, errors.NewValidationError("email", "is already used by another member")
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to upsert profile: %w", err)
 	}
@@ -45,9 +185,35 @@ func (r *GamerProfileRepository) Upsert(ctx context.Context, profile *models.Gam
 	return toGamerProfile(row), nil
 }
 
+func (r *GamerProfileRepository) Update(ctx context.Context, profile *models.GamerProfile) (*models.GamerProfile, error) {
+	queries := r.queries()
+
+	row, err := queries.UpdateGamerProfile(ctx, sqlc.UpdateGamerProfileParams{
+		StudentNumber:       profile.StudentNumber,
+		FirstName:           profile.FirstName,
+		LastName:            profile.LastName,
+		Email:               nullString(profile.Email),
+		DiscordHandle:       nullString(profile.DiscordHandle),
+		MarketingConsent:    profile.MarketingConsent,
+		NotificationConsent: profile.NotificationConsent,
+	})
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("student", profile.StudentNumber)
+	}
+	if isUniqueViolation(err) {
+		return nil, errors.NewValidationError("email", "is already used by another member")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	return toGamerProfile(row), nil
+}
+
+// Delete soft deletes a profile. It's hidden from reads until restored.
 func (r *GamerProfileRepository) Delete(ctx context.Context, studentNumber string) error {
-	queries := sqlc.New(r.db)
-	rows, err := queries.DeleteGamerProfile(ctx, studentNumber)
+	queries := r.queries()
+	rows, err := queries.SoftDeleteGamerProfile(ctx, studentNumber)
 
 	if err != nil {
 		return fmt.Errorf("failed to delete profile: %w", err)
@@ -60,8 +226,92 @@ func (r *GamerProfileRepository) Delete(ctx context.Context, studentNumber strin
 	return nil
 }
 
+func (r *GamerProfileRepository) Restore(ctx context.Context, studentNumber string) (*models.GamerProfile, error) {
+	queries := r.queries()
+	row, err := queries.RestoreGamerProfile(ctx, studentNumber)
+
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("deleted student", studentNumber)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore profile: %w", err)
+	}
+
+	return toGamerProfile(row), nil
+}
+
+// erasePseudonymAttempts bounds how many fresh pseudonyms Erase tries when
+// one is already taken
+const erasePseudonymAttempts = 5
+
+// Erase anonymizes a profile, whether or not it's deleted, along with its
+// ban reasons, notes and audit entries. It returns the pseudonym that replaces the
+// student number.
+func (r *GamerProfileRepository) Erase(ctx context.Context, studentNumber string) (string, error) {
+	for attempt := 1; ; attempt++ {
+		pseudonym, err := newPseudonym()
+		if err != nil {
+			return "", err
+		}
+
+		err = r.erase(ctx, studentNumber, pseudonym)
+		// A failed statement aborts the transaction, so a collision can only
+		// be retried when Erase owns it
+		if isUniqueViolation(err) && r.tx == nil && attempt < erasePseudonymAttempts {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return pseudonym, nil
+	}
+}
+
+func (r *GamerProfileRepository) erase(ctx context.Context, studentNumber, pseudonym string) error {
+	return r.inTx(ctx, func(tx *sql.Tx) error {
+		queries := sqlc.New(tx)
+		row, err := queries.EraseGamerProfile(ctx, sqlc.EraseGamerProfileParams{
+			Pseudonym:     pseudonym,
+			StudentNumber: studentNumber,
+		})
+		if err == sql.ErrNoRows {
+			return errors.NewNotFoundError("student", studentNumber)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to erase profile: %w", err)
+		}
+
+		if err := queries.EraseBanReasons(ctx, row.StudentNumber); err != nil {
+			return fmt.Errorf("failed to erase ban reasons: %w", err)
+		}
+
+		if err := queries.EraseMemberNotes(ctx, row.StudentNumber); err != nil {
+			return fmt.Errorf("failed to erase notes: %w", err)
+		}
+
+		err = queries.EraseAuditSubject(ctx, sqlc.EraseAuditSubjectParams{
+			Pseudonym:     row.StudentNumber,
+			StudentNumber: studentNumber,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to erase audit entries: %w", err)
+		}
+		return nil
+	})
+}
+
+// newPseudonym returns an E followed by 7 random hex digits. It fits the
+// student number column and can't clash with a real, all digit, number.
+func newPseudonym() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate pseudonym: %w", err)
+	}
+	return "E" + hex.EncodeToString(b)[:7], nil
+}
+
 func (r *GamerProfileRepository) CheckMembershipValidity(ctx context.Context, studentNumber string) (tier int, expiryDate *time.Time, err error) {
-	queries := sqlc.New(r.db)
+	queries := r.queries()
 	result, err := queries.CheckMembershipValidity(ctx, studentNumber)
 
 	if err == sql.ErrNoRows {
@@ -74,6 +324,273 @@ func (r *GamerProfileRepository) CheckMembershipValidity(ctx context.Context, st
 	return int(result.MembershipTier), &result.MembershipExpiryDate.Time, nil
 }
 
+// AddMembership records a membership. If it has already started it becomes
+// the current membership, so the tier and expiry on the profile are updated
+// in the same transaction.
+func (r *GamerProfileRepository) AddMembership(ctx context.Context, membership *models.Membership) (*models.Membership, error) {
+	var created *models.Membership
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		queries := sqlc.New(tx)
+		row, err := queries.CreateMembership(ctx, sqlc.CreateMembershipParams{
+			StudentNumber:    membership.StudentNumber,
+			MembershipTier:   int32(membership.MembershipTier),
+			Source:           string(membership.Source),
+			PurchasedAt:      membership.PurchasedAt,
+			StartsAt:         membership.StartsAt,
+			ExpiresAt:        nullTime(membership.ExpiresAt),
+			RecordedByApp:    nullString(membership.RecordedByApp),
+			RecordedByExecID: nullUUID(membership.RecordedByExecID),
+			ExternalRef:      nullString(membership.ExternalRef),
+		})
+		if isUniqueViolation(err) && membership.ExternalRef != nil {
+			return errors.NewConflictError(fmt.Sprintf("purchase %s is already recorded", *membership.ExternalRef))
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create membership: %w", err)
+		}
+
+		if !row.StartsAt.After(time.Now()) {
+			err = queries.SetProfileMembership(ctx, sqlc.SetProfileMembershipParams{
+				StudentNumber:        row.StudentNumber,
+				MembershipTier:       row.MembershipTier,
+				MembershipExpiryDate: row.ExpiresAt,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to update current membership: %w", err)
+			}
+		}
+
+		created = toMembership(row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// GetMembershipByExternalRef returns the membership recorded for a purchase,
+// or nil if there isn't one
+func (r *GamerProfileRepository) GetMembershipByExternalRef(ctx context.Context, externalRef string) (*models.Membership, error) {
+	queries := r.queries()
+	row, err := queries.GetMembershipByExternalRef(ctx, nullStringValue(externalRef))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get membership: %w", err)
+	}
+
+	return toMembership(row), nil
+}
+
+func (r *GamerProfileRepository) ListMemberships(ctx context.Context, studentNumber string) ([]models.Membership, error) {
+	queries := r.queries()
+	rows, err := queries.ListMemberships(ctx, studentNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list memberships: %w", err)
+	}
+
+	memberships := make([]models.Membership, len(rows))
+	for i, row := range rows {
+		memberships[i] = *toMembership(row)
+	}
+	return memberships, nil
+}
+
+func (r *GamerProfileRepository) CreateBan(ctx context.Context, ban *models.Ban) (*models.Ban, error) {
+	queries := r.queries()
+	row, err := queries.CreateBan(ctx, sqlc.CreateBanParams{
+		StudentNumber:  ban.StudentNumber,
+		Reason:         ban.Reason,
+		StartsAt:       ban.StartsAt,
+		EndsAt:         nullTime(ban.EndsAt),
+		IssuedByApp:    nullString(ban.IssuedByApp),
+		IssuedByExecID: nullUUID(ban.IssuedByExecID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create ban: %w", err)
+	}
+
+	return toBan(row), nil
+}
+
+func (r *GamerProfileRepository) GetBan(ctx context.Context, banID string) (*models.Ban, error) {
+	id, err := uuid.Parse(banID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("ban", banID)
+	}
+
+	queries := r.queries()
+	row, err := queries.GetBan(ctx, id)
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("ban", banID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ban: %w", err)
+	}
+
+	return toBan(row), nil
+}
+
+// GetActiveBan returns the ban currently in force for a student, or nil if
+// there isn't one
+func (r *GamerProfileRepository) GetActiveBan(ctx context.Context, studentNumber string) (*models.Ban, error) {
+	queries := r.queries()
+	row, err := queries.GetActiveBan(ctx, studentNumber)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active ban: %w", err)
+	}
+
+	return toBan(row), nil
+}
+
+func (r *GamerProfileRepository) ListBans(ctx context.Context, studentNumber string) ([]models.Ban, error) {
+	queries := r.queries()
+	rows, err := queries.ListBans(ctx, studentNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bans: %w", err)
+	}
+
+	bans := make([]models.Ban, len(rows))
+	for i, row := range rows {
+		bans[i] = *toBan(row)
+	}
+	return bans, nil
+}
+
+// LiftBan ends a ban early. Bans that are already lifted are not found.
+func (r *GamerProfileRepository) LiftBan(ctx context.Context, ban *models.Ban) (*models.Ban, error) {
+	id, err := uuid.Parse(ban.ID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("ban", ban.ID)
+	}
+
+	queries := r.queries()
+	row, err := queries.LiftBan(ctx, sqlc.LiftBanParams{
+		ID:             id,
+		LiftedByApp:    nullString(ban.LiftedByApp),
+		LiftedByExecID: nullUUID(ban.LiftedByExecID),
+		LiftReason:     nullString(ban.LiftReason),
+	})
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("ban", ban.ID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lift ban: %w", err)
+	}
+
+	return toBan(row), nil
+}
+
+// CreateNote adds a note to a member. The notes on the profile are updated
+// in the same transaction in case the note is pinned.
+func (r *GamerProfileRepository) CreateNote(ctx context.Context, note *models.MemberNote) (*models.MemberNote, error) {
+	var created *models.MemberNote
+	err := r.inTx(ctx, func(tx *sql.Tx) error {
+		queries := sqlc.New(tx)
+		row, err := queries.CreateMemberNote(ctx, sqlc.CreateMemberNoteParams{
+			StudentNumber: note.StudentNumber,
+			Body:          note.Body,
+			Category:      string(note.Category),
+			Pinned:        note.Pinned,
+			AuthorApp:     nullString(note.AuthorApp),
+			AuthorExecID:  nullUUID(note.AuthorExecID),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create note: %w", err)
+		}
+
+		if err := queries.RefreshProfileNotes(ctx, row.StudentNumber); err != nil {
+			return fmt.Errorf("failed to update profile notes: %w", err)
+		}
+
+		created = toMemberNote(row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (r *GamerProfileRepository) GetNote(ctx context.Context, noteID string) (*models.MemberNote, error) {
+	id, err := uuid.Parse(noteID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("note", noteID)
+	}
+
+	queries := r.queries()
+	row, err := queries.GetMemberNote(ctx, id)
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("note", noteID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get note: %w", err)
+	}
+
+	return toMemberNote(row), nil
+}
+
+func (r *GamerProfileRepository) ListNotes(ctx context.Context, studentNumber string, includeArchived bool) ([]models.MemberNote, error) {
+	queries := r.queries()
+	rows, err := queries.ListMemberNotes(ctx, sqlc.ListMemberNotesParams{
+		StudentNumber:   studentNumber,
+		IncludeArchived: includeArchived,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notes: %w", err)
+	}
+
+	notes := make([]models.MemberNote, len(rows))
+	for i, row := range rows {
+		notes[i] = *toMemberNote(row)
+	}
+	return notes, nil
+}
+
+// ArchiveNote hides a note from the timeline. Notes that are already
+// archived are not found.
+func (r *GamerProfileRepository) ArchiveNote(ctx context.Context, note *models.MemberNote) (*models.MemberNote, error) {
+	id, err := uuid.Parse(note.ID)
+	if err != nil {
+		return nil, errors.NewNotFoundError("note", note.ID)
+	}
+
+	var archived *models.MemberNote
+	err = r.inTx(ctx, func(tx *sql.Tx) error {
+		queries := sqlc.New(tx)
+		row, err := queries.ArchiveMemberNote(ctx, sqlc.ArchiveMemberNoteParams{
+			ID:               id,
+			ArchivedByApp:    nullString(note.ArchivedByApp),
+			ArchivedByExecID: nullUUID(note.ArchivedByExecID),
+		})
+		if err == sql.ErrNoRows {
+			return errors.NewNotFoundError("note", note.ID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to archive note: %w", err)
+		}
+
+		if err := queries.RefreshProfileNotes(ctx, row.StudentNumber); err != nil {
+			return fmt.Errorf("failed to update profile notes: %w", err)
+		}
+
+		archived = toMemberNote(row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return archived, nil
+}
+
 /*
 sqlc model conversion helpers
 */
@@ -84,18 +601,53 @@ func toUpsertParams(p *models.GamerProfile) sqlc.UpsertGamerProfileParams {
 		StudentNumber:        p.StudentNumber,
 		MembershipTier:       int32(p.MembershipTier),
 		Banned:               nullBool(p.Banned),
-		Notes:                nullString(p.Notes),
 		CreatedAt:            sql.NullTime{Valid: true, Time: p.CreatedAt},
 		MembershipExpiryDate: nullTime(p.MembershipExpiryDate),
+		Email:                nullString(p.Email),
+		DiscordHandle:        nullString(p.DiscordHandle),
+		MarketingConsent:     p.MarketingConsent,
+		NotificationConsent:  p.NotificationConsent,
+	}
+}
+
+// toProfileFilterParams converts the filter shared by the list and count
+// queries
+func toProfileFilterParams(f models.ProfileFilter) sqlc.ListGamerProfilesParams {
+	params := sqlc.ListGamerProfilesParams{
+		Banned:        nullBool(f.Banned),
+		ExpiresBefore: nullTime(f.ExpiresBefore),
+		ExpiresAfter:  nullTime(f.ExpiresAfter),
+		CreatedFrom:   nullTime(f.CreatedFrom),
+		CreatedTo:     nullTime(f.CreatedTo),
+		Search:        nullSearchPattern(f.Search),
 	}
+	if f.MembershipTier != nil {
+		params.MembershipTier = sql.NullInt32{Valid: true, Int32: int32(*f.MembershipTier)}
+	}
+	return params
+}
+
+// likeEscaper escapes the LIKE wildcards and the escape character itself so
+// search text is matched literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// nullSearchPattern turns search text into an ILIKE pattern matching it
+// anywhere in a column, or NULL when there is nothing to search for
+func nullSearchPattern(search string) sql.NullString {
+	if search == "" {
+		return sql.NullString{}
+	}
+	return sql.NullString{Valid: true, String: "%" + likeEscaper.Replace(search) + "%"}
 }
 
 func toGamerProfile(row sqlc.GamerProfile) *models.GamerProfile {
 	profile := &models.GamerProfile{
-		StudentNumber:  row.StudentNumber,
-		FirstName:      row.FirstName,
-		LastName:       row.LastName,
-		MembershipTier: int(row.MembershipTier),
+		StudentNumber:       row.StudentNumber,
+		FirstName:           row.FirstName,
+		LastName:            row.LastName,
+		MembershipTier:      int(row.MembershipTier),
+		MarketingConsent:    row.MarketingConsent,
+		NotificationConsent: row.NotificationConsent,
 	}
 	if row.ID.Valid {
 		profile.ID = row.ID.UUID.String()
@@ -112,10 +664,111 @@ func toGamerProfile(row sqlc.GamerProfile) *models.GamerProfile {
 	if row.MembershipExpiryDate.Valid {
 		profile.MembershipExpiryDate = &row.MembershipExpiryDate.Time
 	}
+	if row.DeletedAt.Valid {
+		profile.DeletedAt = &row.DeletedAt.Time
+	}
+	if row.Email.Valid {
+		profile.Email = &row.Email.String
+	}
+	if row.DiscordHandle.Valid {
+		profile.DiscordHandle = &row.DiscordHandle.String
+	}
 
 	return profile
 }
 
+func toMembership(row sqlc.Membership) *models.Membership {
+	membership := &models.Membership{
+		ID:             row.ID.String(),
+		StudentNumber:  row.StudentNumber,
+		MembershipTier: int(row.MembershipTier),
+		Source:         models.MembershipSource(row.Source),
+		PurchasedAt:    row.PurchasedAt,
+		StartsAt:       row.StartsAt,
+		CreatedAt:      row.CreatedAt,
+	}
+	if row.ExpiresAt.Valid {
+		membership.ExpiresAt = &row.ExpiresAt.Time
+	}
+	if row.RecordedByApp.Valid {
+		membership.RecordedByApp = &row.RecordedByApp.String
+	}
+	if row.RecordedByExecID.Valid {
+		execID := row.RecordedByExecID.UUID.String()
+		membership.RecordedByExecID = &execID
+	}
+	if row.ExternalRef.Valid {
+		membership.ExternalRef = &row.ExternalRef.String
+	}
+
+	return membership
+}
+
+func toBan(row sqlc.Ban) *models.Ban {
+	ban := &models.Ban{
+		ID:            row.ID.String(),
+		StudentNumber: row.StudentNumber,
+		Reason:        row.Reason,
+		StartsAt:      row.StartsAt,
+		CreatedAt:     row.CreatedAt,
+	}
+	if row.EndsAt.Valid {
+		ban.EndsAt = &row.EndsAt.Time
+	}
+	if row.IssuedByApp.Valid {
+		ban.IssuedByApp = &row.IssuedByApp.String
+	}
+	if row.IssuedByExecID.Valid {
+		execID := row.IssuedByExecID.UUID.String()
+		ban.IssuedByExecID = &execID
+	}
+	if row.LiftedAt.Valid {
+		ban.LiftedAt = &row.LiftedAt.Time
+	}
+	if row.LiftedByApp.Valid {
+		ban.LiftedByApp = &row.LiftedByApp.String
+	}
+	if row.LiftedByExecID.Valid {
+		execID := row.LiftedByExecID.UUID.String()
+		ban.LiftedByExecID = &execID
+	}
+	if row.LiftReason.Valid {
+		ban.LiftReason = &row.LiftReason.String
+	}
+
+	return ban
+}
+
+func toMemberNote(row sqlc.MemberNote) *models.MemberNote {
+	note := &models.MemberNote{
+		ID:            row.ID.String(),
+		StudentNumber: row.StudentNumber,
+		Body:          row.Body,
+		Category:      models.NoteCategory(row.Category),
+		Pinned:        row.Pinned,
+		CreatedAt:     row.CreatedAt,
+	}
+	if row.AuthorApp.Valid {
+		note.AuthorApp = &row.AuthorApp.String
+	}
+	if row.AuthorExecID.Valid {
+		execID := row.AuthorExecID.UUID.String()
+		note.AuthorExecID = &execID
+	}
+	if row.ArchivedAt.Valid {
+		note.ArchivedAt = &row.ArchivedAt.Time
+	}
+	if row.ArchivedByApp.Valid {
+		note.ArchivedByApp = &row.ArchivedByApp.String
+	}
+	if row.ArchivedByExecID.Valid {
+		execID := row.ArchivedByExecID.UUID.String()
+		note.ArchivedByExecID = &execID
+	}
+
+	return note
+}
+
 func nullBool(v *bool) sql.NullBool {
 	if v == nil {
 		return sql.NullBool{}
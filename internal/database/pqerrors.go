@@ -0,0 +1,31 @@
+package database
+
+import (
+	goerrors "errors"
+
+	"github.com/lib/pq"
+)
+
+// Postgres error codes the repositories turn into domain errors
+const (
+	pqForeignKeyViolation = "23503"
+	pqUniqueViolation     = "23505"
+	pqExclusionViolation  = "23P01"
+)
+
+func isUniqueViolation(err error) bool {
+	return hasPQCode(err, pqUniqueViolation)
+}
+
+func isForeignKeyViolation(err error) bool {
+	return hasPQCode(err, pqForeignKeyViolation)
+}
+
+func isExclusionViolation(err error) bool {
+	return hasPQCode(err, pqExclusionViolation)
+}
+
+func hasPQCode(err error, code pq.ErrorCode) bool {
+	var pqErr *pq.Error
+	return goerrors.As(err, &pqErr) && pqErr.Code == code
+}
@@ -0,0 +1,50 @@
+-- name: CreateAuditLog :exec
+INSERT INTO audit_log (actor_app, actor_exec_id, method, route, action, entity_type, entity_id, before, after)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+
+-- name: ListAuditLogs :many
+SELECT *
+FROM audit_log
+WHERE (sqlc.narg('entity_type')::TEXT IS NULL OR entity_type = sqlc.narg('entity_type'))
+  AND (sqlc.narg('entity_id')::TEXT IS NULL OR entity_id = sqlc.narg('entity_id'))
+  AND (sqlc.narg('actor_app')::TEXT IS NULL OR actor_app = sqlc.narg('actor_app'))
+  AND (sqlc.narg('actor_exec_id')::UUID IS NULL OR actor_exec_id = sqlc.narg('actor_exec_id'))
+  AND (sqlc.narg('created_from')::TIMESTAMPTZ IS NULL OR created_at >= sqlc.narg('created_from'))
+  AND (sqlc.narg('created_to')::TIMESTAMPTZ IS NULL OR created_at < sqlc.narg('created_to'))
+ORDER BY created_at DESC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListAuditLogsForStudent :many
+-- Every entry about a member, matched the same way as EraseAuditSubject,
+-- oldest first
+SELECT *
+FROM audit_log
+WHERE (entity_type = 'profile' AND entity_id = sqlc.arg('student_number')::TEXT)
+   OR before->>'student_number' = sqlc.arg('student_number')::TEXT
+   OR after->>'student_number' = sqlc.arg('student_number')::TEXT
+ORDER BY created_at ASC;
+
+-- name: EraseAuditSubject :exec
+-- Points an erased member's audit entries at their pseudonym. Profile
+-- entries hold personal fields, so their states are dropped. Ban reasons and
+-- note bodies are blanked the same way EraseBanReasons and EraseMemberNotes
+-- blank the rows themselves.
+UPDATE audit_log
+SET entity_id = CASE WHEN entity_type = 'profile' THEN sqlc.arg('pseudonym')::TEXT ELSE entity_id END,
+    before = CASE
+        WHEN entity_type = 'profile' THEN 'null'::JSONB
+        WHEN before->>'student_number' IS NULL THEN before
+        WHEN entity_type = 'ban' THEN (jsonb_set(before, '{student_number}', to_jsonb(sqlc.arg('pseudonym')::TEXT)) || '{"reason": "Erased"}'::JSONB) - 'lift_reason'
+        WHEN entity_type = 'note' THEN jsonb_set(before, '{student_number}', to_jsonb(sqlc.arg('pseudonym')::TEXT)) || '{"body": "Erased"}'::JSONB
+        ELSE jsonb_set(before, '{student_number}', to_jsonb(sqlc.arg('pseudonym')::TEXT))
+    END,
+    after = CASE
+        WHEN entity_type = 'profile' THEN 'null'::JSONB
+        WHEN after->>'student_number' IS NULL THEN after
+        WHEN entity_type = 'ban' THEN (jsonb_set(after, '{student_number}', to_jsonb(sqlc.arg('pseudonym')::TEXT)) || '{"reason": "Erased"}'::JSONB) - 'lift_reason'
+        WHEN entity_type = 'note' THEN jsonb_set(after, '{student_number}', to_jsonb(sqlc.arg('pseudonym')::TEXT)) || '{"body": "Erased"}'::JSONB
+        ELSE jsonb_set(after, '{student_number}', to_jsonb(sqlc.arg('pseudonym')::TEXT))
+    END
+WHERE (entity_type = 'profile' AND entity_id = sqlc.arg('student_number')::TEXT)
+   OR before->>'student_number' = sqlc.arg('student_number')::TEXT
+   OR after->>'student_number' = sqlc.arg('student_number')::TEXT;
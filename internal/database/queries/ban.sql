@@ -0,0 +1,43 @@
+-- name: CreateBan :one
+INSERT INTO ban (student_number, reason, starts_at, ends_at, issued_by_app, issued_by_exec_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetBan :one
+SELECT *
+FROM ban
+WHERE id = $1;
+
+-- name: GetActiveBan :one
+-- Permanent bans are returned ahead of suspensions, then the suspension
+-- that ends last.
+SELECT *
+FROM ban
+WHERE student_number = $1
+  AND lifted_at IS NULL
+  AND starts_at <= NOW()
+  AND (ends_at IS NULL OR ends_at > NOW())
+ORDER BY ends_at DESC NULLS FIRST
+LIMIT 1;
+
+-- name: ListBans :many
+SELECT *
+FROM ban
+WHERE student_number = $1
+ORDER BY starts_at DESC, created_at DESC;
+
+-- name: LiftBan :one
+UPDATE ban
+SET lifted_at = NOW(),
+    lifted_by_app = $2,
+    lifted_by_exec_id = $3,
+    lift_reason = $4
+WHERE id = $1
+  AND lifted_at IS NULL
+RETURNING *;
+
+-- name: EraseBanReasons :exec
+UPDATE ban
+SET reason = 'Erased',
+    lift_reason = NULL
+WHERE student_number = $1;
@@ -0,0 +1,25 @@
+-- name: CreateExec :one
+INSERT INTO exec (name, email, role, password_hash)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetExec :one
+SELECT *
+FROM exec
+WHERE id = $1;
+
+-- name: GetExecByEmail :one
+SELECT *
+FROM exec
+WHERE LOWER(email) = LOWER(sqlc.arg('email'));
+
+-- name: ListExecs :many
+SELECT *
+FROM exec
+ORDER BY name;
+
+-- name: SetExecActive :one
+UPDATE exec
+SET active = $2
+WHERE id = $1
+RETURNING *;
@@ -12,6 +12,15 @@ WHERE ga.student_number = $1
 AND gp.membership_tier = 1
 AND DATE(ga.started_at AT TIME ZONE 'America/Los_Angeles') = DATE(NOW() AT TIME ZONE 'America/Los_Angeles');
 
+-- name: GetStudentActivityBetween :many
+-- Sessions that overlap the window, including ones still running.
+SELECT id, student_number, pc_number, game, started_at, ended_at, exec_name
+FROM gamer_activity
+WHERE student_number = sqlc.arg(student_number)
+AND started_at < sqlc.arg(window_end)
+AND (ended_at IS NULL OR ended_at > sqlc.arg(window_start))
+ORDER BY started_at;
+
 -- name: GetRecentActivities :many
 SELECT ga.id, ga.student_number, ga.pc_number, ga.game, ga.started_at, ga.ended_at, ga.exec_name,
        gp.first_name, gp.last_name
@@ -34,31 +43,62 @@ ORDER BY ga.started_at DESC NULLS LAST
 LIMIT $1 OFFSET $2;
 
 -- name: CreateGamerActivity :one
-INSERT INTO gamer_activity (id, student_number, pc_number, game, started_at)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, student_number, pc_number, game, started_at, ended_at, exec_name;
+INSERT INTO gamer_activity (id, student_number, pc_number, game, started_at, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, student_number, pc_number, game, started_at, ended_at, exec_name, expires_at;
 
 -- name: UpdateActivityEndTime :one
 UPDATE gamer_activity
-SET ended_at = $1, exec_name = $2
-WHERE student_number = $3
-AND pc_number = $4
+SET ended_at = $1, exec_name = $2, exec_id = $3
+WHERE student_number = $4
+AND pc_number = $5
 AND ended_at IS NULL
-RETURNING id, student_number, pc_number, game, started_at, ended_at, exec_name;
+RETURNING id, student_number, pc_number, game, started_at, ended_at, exec_name, exec_id;
+
+-- name: GetOpenSessionConflicts :many
+-- Open sessions on the PC or for the student, which a new session would clash
+-- with.
+SELECT ga.id, ga.student_number, ga.pc_number, ga.game, ga.started_at, ga.ended_at, ga.exec_name,
+       gp.first_name, gp.last_name
+FROM gamer_activity ga
+JOIN gamer_profile gp ON ga.student_number = gp.student_number
+WHERE ga.ended_at IS NULL
+AND (ga.pc_number = sqlc.arg(pc_number) OR ga.student_number = sqlc.arg(student_number))
+ORDER BY ga.started_at;
 
 -- name: GetActiveSessions :many
 SELECT ga.id, ga.student_number, ga.pc_number, ga.game, ga.started_at, ga.ended_at, ga.exec_name,
-       gp.first_name, gp.last_name, gp.membership_tier
+       ga.expires_at, ga.overdue_flagged_at, gp.first_name, gp.last_name, gp.membership_tier
 FROM gamer_activity ga
 JOIN gamer_profile gp ON ga.student_number = gp.student_number
 WHERE ga.ended_at IS NULL;
 
+-- name: FlagOverdueSession :execrows
+UPDATE gamer_activity
+SET overdue_flagged_at = $1
+WHERE id = $2
+AND ended_at IS NULL
+AND overdue_flagged_at IS NULL;
+
+-- name: EndOverdueSession :one
+-- Overdue sessions ended by the server have no exec.
+UPDATE gamer_activity
+SET ended_at = $1, ended_by_system = TRUE
+WHERE id = $2
+AND ended_at IS NULL
+RETURNING id, student_number, pc_number, game, started_at, ended_at, exec_name, exec_id, ended_by_system;
+
 -- name: GetExecLeaderboard :many
-SELECT exec_name, COUNT(*)::BIGINT AS signout_count
-FROM gamer_activity
-WHERE ended_at IS NOT NULL
-AND exec_name IS NOT NULL
-AND ended_at >= $1
-AND ended_at < $2
-GROUP BY exec_name
+-- Sessions signed out by an exec account count towards that account under
+-- its current name. Older sessions only have the free-text exec_name.
+-- Sessions the server ended don't count towards anyone.
+SELECT ga.exec_id, COALESCE(e.name, ga.exec_name)::TEXT AS exec_name, COUNT(*)::BIGINT AS signout_count
+FROM gamer_activity ga
+LEFT JOIN exec e ON e.id = ga.exec_id
+WHERE ga.ended_at IS NOT NULL
+AND NOT ga.ended_by_system
+AND (ga.exec_id IS NOT NULL OR ga.exec_name IS NOT NULL)
+AND ga.ended_at >= $1
+AND ga.ended_at < $2
+GROUP BY ga.exec_id, COALESCE(e.name, ga.exec_name)
 ORDER BY signout_count DESC, exec_name ASC;
@@ -1,26 +1,182 @@
 -- name: GetGamerProfile :one
+SELECT sqlc.embed(gamer_profile), EXISTS (
+    SELECT 1
+    FROM ban
+    WHERE ban.student_number = gamer_profile.student_number
+      AND ban.lifted_at IS NULL
+      AND ban.starts_at <= NOW()
+      AND (ban.ends_at IS NULL OR ban.ends_at > NOW())
+) AS active_ban
+FROM gamer_profile
+WHERE student_number = $1
+  AND deleted_at IS NULL;
+
+-- name: GetGamerProfileByEmail :one
+-- Deleted profiles are included since they still hold their email.
 SELECT *
 FROM gamer_profile
+WHERE LOWER(email) = LOWER(sqlc.arg('email'));
+
+-- name: GetGamerProfileForUpdate :one
+-- Locks the profile row until the end of the transaction so concurrent
+-- partial updates apply one after another.
+SELECT sqlc.embed(gamer_profile), EXISTS (
+    SELECT 1
+    FROM ban
+    WHERE ban.student_number = gamer_profile.student_number
+      AND ban.lifted_at IS NULL
+      AND ban.starts_at <= NOW()
+      AND (ban.ends_at IS NULL OR ban.ends_at > NOW())
+) AS active_ban
+FROM gamer_profile
+WHERE student_number = $1
+  AND deleted_at IS NULL
+FOR UPDATE OF gamer_profile;
+
+-- name: GetGamerProfileIncludingDeleted :one
+SELECT sqlc.embed(gamer_profile), EXISTS (
+    SELECT 1
+    FROM ban
+    WHERE ban.student_number = gamer_profile.student_number
+      AND ban.lifted_at IS NULL
+      AND ban.starts_at <= NOW()
+      AND (ban.ends_at IS NULL OR ban.ends_at > NOW())
+) AS active_ban
+FROM gamer_profile
 WHERE student_number = $1;
 
 -- name: UpsertGamerProfile :one
-INSERT INTO gamer_profile (first_name, last_name, student_number, membership_tier, banned, notes, created_at, membership_expiry_date)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+-- Deleted profiles are left alone. They are brought back by RestoreGamerProfile.
+INSERT INTO gamer_profile (first_name, last_name, student_number, membership_tier, banned, created_at, membership_expiry_date, email, discord_handle, marketing_consent, notification_consent)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 ON CONFLICT (student_number)
 DO UPDATE SET
     first_name = EXCLUDED.first_name,
     last_name = EXCLUDED.last_name,
-    membership_tier = EXCLUDED.membership_tier,
-    banned = EXCLUDED.banned,
-    notes = EXCLUDED.notes,
-    created_at = EXCLUDED.created_at,
-    membership_expiry_date = EXCLUDED.membership_expiry_date
+    email = EXCLUDED.email,
+    discord_handle = EXCLUDED.discord_handle,
+    marketing_consent = EXCLUDED.marketing_consent,
+    notification_consent = EXCLUDED.notification_consent
+WHERE gamer_profile.deleted_at IS NULL
+RETURNING *;
+
+-- name: SetProfileMembership :exec
+UPDATE gamer_profile
+SET membership_tier = $2,
+    membership_expiry_date = $3
+WHERE student_number = $1;
+
+-- name: RefreshProfileNotes :exec
+-- notes is a copy of the latest pinned note, kept for clients from before
+-- notes were a timeline
+UPDATE gamer_profile
+SET notes = (
+    SELECT body
+    FROM member_note
+    WHERE member_note.student_number = gamer_profile.student_number
+      AND pinned
+      AND archived_at IS NULL
+    ORDER BY created_at DESC
+    LIMIT 1
+)
+WHERE student_number = $1;
+
+-- name: UpdateGamerProfile :one
+UPDATE gamer_profile
+SET first_name = $2,
+    last_name = $3,
+    email = $4,
+    discord_handle = $5,
+    marketing_consent = $6,
+    notification_consent = $7
+WHERE student_number = $1
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: SoftDeleteGamerProfile :execrows
+UPDATE gamer_profile
+SET deleted_at = NOW()
+WHERE student_number = $1
+  AND deleted_at IS NULL;
+
+-- name: RestoreGamerProfile :one
+UPDATE gamer_profile
+SET deleted_at = NULL
+WHERE student_number = $1
+  AND deleted_at IS NOT NULL
+  AND erased_at IS NULL
 RETURNING *;
 
--- name: DeleteGamerProfile :execrows
-DELETE FROM gamer_profile WHERE student_number = $1;
+-- name: EraseGamerProfile :one
+-- Replaces the student number with a pseudonym that can't be looked up
+-- and blanks the personal fields. Sessions, memberships and bans follow the
+-- new student number through ON UPDATE CASCADE. A pseudonym that is already
+-- taken fails the primary key, so the caller should retry with another.
+UPDATE gamer_profile
+SET student_number = sqlc.arg('pseudonym'),
+    first_name = 'Erased',
+    last_name = 'Member',
+    notes = NULL,
+    email = NULL,
+    discord_handle = NULL,
+    marketing_consent = FALSE,
+    notification_consent = FALSE,
+    deleted_at = COALESCE(deleted_at, NOW()),
+    erased_at = NOW()
+WHERE student_number = sqlc.arg('student_number')
+  AND erased_at IS NULL
+RETURNING *;
 
 -- name: CheckMembershipValidity :one
-SELECT membership_tier, membership_expiry_date
+-- The current membership is the latest one to have started. Members without
+-- one are on tier 0.
+SELECT COALESCE(m.membership_tier, 0)::INTEGER AS membership_tier, m.expires_at AS membership_expiry_date
+FROM gamer_profile p
+LEFT JOIN LATERAL (
+    SELECT membership_tier, expires_at
+    FROM membership
+    WHERE membership.student_number = p.student_number
+      AND starts_at <= NOW()
+    ORDER BY starts_at DESC, created_at DESC
+    LIMIT 1
+) m ON TRUE
+WHERE p.student_number = $1
+  AND p.deleted_at IS NULL;
+
+-- name: ListGamerProfiles :many
+-- search is a LIKE pattern whose wildcards have already been escaped with \.
+-- total_count counts every matching profile, not just the page.
+SELECT sqlc.embed(gamer_profile), EXISTS (
+    SELECT 1
+    FROM ban
+    WHERE ban.student_number = gamer_profile.student_number
+      AND ban.lifted_at IS NULL
+      AND ban.starts_at <= NOW()
+      AND (ban.ends_at IS NULL OR ban.ends_at > NOW())
+) AS active_ban, COUNT(*) OVER () AS total_count
 FROM gamer_profile
-WHERE student_number = $1;
+WHERE deleted_at IS NULL
+  AND (sqlc.narg('membership_tier')::INTEGER IS NULL OR membership_tier = sqlc.narg('membership_tier'))
+  AND (sqlc.narg('banned')::BOOLEAN IS NULL OR EXISTS (
+           SELECT 1
+           FROM ban
+           WHERE ban.student_number = gamer_profile.student_number
+             AND ban.lifted_at IS NULL
+             AND ban.starts_at <= NOW()
+             AND (ban.ends_at IS NULL OR ban.ends_at > NOW())
+       ) = sqlc.narg('banned'))
+  AND (sqlc.narg('expires_before')::TIMESTAMPTZ IS NULL OR membership_expiry_date < sqlc.narg('expires_before'))
+  AND (sqlc.narg('expires_after')::TIMESTAMPTZ IS NULL OR membership_expiry_date >= sqlc.narg('expires_after'))
+  AND (sqlc.narg('created_from')::TIMESTAMPTZ IS NULL OR created_at >= sqlc.narg('created_from'))
+  AND (sqlc.narg('created_to')::TIMESTAMPTZ IS NULL OR created_at < sqlc.narg('created_to'))
+  AND (sqlc.narg('search')::TEXT IS NULL
+       OR student_number ILIKE sqlc.narg('search') ESCAPE '\'
+       OR first_name ILIKE sqlc.narg('search') ESCAPE '\'
+       OR last_name ILIKE sqlc.narg('search') ESCAPE '\'
+       OR (first_name || ' ' || last_name) ILIKE sqlc.narg('search') ESCAPE '\'
+       OR email ILIKE sqlc.narg('search') ESCAPE '\'
+       OR discord_handle ILIKE sqlc.narg('search') ESCAPE '\')
+ORDER BY last_name ASC, first_name ASC, student_number ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+
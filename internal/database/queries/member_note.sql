@@ -0,0 +1,30 @@
+-- name: CreateMemberNote :one
+INSERT INTO member_note (student_number, body, category, pinned, author_app, author_exec_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetMemberNote :one
+SELECT *
+FROM member_note
+WHERE id = $1;
+
+-- name: ListMemberNotes :many
+SELECT *
+FROM member_note
+WHERE student_number = $1
+  AND (sqlc.arg('include_archived')::BOOLEAN OR archived_at IS NULL)
+ORDER BY created_at DESC;
+
+-- name: ArchiveMemberNote :one
+UPDATE member_note
+SET archived_at = NOW(),
+    archived_by_app = $2,
+    archived_by_exec_id = $3
+WHERE id = $1
+  AND archived_at IS NULL
+RETURNING *;
+
+-- name: EraseMemberNotes :exec
+UPDATE member_note
+SET body = 'Erased'
+WHERE student_number = $1;
@@ -0,0 +1,15 @@
+-- name: CreateMembership :one
+INSERT INTO membership (student_number, membership_tier, source, purchased_at, starts_at, expires_at, recorded_by_app, recorded_by_exec_id, external_ref)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: GetMembershipByExternalRef :one
+SELECT *
+FROM membership
+WHERE external_ref = $1;
+
+-- name: ListMemberships :many
+SELECT *
+FROM membership
+WHERE student_number = $1
+ORDER BY starts_at DESC, created_at DESC;
@@ -0,0 +1,86 @@
+-- name: CreateReservation :one
+INSERT INTO reservation (team_name, starts_at, ends_at, note)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: AddReservationMember :exec
+INSERT INTO reservation_member (reservation_id, student_number)
+VALUES ($1, $2);
+
+-- name: AddReservationStation :exec
+INSERT INTO reservation_station (reservation_id, station_number, starts_at, ends_at)
+VALUES ($1, $2, $3, $4);
+
+-- name: GetReservation :one
+SELECT r.*,
+       ARRAY(SELECT rs.station_number FROM reservation_station rs WHERE rs.reservation_id = r.id ORDER BY rs.station_number)::INTEGER[] AS station_numbers,
+       ARRAY(SELECT rm.student_number FROM reservation_member rm WHERE rm.reservation_id = r.id ORDER BY rm.student_number)::TEXT[] AS student_numbers
+FROM reservation r
+WHERE r.id = $1;
+
+-- name: ListReservations :many
+SELECT r.*,
+       ARRAY(SELECT rs.station_number FROM reservation_station rs WHERE rs.reservation_id = r.id ORDER BY rs.station_number)::INTEGER[] AS station_numbers,
+       ARRAY(SELECT rm.student_number FROM reservation_member rm WHERE rm.reservation_id = r.id ORDER BY rm.student_number)::TEXT[] AS student_numbers
+FROM reservation r
+WHERE (sqlc.narg('window_start')::TIMESTAMPTZ IS NULL OR r.ends_at > sqlc.narg('window_start'))
+  AND (sqlc.narg('window_end')::TIMESTAMPTZ IS NULL OR r.starts_at < sqlc.narg('window_end'))
+  AND (sqlc.narg('status')::TEXT IS NULL OR r.status = sqlc.narg('status'))
+  AND (sqlc.narg('station_number')::INTEGER IS NULL OR EXISTS (
+    SELECT 1 FROM reservation_station rs WHERE rs.reservation_id = r.id AND rs.station_number = sqlc.narg('station_number')
+  ))
+  AND (sqlc.narg('student_number')::TEXT IS NULL OR EXISTS (
+    SELECT 1 FROM reservation_member rm WHERE rm.reservation_id = r.id AND rm.student_number = sqlc.narg('student_number')
+  ))
+ORDER BY r.starts_at, r.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: GetOverlappingReservations :many
+-- Active reservations holding any of the stations at some point in the
+-- window
+SELECT r.*,
+       ARRAY(SELECT rs.station_number FROM reservation_station rs WHERE rs.reservation_id = r.id ORDER BY rs.station_number)::INTEGER[] AS station_numbers,
+       ARRAY(SELECT rm.student_number FROM reservation_member rm WHERE rm.reservation_id = r.id ORDER BY rm.student_number)::TEXT[] AS student_numbers
+FROM reservation r
+WHERE r.id IN (
+  SELECT rs.reservation_id
+  FROM reservation_station rs
+  WHERE rs.active
+    AND rs.station_number = ANY(sqlc.arg('station_numbers')::INTEGER[])
+    AND tstzrange(rs.starts_at, rs.ends_at) && tstzrange(sqlc.arg('window_start')::TIMESTAMPTZ, sqlc.arg('window_end')::TIMESTAMPTZ)
+)
+ORDER BY r.starts_at;
+
+-- name: ListNoShowReservations :many
+-- Reservations nobody has checked in to that started before the cutoff
+SELECT r.*,
+       ARRAY(SELECT rs.station_number FROM reservation_station rs WHERE rs.reservation_id = r.id ORDER BY rs.station_number)::INTEGER[] AS station_numbers,
+       ARRAY(SELECT rm.student_number FROM reservation_member rm WHERE rm.reservation_id = r.id ORDER BY rm.student_number)::TEXT[] AS student_numbers
+FROM reservation r
+WHERE r.status = 'booked'
+  AND r.starts_at < $1
+ORDER BY r.starts_at;
+
+-- name: CheckInReservation :execrows
+UPDATE reservation
+SET status = 'checked_in', checked_in_at = $2
+WHERE id = $1
+  AND status = 'booked';
+
+-- name: CancelReservation :execrows
+UPDATE reservation
+SET status = 'cancelled', cancelled_at = $2
+WHERE id = $1
+  AND status IN ('booked', 'checked_in');
+
+-- name: ReleaseNoShowReservation :execrows
+UPDATE reservation
+SET status = 'no_show', released_at = $2
+WHERE id = $1
+  AND status = 'booked';
+
+-- name: ReleaseReservationStations :exec
+-- Frees the stations of a reservation that was cancelled or not shown up to
+UPDATE reservation_station
+SET active = FALSE
+WHERE reservation_id = $1;
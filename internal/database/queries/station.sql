@@ -0,0 +1,31 @@
+-- name: CreateStation :one
+INSERT INTO station (number, zone, type, specs, status, status_reason)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetStation :one
+SELECT *
+FROM station
+WHERE number = $1;
+
+-- name: ListStations :many
+SELECT *
+FROM station
+ORDER BY number;
+
+-- name: UpdateStation :one
+UPDATE station
+SET zone = $2, type = $3, specs = $4, status = $5, status_reason = $6, updated_at = NOW()
+WHERE number = $1
+RETURNING *;
+
+-- name: DeleteStation :execrows
+DELETE FROM station
+WHERE number = $1;
+
+-- name: StationHasSessions :one
+SELECT EXISTS (
+  SELECT 1
+  FROM gamer_activity
+  WHERE pc_number = $1
+);
@@ -0,0 +1,319 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ubcesports/echo-base/internal/database/sqlc"
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/interfaces/reservation"
+	"github.com/ubcesports/echo-base/internal/models"
+	"github.com/ubcesports/echo-base/internal/utils"
+)
+
+type ReservationRepository struct {
+	db *sql.DB
+}
+
+func NewReservationRepository(db *sql.DB) reservation.ReservationRepository {
+	return &ReservationRepository{db: db}
+}
+
+func (r *ReservationRepository) Create(ctx context.Context, res *models.Reservation) (*models.Reservation, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Checking first lets the conflict name the clashing reservation.
+	// Bookings that race past the check are stopped by the exclusion
+	// constraint on reserved stations.
+	queries := sqlc.New(tx)
+	overlapping, err := overlappingReservations(ctx, queries, res.StationNumbers, res.StartsAt, res.EndsAt)
+	if err != nil {
+		return nil, err
+	}
+	if len(overlapping) > 0 {
+		return nil, reservationConflict(res, overlapping)
+	}
+
+	row, err := queries.CreateReservation(ctx, sqlc.CreateReservationParams{
+		TeamName: nullString(res.TeamName),
+		StartsAt: res.StartsAt,
+		EndsAt:   res.EndsAt,
+		Note:     nullString(res.Note),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reservation: %w", err)
+	}
+
+	for _, studentNumber := range res.StudentNumbers {
+		err := queries.AddReservationMember(ctx, sqlc.AddReservationMemberParams{
+			ReservationID: row.ID,
+			StudentNumber: studentNumber,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to add reservation member: %w", err)
+		}
+	}
+
+	for _, number := range res.StationNumbers {
+		err := queries.AddReservationStation(ctx, sqlc.AddReservationStationParams{
+			ReservationID: row.ID,
+			StationNumber: int32(number),
+			StartsAt:      res.StartsAt,
+			EndsAt:        res.EndsAt,
+		})
+		if isExclusionViolation(err) {
+			tx.Rollback()
+			overlapping, err := overlappingReservations(ctx, sqlc.New(r.db), res.StationNumbers, res.StartsAt, res.EndsAt)
+			if err != nil {
+				return nil, err
+			}
+			return nil, reservationConflict(res, overlapping)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to reserve station: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return r.Get(ctx, row.ID.String())
+}
+
+func (r *ReservationRepository) Get(ctx context.Context, id string) (*models.Reservation, error) {
+	reservationID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.NewNotFoundError("reservation", id)
+	}
+
+	queries := sqlc.New(r.db)
+	row, err := queries.GetReservation(ctx, reservationID)
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("reservation", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reservation: %w", err)
+	}
+
+	res := toReservation(row)
+	return &res, nil
+}
+
+func (r *ReservationRepository) List(ctx context.Context, filter models.ReservationFilter) ([]models.Reservation, error) {
+	stationNumber := sql.NullInt32{}
+	if filter.StationNumber != nil {
+		stationNumber = sql.NullInt32{Valid: true, Int32: int32(*filter.StationNumber)}
+	}
+
+	queries := sqlc.New(r.db)
+	rows, err := queries.ListReservations(ctx, sqlc.ListReservationsParams{
+		WindowStart:   nullTime(filter.From),
+		WindowEnd:     nullTime(filter.To),
+		Status:        nullStringValue(string(filter.Status)),
+		StationNumber: stationNumber,
+		StudentNumber: nullStringValue(filter.StudentNumber),
+		Limit:         int64(filter.Limit),
+		Offset:        int64((filter.Page - 1) * filter.Limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reservations: %w", err)
+	}
+
+	reservations := make([]models.Reservation, len(rows))
+	for i, row := range rows {
+		reservations[i] = toReservation(sqlc.GetReservationRow(row))
+	}
+	return reservations, nil
+}
+
+// GetOverlapping returns the active reservations holding any of the stations
+// at some point between windowStart and windowEnd
+func (r *ReservationRepository) GetOverlapping(ctx context.Context, stationNumbers []int, windowStart, windowEnd time.Time) ([]models.Reservation, error) {
+	return overlappingReservations(ctx, sqlc.New(r.db), stationNumbers, windowStart, windowEnd)
+}
+
+// ListNoShows returns booked reservations nobody has checked in to that
+// started before startedBefore
+func (r *ReservationRepository) ListNoShows(ctx context.Context, startedBefore time.Time) ([]models.Reservation, error) {
+	queries := sqlc.New(r.db)
+	rows, err := queries.ListNoShowReservations(ctx, startedBefore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list no-show reservations: %w", err)
+	}
+
+	reservations := make([]models.Reservation, len(rows))
+	for i, row := range rows {
+		reservations[i] = toReservation(sqlc.GetReservationRow(row))
+	}
+	return reservations, nil
+}
+
+// CheckIn marks a booked reservation as shown up to. It reports false if the
+// reservation wasn't booked any more.
+func (r *ReservationRepository) CheckIn(ctx context.Context, id string, checkedInAt time.Time) (bool, error) {
+	reservationID, err := uuid.Parse(id)
+	if err != nil {
+		return false, errors.NewNotFoundError("reservation", id)
+	}
+
+	queries := sqlc.New(r.db)
+	checkedIn, err := queries.CheckInReservation(ctx, sqlc.CheckInReservationParams{
+		ID:          reservationID,
+		CheckedInAt: sql.NullTime{Valid: true, Time: checkedInAt},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to check in reservation: %w", err)
+	}
+	return checkedIn > 0, nil
+}
+
+// Cancel cancels an active reservation and frees its stations
+func (r *ReservationRepository) Cancel(ctx context.Context, id string, cancelledAt time.Time) (*models.Reservation, error) {
+	reservationID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.NewNotFoundError("reservation", id)
+	}
+
+	released, err := r.release(ctx, reservationID, func(queries *sqlc.Queries) (int64, error) {
+		return queries.CancelReservation(ctx, sqlc.CancelReservationParams{
+			ID:          reservationID,
+			CancelledAt: sql.NullTime{Valid: true, Time: cancelledAt},
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel reservation: %w", err)
+	}
+
+	res, err := r.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !released {
+		return nil, errors.NewConflictError(fmt.Sprintf("reservation is already %s", res.Status))
+	}
+	return res, nil
+}
+
+// ReleaseNoShow gives up the stations of a reservation nobody showed up to.
+// It returns nil if the reservation was checked in to or cancelled in the
+// meantime.
+func (r *ReservationRepository) ReleaseNoShow(ctx context.Context, id string, releasedAt time.Time) (*models.Reservation, error) {
+	reservationID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, errors.NewNotFoundError("reservation", id)
+	}
+
+	released, err := r.release(ctx, reservationID, func(queries *sqlc.Queries) (int64, error) {
+		return queries.ReleaseNoShowReservation(ctx, sqlc.ReleaseNoShowReservationParams{
+			ID:         reservationID,
+			ReleasedAt: sql.NullTime{Valid: true, Time: releasedAt},
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to release reservation: %w", err)
+	}
+	if !released {
+		return nil, nil
+	}
+
+	return r.Get(ctx, id)
+}
+
+// release runs setStatus and frees the reservation's stations if it changed
+// the reservation, all in one transaction
+func (r *ReservationRepository) release(ctx context.Context, id uuid.UUID, setStatus func(*sqlc.Queries) (int64, error)) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	queries := sqlc.New(tx)
+	changed, err := setStatus(queries)
+	if err != nil {
+		return false, err
+	}
+	if changed == 0 {
+		return false, nil
+	}
+
+	if err := queries.ReleaseReservationStations(ctx, id); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func overlappingReservations(ctx context.Context, queries *sqlc.Queries, stationNumbers []int, windowStart, windowEnd time.Time) ([]models.Reservation, error) {
+	numbers := make([]int32, len(stationNumbers))
+	for i, n := range stationNumbers {
+		numbers[i] = int32(n)
+	}
+
+	rows, err := queries.GetOverlappingReservations(ctx, sqlc.GetOverlappingReservationsParams{
+		StationNumbers: numbers,
+		WindowStart:    windowStart,
+		WindowEnd:      windowEnd,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to check overlapping reservations: %w", err)
+	}
+
+	reservations := make([]models.Reservation, len(rows))
+	for i, row := range rows {
+		reservations[i] = toReservation(sqlc.GetReservationRow(row))
+	}
+	return reservations, nil
+}
+
+// reservationConflict names the first station of res that is already
+// reserved and when
+func reservationConflict(res *models.Reservation, overlapping []models.Reservation) error {
+	for _, other := range overlapping {
+		for _, number := range res.StationNumbers {
+			if slices.Contains(other.StationNumbers, number) {
+				return errors.NewConflictError(fmt.Sprintf("station %d is already reserved from %s to %s",
+					number,
+					other.StartsAt.In(utils.LoungeLocation).Format("Jan 2 15:04"),
+					other.EndsAt.In(utils.LoungeLocation).Format("Jan 2 15:04")))
+			}
+		}
+	}
+	return errors.NewConflictError("the stations are already reserved at that time")
+}
+
+func toReservation(row sqlc.GetReservationRow) models.Reservation {
+	res := models.Reservation{
+		ID:             row.ID.String(),
+		StationNumbers: make([]int, len(row.StationNumbers)),
+		StudentNumbers: row.StudentNumbers,
+		StartsAt:       row.StartsAt,
+		EndsAt:         row.EndsAt,
+		Status:         models.ReservationStatus(row.Status),
+		CreatedAt:      row.CreatedAt,
+		CheckedInAt:    timePtr(row.CheckedInAt),
+		CancelledAt:    timePtr(row.CancelledAt),
+		ReleasedAt:     timePtr(row.ReleasedAt),
+	}
+	for i, n := range row.StationNumbers {
+		res.StationNumbers[i] = int(n)
+	}
+	if res.StudentNumbers == nil {
+		res.StudentNumbers = []string{}
+	}
+	if row.TeamName.Valid {
+		res.TeamName = &row.TeamName.String
+	}
+	if row.Note.Valid {
+		res.Note = &row.Note.String
+	}
+	return res
+}
@@ -13,9 +13,9 @@ import (
 )
 
 const createGamerActivity = `-- name: CreateGamerActivity :one
-INSERT INTO gamer_activity (id, student_number, pc_number, game, started_at)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, student_number, pc_number, game, started_at, ended_at, exec_name
+INSERT INTO gamer_activity (id, student_number, pc_number, game, started_at, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, student_number, pc_number, game, started_at, ended_at, exec_name, expires_at
 `
 
 type CreateGamerActivityParams struct {
@@ -24,6 +24,7 @@ type CreateGamerActivityParams struct {
 	PcNumber      sql.NullInt32
 	Game          sql.NullString
 	StartedAt     sql.NullTime
+	ExpiresAt     sql.NullTime
 }
 
 type CreateGamerActivityRow struct {
@@ -34,6 +35,7 @@ type CreateGamerActivityRow struct {
 	StartedAt     sql.NullTime
 	EndedAt       sql.NullTime
 	ExecName      sql.NullString
+	ExpiresAt     sql.NullTime
 }
 
 func (q *Queries) CreateGamerActivity(ctx context.Context, arg CreateGamerActivityParams) (CreateGamerActivityRow, error) {
@@ -43,6 +45,7 @@ func (q *Queries) CreateGamerActivity(ctx context.Context, arg CreateGamerActivi
 		arg.PcNumber,
 		arg.Game,
 		arg.StartedAt,
+		arg.ExpiresAt,
 	)
 	var i CreateGamerActivityRow
 	err := row.Scan(
@@ -53,29 +56,96 @@ func (q *Queries) CreateGamerActivity(ctx context.Context, arg CreateGamerActivi
 		&i.StartedAt,
 		&i.EndedAt,
 		&i.ExecName,
+		&i.ExpiresAt,
 	)
 	return i, err
 }
 
+const endOverdueSession = `-- name: EndOverdueSession :one
+UPDATE gamer_activity
+SET ended_at = $1, ended_by_system = TRUE
+WHERE id = $2
+AND ended_at IS NULL
+RETURNING id, student_number, pc_number, game, started_at, ended_at, exec_name, exec_id, ended_by_system
+`
+
+type EndOverdueSessionParams struct {
+	EndedAt sql.NullTime
+	ID      uuid.UUID
+}
+
+type EndOverdueSessionRow struct {
+	ID            uuid.UUID
+	StudentNumber string
+	PcNumber      sql.NullInt32
+	Game          sql.NullString
+	StartedAt     sql.NullTime
+	EndedAt       sql.NullTime
+	ExecName      sql.NullString
+	ExecID        uuid.NullUUID
+	EndedBySystem bool
+}
+
+// Overdue sessions ended by the server have no exec.
+func (q *Queries) EndOverdueSession(ctx context.Context, arg EndOverdueSessionParams) (EndOverdueSessionRow, error) {
+	row := q.db.QueryRowContext(ctx, endOverdueSession, arg.EndedAt, arg.ID)
+	var i EndOverdueSessionRow
+	err := row.Scan(
+		&i.ID,
+		&i.StudentNumber,
+		&i.PcNumber,
+		&i.Game,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.ExecName,
+		&i.ExecID,
+		&i.EndedBySystem,
+	)
+	return i, err
+}
+
+const flagOverdueSession = `-- name: FlagOverdueSession :execrows
+UPDATE gamer_activity
+SET overdue_flagged_at = $1
+WHERE id = $2
+AND ended_at IS NULL
+AND overdue_flagged_at IS NULL
+`
+
+type FlagOverdueSessionParams struct {
+	OverdueFlaggedAt sql.NullTime
+	ID               uuid.UUID
+}
+
+func (q *Queries) FlagOverdueSession(ctx context.Context, arg FlagOverdueSessionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, flagOverdueSession, arg.OverdueFlaggedAt, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getActiveSessions = `-- name: GetActiveSessions :many
 SELECT ga.id, ga.student_number, ga.pc_number, ga.game, ga.started_at, ga.ended_at, ga.exec_name,
-       gp.first_name, gp.last_name, gp.membership_tier
+       ga.expires_at, ga.overdue_flagged_at, gp.first_name, gp.last_name, gp.membership_tier
 FROM gamer_activity ga
 JOIN gamer_profile gp ON ga.student_number = gp.student_number
 WHERE ga.ended_at IS NULL
 `
 
 type GetActiveSessionsRow struct {
-	ID             uuid.UUID
-	StudentNumber  string
-	PcNumber       sql.NullInt32
-	Game           sql.NullString
-	StartedAt      sql.NullTime
-	EndedAt        sql.NullTime
-	ExecName       sql.NullString
-	FirstName      string
-	LastName       string
-	MembershipTier int32
+	ID               uuid.UUID
+	StudentNumber    string
+	PcNumber         sql.NullInt32
+	Game             sql.NullString
+	StartedAt        sql.NullTime
+	EndedAt          sql.NullTime
+	ExecName         sql.NullString
+	ExpiresAt        sql.NullTime
+	OverdueFlaggedAt sql.NullTime
+	FirstName        string
+	LastName         string
+	MembershipTier   int32
 }
 
 func (q *Queries) GetActiveSessions(ctx context.Context) ([]GetActiveSessionsRow, error) {
@@ -95,6 +165,8 @@ func (q *Queries) GetActiveSessions(ctx context.Context) ([]GetActiveSessionsRow
 			&i.StartedAt,
 			&i.EndedAt,
 			&i.ExecName,
+			&i.ExpiresAt,
+			&i.OverdueFlaggedAt,
 			&i.FirstName,
 			&i.LastName,
 			&i.MembershipTier,
@@ -113,13 +185,15 @@ func (q *Queries) GetActiveSessions(ctx context.Context) ([]GetActiveSessionsRow
 }
 
 const getExecLeaderboard = `-- name: GetExecLeaderboard :many
-SELECT exec_name, COUNT(*)::BIGINT AS signout_count
-FROM gamer_activity
-WHERE ended_at IS NOT NULL
-AND exec_name IS NOT NULL
-AND ended_at >= $1
-AND ended_at < $2
-GROUP BY exec_name
+SELECT ga.exec_id, COALESCE(e.name, ga.exec_name)::TEXT AS exec_name, COUNT(*)::BIGINT AS signout_count
+FROM gamer_activity ga
+LEFT JOIN exec e ON e.id = ga.exec_id
+WHERE ga.ended_at IS NOT NULL
+AND NOT ga.ended_by_system
+AND (ga.exec_id IS NOT NULL OR ga.exec_name IS NOT NULL)
+AND ga.ended_at >= $1
+AND ga.ended_at < $2
+GROUP BY ga.exec_id, COALESCE(e.name, ga.exec_name)
 ORDER BY signout_count DESC, exec_name ASC
 `
 
@@ -129,10 +203,14 @@ type GetExecLeaderboardParams struct {
 }
 
 type GetExecLeaderboardRow struct {
-	ExecName     sql.NullString
+	ExecID       uuid.NullUUID
+	ExecName     string
 	SignoutCount int64
 }
 
+// Sessions signed out by an exec account count towards that account under
+// its current name. Older sessions only have the free-text exec_name.
+// Sessions the server ended don't count towards anyone.
 func (q *Queries) GetExecLeaderboard(ctx context.Context, arg GetExecLeaderboardParams) ([]GetExecLeaderboardRow, error) {
 	rows, err := q.db.QueryContext(ctx, getExecLeaderboard, arg.EndedAt, arg.EndedAt_2)
 	if err != nil {
@@ -142,7 +220,7 @@ func (q *Queries) GetExecLeaderboard(ctx context.Context, arg GetExecLeaderboard
 	var items []GetExecLeaderboardRow
 	for rows.Next() {
 		var i GetExecLeaderboardRow
-		if err := rows.Scan(&i.ExecName, &i.SignoutCount); err != nil {
+		if err := rows.Scan(&i.ExecID, &i.ExecName, &i.SignoutCount); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -204,6 +282,68 @@ func (q *Queries) GetGamerActivity(ctx context.Context, studentNumber string) ([
 	return items, nil
 }
 
+const getOpenSessionConflicts = `-- name: GetOpenSessionConflicts :many
+SELECT ga.id, ga.student_number, ga.pc_number, ga.game, ga.started_at, ga.ended_at, ga.exec_name,
+       gp.first_name, gp.last_name
+FROM gamer_activity ga
+JOIN gamer_profile gp ON ga.student_number = gp.student_number
+WHERE ga.ended_at IS NULL
+AND (ga.pc_number = $1 OR ga.student_number = $2)
+ORDER BY ga.started_at
+`
+
+type GetOpenSessionConflictsParams struct {
+	PcNumber      sql.NullInt32
+	StudentNumber string
+}
+
+type GetOpenSessionConflictsRow struct {
+	ID            uuid.UUID
+	StudentNumber string
+	PcNumber      sql.NullInt32
+	Game          sql.NullString
+	StartedAt     sql.NullTime
+	EndedAt       sql.NullTime
+	ExecName      sql.NullString
+	FirstName     string
+	LastName      string
+}
+
+// Open sessions on the PC or for the student, which a new session would clash
+// with.
+func (q *Queries) GetOpenSessionConflicts(ctx context.Context, arg GetOpenSessionConflictsParams) ([]GetOpenSessionConflictsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getOpenSessionConflicts, arg.PcNumber, arg.StudentNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOpenSessionConflictsRow
+	for rows.Next() {
+		var i GetOpenSessionConflictsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.StudentNumber,
+			&i.PcNumber,
+			&i.Game,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.ExecName,
+			&i.FirstName,
+			&i.LastName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getRecentActivities = `-- name: GetRecentActivities :many
 SELECT ga.id, ga.student_number, ga.pc_number, ga.game, ga.started_at, ga.ended_at, ga.exec_name,
        gp.first_name, gp.last_name
@@ -328,6 +468,63 @@ func (q *Queries) GetRecentActivitiesWithSearch(ctx context.Context, arg GetRece
 	return items, nil
 }
 
+const getStudentActivityBetween = `-- name: GetStudentActivityBetween :many
+SELECT id, student_number, pc_number, game, started_at, ended_at, exec_name
+FROM gamer_activity
+WHERE student_number = $1
+AND started_at < $2
+AND (ended_at IS NULL OR ended_at > $3)
+ORDER BY started_at
+`
+
+type GetStudentActivityBetweenParams struct {
+	StudentNumber string
+	WindowEnd     sql.NullTime
+	WindowStart   sql.NullTime
+}
+
+type GetStudentActivityBetweenRow struct {
+	ID            uuid.UUID
+	StudentNumber string
+	PcNumber      sql.NullInt32
+	Game          sql.NullString
+	StartedAt     sql.NullTime
+	EndedAt       sql.NullTime
+	ExecName      sql.NullString
+}
+
+// Sessions that overlap the window, including ones still running.
+func (q *Queries) GetStudentActivityBetween(ctx context.Context, arg GetStudentActivityBetweenParams) ([]GetStudentActivityBetweenRow, error) {
+	rows, err := q.db.QueryContext(ctx, getStudentActivityBetween, arg.StudentNumber, arg.WindowEnd, arg.WindowStart)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetStudentActivityBetweenRow
+	for rows.Next() {
+		var i GetStudentActivityBetweenRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.StudentNumber,
+			&i.PcNumber,
+			&i.Game,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.ExecName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTodayActivitiesByStudent = `-- name: GetTodayActivitiesByStudent :many
 SELECT ga.id, ga.student_number, ga.pc_number, ga.game, ga.started_at, ga.ended_at, ga.exec_name
 FROM gamer_activity ga
@@ -380,16 +577,17 @@ func (q *Queries) GetTodayActivitiesByStudent(ctx context.Context, studentNumber
 
 const updateActivityEndTime = `-- name: UpdateActivityEndTime :one
 UPDATE gamer_activity
-SET ended_at = $1, exec_name = $2
-WHERE student_number = $3
-AND pc_number = $4
+SET ended_at = $1, exec_name = $2, exec_id = $3
+WHERE student_number = $4
+AND pc_number = $5
 AND ended_at IS NULL
-RETURNING id, student_number, pc_number, game, started_at, ended_at, exec_name
+RETURNING id, student_number, pc_number, game, started_at, ended_at, exec_name, exec_id
 `
 
 type UpdateActivityEndTimeParams struct {
 	EndedAt       sql.NullTime
 	ExecName      sql.NullString
+	ExecID        uuid.NullUUID
 	StudentNumber string
 	PcNumber      sql.NullInt32
 }
@@ -402,12 +600,14 @@ type UpdateActivityEndTimeRow struct {
 	StartedAt     sql.NullTime
 	EndedAt       sql.NullTime
 	ExecName      sql.NullString
+	ExecID        uuid.NullUUID
 }
 
 func (q *Queries) UpdateActivityEndTime(ctx context.Context, arg UpdateActivityEndTimeParams) (UpdateActivityEndTimeRow, error) {
 	row := q.db.QueryRowContext(ctx, updateActivityEndTime,
 		arg.EndedAt,
 		arg.ExecName,
+		arg.ExecID,
 		arg.StudentNumber,
 		arg.PcNumber,
 	)
@@ -420,6 +620,7 @@ func (q *Queries) UpdateActivityEndTime(ctx context.Context, arg UpdateActivityE
 		&i.StartedAt,
 		&i.EndedAt,
 		&i.ExecName,
+		&i.ExecID,
 	)
 	return i, err
 }
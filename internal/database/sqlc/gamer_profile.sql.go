@@ -11,9 +11,18 @@ import (
 )
 
 const checkMembershipValidity = `-- name: CheckMembershipValidity :one
-SELECT membership_tier, membership_expiry_date
-FROM gamer_profile
-WHERE student_number = $1
+SELECT COALESCE(m.membership_tier, 0)::INTEGER AS membership_tier, m.expires_at AS membership_expiry_date
+FROM gamer_profile p
+LEFT JOIN LATERAL (
+    SELECT membership_tier, expires_at
+    FROM membership
+    WHERE membership.student_number = p.student_number
+      AND starts_at <= NOW()
+    ORDER BY starts_at DESC, created_at DESC
+    LIMIT 1
+) m ON TRUE
+WHERE p.student_number = $1
+  AND p.deleted_at IS NULL
 `
 
 type CheckMembershipValidityRow struct {
@@ -21,6 +30,8 @@ type CheckMembershipValidityRow struct {
 	MembershipExpiryDate sql.NullTime
 }
 
+// The current membership is the latest one to have started. Members without
+// one are on tier 0.
 func (q *Queries) CheckMembershipValidity(ctx context.Context, studentNumber string) (CheckMembershipValidityRow, error) {
 	row := q.db.QueryRowContext(ctx, checkMembershipValidity, studentNumber)
 	var i CheckMembershipValidityRow
@@ -28,26 +39,442 @@ func (q *Queries) CheckMembershipValidity(ctx context.Context, studentNumber str
 	return i, err
 }
 
-const deleteGamerProfile = `-- name: DeleteGamerProfile :execrows
-DELETE FROM gamer_profile WHERE student_number = $1
+const eraseGamerProfile = `-- name: EraseGamerProfile :one
+UPDATE gamer_profile
+SET student_number = $1,
+    first_name = 'Erased',
+    last_name = 'Member',
+    notes = NULL,
+    email = NULL,
+    discord_handle = NULL,
+    marketing_consent = FALSE,
+    notification_consent = FALSE,
+    deleted_at = COALESCE(deleted_at, NOW()),
+    erased_at = NOW()
+WHERE student_number = $2
+  AND erased_at IS NULL
+RETURNING first_name, last_name, student_number, membership_tier, banned, notes, created_at, id, membership_expiry_date, deleted_at, erased_at, email, discord_handle, marketing_consent, notification_consent
+`
+
+type EraseGamerProfileParams struct {
+	Pseudonym     string
+	StudentNumber string
+}
+
+// Replaces the student number with a pseudonym that can't be looked up
+// and blanks the personal fields. Sessions, memberships and bans follow the
+// new student number through ON UPDATE CASCADE. A pseudonym that is already
+// taken fails the primary key, so the caller should retry with another.
+func (q *Queries) EraseGamerProfile(ctx context.Context, arg EraseGamerProfileParams) (GamerProfile, error) {
+	row := q.db.QueryRowContext(ctx, eraseGamerProfile, arg.Pseudonym, arg.StudentNumber)
+	var i GamerProfile
+	err := row.Scan(
+		&i.FirstName,
+		&i.LastName,
+		&i.StudentNumber,
+		&i.MembershipTier,
+		&i.Banned,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.ID,
+		&i.MembershipExpiryDate,
+		&i.DeletedAt,
+		&i.ErasedAt,
+		&i.Email,
+		&i.DiscordHandle,
+		&i.MarketingConsent,
+		&i.NotificationConsent,
+	)
+	return i, err
+}
+
+const getGamerProfile = `-- name: GetGamerProfile :one
+SELECT gamer_profile.first_name, gamer_profile.last_name, gamer_profile.student_number, gamer_profile.membership_tier, gamer_profile.banned, gamer_profile.notes, gamer_profile.created_at, gamer_profile.id, gamer_profile.membership_expiry_date, gamer_profile.deleted_at, gamer_profile.erased_at, gamer_profile.email, gamer_profile.discord_handle, gamer_profile.marketing_consent, gamer_profile.notification_consent, EXISTS (
+    SELECT 1
+    FROM ban
+    WHERE ban.student_number = gamer_profile.student_number
+      AND ban.lifted_at IS NULL
+      AND ban.starts_at <= NOW()
+      AND (ban.ends_at IS NULL OR ban.ends_at > NOW())
+) AS active_ban
+FROM gamer_profile
+WHERE student_number = $1
+  AND deleted_at IS NULL
+`
+
+type GetGamerProfileRow struct {
+	GamerProfile GamerProfile
+	ActiveBan    bool
+}
+
+func (q *Queries) GetGamerProfile(ctx context.Context, studentNumber string) (GetGamerProfileRow, error) {
+	row := q.db.QueryRowContext(ctx, getGamerProfile, studentNumber)
+	var i GetGamerProfileRow
+	err := row.Scan(
+		&i.GamerProfile.FirstName,
+		&i.GamerProfile.LastName,
+		&i.GamerProfile.StudentNumber,
+		&i.GamerProfile.MembershipTier,
+		&i.GamerProfile.Banned,
+		&i.GamerProfile.Notes,
+		&i.GamerProfile.CreatedAt,
+		&i.GamerProfile.ID,
+		&i.GamerProfile.MembershipExpiryDate,
+		&i.GamerProfile.DeletedAt,
+		&i.GamerProfile.ErasedAt,
+		&i.GamerProfile.Email,
+		&i.GamerProfile.DiscordHandle,
+		&i.GamerProfile.MarketingConsent,
+		&i.GamerProfile.NotificationConsent,
+		&i.ActiveBan,
+	)
+	return i, err
+}
+
+const getGamerProfileByEmail = `-- name: GetGamerProfileByEmail :one
+SELECT first_name, last_name, student_number, membership_tier, banned, notes, created_at, id, membership_expiry_date, deleted_at, erased_at, email, discord_handle, marketing_consent, notification_consent
+FROM gamer_profile
+WHERE LOWER(email) = LOWER($1)
+`
+
+// Deleted profiles are included since they still hold their email.
+func (q *Queries) GetGamerProfileByEmail(ctx context.Context, email string) (GamerProfile, error) {
+	row := q.db.QueryRowContext(ctx, getGamerProfileByEmail, email)
+	var i GamerProfile
+	err := row.Scan(
+		&i.FirstName,
+		&i.LastName,
+		&i.StudentNumber,
+		&i.MembershipTier,
+		&i.Banned,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.ID,
+		&i.MembershipExpiryDate,
+		&i.DeletedAt,
+		&i.ErasedAt,
+		&i.Email,
+		&i.DiscordHandle,
+		&i.MarketingConsent,
+		&i.NotificationConsent,
+	)
+	return i, err
+}
+
+const getGamerProfileForUpdate = `-- name: GetGamerProfileForUpdate :one
+SELECT gamer_profile.first_name, gamer_profile.last_name, gamer_profile.student_number, gamer_profile.membership_tier, gamer_profile.banned, gamer_profile.notes, gamer_profile.created_at, gamer_profile.id, gamer_profile.membership_expiry_date, gamer_profile.deleted_at, gamer_profile.erased_at, gamer_profile.email, gamer_profile.discord_handle, gamer_profile.marketing_consent, gamer_profile.notification_consent, EXISTS (
+    SELECT 1
+    FROM ban
+    WHERE ban.student_number = gamer_profile.student_number
+      AND ban.lifted_at IS NULL
+      AND ban.starts_at <= NOW()
+      AND (ban.ends_at IS NULL OR ban.ends_at > NOW())
+) AS active_ban
+FROM gamer_profile
+WHERE student_number = $1
+  AND deleted_at IS NULL
+FOR UPDATE OF gamer_profile
+`
+
+type GetGamerProfileForUpdateRow struct {
+	GamerProfile GamerProfile
+	ActiveBan    bool
+}
+
+// Locks the profile row until the end of the transaction so concurrent
+// partial updates apply one after another.
+func (q *Queries) GetGamerProfileForUpdate(ctx context.Context, studentNumber string) (GetGamerProfileForUpdateRow, error) {
+	row := q.db.QueryRowContext(ctx, getGamerProfileForUpdate, studentNumber)
+	var i GetGamerProfileForUpdateRow
+	err := row.Scan(
+		&i.GamerProfile.FirstName,
+		&i.GamerProfile.LastName,
+		&i.GamerProfile.StudentNumber,
+		&i.GamerProfile.MembershipTier,
+		&i.GamerProfile.Banned,
+		&i.GamerProfile.Notes,
+		&i.GamerProfile.CreatedAt,
+		&i.GamerProfile.ID,
+		&i.GamerProfile.MembershipExpiryDate,
+		&i.GamerProfile.DeletedAt,
+		&i.GamerProfile.ErasedAt,
+		&i.GamerProfile.Email,
+		&i.GamerProfile.DiscordHandle,
+		&i.GamerProfile.MarketingConsent,
+		&i.GamerProfile.NotificationConsent,
+		&i.ActiveBan,
+	)
+	return i, err
+}
+
+const getGamerProfileIncludingDeleted = `-- name: GetGamerProfileIncludingDeleted :one
+SELECT gamer_profile.first_name, gamer_profile.last_name, gamer_profile.student_number, gamer_profile.membership_tier, gamer_profile.banned, gamer_profile.notes, gamer_profile.created_at, gamer_profile.id, gamer_profile.membership_expiry_date, gamer_profile.deleted_at, gamer_profile.erased_at, gamer_profile.email, gamer_profile.discord_handle, gamer_profile.marketing_consent, gamer_profile.notification_consent, EXISTS (
+    SELECT 1
+    FROM ban
+    WHERE ban.student_number = gamer_profile.student_number
+      AND ban.lifted_at IS NULL
+      AND ban.starts_at <= NOW()
+      AND (ban.ends_at IS NULL OR ban.ends_at > NOW())
+) AS active_ban
+FROM gamer_profile
+WHERE student_number = $1
+`
+
+type GetGamerProfileIncludingDeletedRow struct {
+	GamerProfile GamerProfile
+	ActiveBan    bool
+}
+
+func (q *Queries) GetGamerProfileIncludingDeleted(ctx context.Context, studentNumber string) (GetGamerProfileIncludingDeletedRow, error) {
+	row := q.db.QueryRowContext(ctx, getGamerProfileIncludingDeleted, studentNumber)
+	var i GetGamerProfileIncludingDeletedRow
+	err := row.Scan(
+		&i.GamerProfile.FirstName,
+		&i.GamerProfile.LastName,
+		&i.GamerProfile.StudentNumber,
+		&i.GamerProfile.MembershipTier,
+		&i.GamerProfile.Banned,
+		&i.GamerProfile.Notes,
+		&i.GamerProfile.CreatedAt,
+		&i.GamerProfile.ID,
+		&i.GamerProfile.MembershipExpiryDate,
+		&i.GamerProfile.DeletedAt,
+		&i.GamerProfile.ErasedAt,
+		&i.GamerProfile.Email,
+		&i.GamerProfile.DiscordHandle,
+		&i.GamerProfile.MarketingConsent,
+		&i.GamerProfile.NotificationConsent,
+		&i.ActiveBan,
+	)
+	return i, err
+}
+
+const listGamerProfiles = `-- name: ListGamerProfiles :many
+SELECT gamer_profile.first_name, gamer_profile.last_name, gamer_profile.student_number, gamer_profile.membership_tier, gamer_profile.banned, gamer_profile.notes, gamer_profile.created_at, gamer_profile.id, gamer_profile.membership_expiry_date, gamer_profile.deleted_at, gamer_profile.erased_at, gamer_profile.email, gamer_profile.discord_handle, gamer_profile.marketing_consent, gamer_profile.notification_consent, EXISTS (
+    SELECT 1
+    FROM ban
+    WHERE ban.student_number = gamer_profile.student_number
+      AND ban.lifted_at IS NULL
+      AND ban.starts_at <= NOW()
+      AND (ban.ends_at IS NULL OR ban.ends_at > NOW())
+) AS active_ban, COUNT(*) OVER () AS total_count
+FROM gamer_profile
+WHERE deleted_at IS NULL
+  AND ($1::INTEGER IS NULL OR membership_tier = $1)
+  AND ($2::BOOLEAN IS NULL OR EXISTS (
+           SELECT 1
+           FROM ban
+           WHERE ban.student_number = gamer_profile.student_number
+             AND ban.lifted_at IS NULL
+             AND ban.starts_at <= NOW()
+             AND (ban.ends_at IS NULL OR ban.ends_at > NOW())
+       ) = $2)
+  AND ($3::TIMESTAMPTZ IS NULL OR membership_expiry_date < $3)
+  AND ($4::TIMESTAMPTZ IS NULL OR membership_expiry_date >= $4)
+  AND ($5::TIMESTAMPTZ IS NULL OR created_at >= $5)
+  AND ($6::TIMESTAMPTZ IS NULL OR created_at < $6)
+  AND ($7::TEXT IS NULL
+       OR student_number ILIKE $7 ESCAPE '\'
+       OR first_name ILIKE $7 ESCAPE '\'
+       OR last_name ILIKE $7 ESCAPE '\'
+       OR (first_name || ' ' || last_name) ILIKE $7 ESCAPE '\'
+       OR email ILIKE $7 ESCAPE '\'
+       OR discord_handle ILIKE $7 ESCAPE '\')
+ORDER BY last_name ASC, first_name ASC, student_number ASC
+LIMIT $8 OFFSET $9
+`
+
+type ListGamerProfilesParams struct {
+	MembershipTier sql.NullInt32
+	Banned         sql.NullBool
+	ExpiresBefore  sql.NullTime
+	ExpiresAfter   sql.NullTime
+	CreatedFrom    sql.NullTime
+	CreatedTo      sql.NullTime
+	Search         sql.NullString
+	Limit          int64
+	Offset         int64
+}
+
+type ListGamerProfilesRow struct {
+	GamerProfile GamerProfile
+	ActiveBan    bool
+	TotalCount   int64
+}
+
+// search is a LIKE pattern whose wildcards have already been escaped with \.
+// total_count counts every matching profile, not just the page.
+
+func (q *Queries) ListGamerProfiles(ctx context.Context, arg ListGamerProfilesParams) ([]ListGamerProfilesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listGamerProfiles,
+		arg.MembershipTier,
+		arg.Banned,
+		arg.ExpiresBefore,
+		arg.ExpiresAfter,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.Search,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListGamerProfilesRow
+	for rows.Next() {
+		var i ListGamerProfilesRow
+		if err := rows.Scan(
+			&i.GamerProfile.FirstName,
+			&i.GamerProfile.LastName,
+			&i.GamerProfile.StudentNumber,
+			&i.GamerProfile.MembershipTier,
+			&i.GamerProfile.Banned,
+			&i.GamerProfile.Notes,
+			&i.GamerProfile.CreatedAt,
+			&i.GamerProfile.ID,
+			&i.GamerProfile.MembershipExpiryDate,
+			&i.GamerProfile.DeletedAt,
+			&i.GamerProfile.ErasedAt,
+			&i.GamerProfile.Email,
+			&i.GamerProfile.DiscordHandle,
+			&i.GamerProfile.MarketingConsent,
+			&i.GamerProfile.NotificationConsent,
+			&i.ActiveBan,
+			&i.TotalCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const refreshProfileNotes = `-- name: RefreshProfileNotes :exec
+UPDATE gamer_profile
+SET notes = (
+    SELECT body
+    FROM member_note
+    WHERE member_note.student_number = gamer_profile.student_number
+      AND pinned
+      AND archived_at IS NULL
+    ORDER BY created_at DESC
+    LIMIT 1
+)
+WHERE student_number = $1
+`
+
+// notes is a copy of the latest pinned note, kept for clients from before
+// notes were a timeline
+func (q *Queries) RefreshProfileNotes(ctx context.Context, studentNumber string) error {
+	_, err := q.db.ExecContext(ctx, refreshProfileNotes, studentNumber)
+	return err
+}
+
+const restoreGamerProfile = `-- name: RestoreGamerProfile :one
+UPDATE gamer_profile
+SET deleted_at = NULL
+WHERE student_number = $1
+  AND deleted_at IS NOT NULL
+  AND erased_at IS NULL
+RETURNING first_name, last_name, student_number, membership_tier, banned, notes, created_at, id, membership_expiry_date, deleted_at, erased_at, email, discord_handle, marketing_consent, notification_consent
+`
+
+func (q *Queries) RestoreGamerProfile(ctx context.Context, studentNumber string) (GamerProfile, error) {
+	row := q.db.QueryRowContext(ctx, restoreGamerProfile, studentNumber)
+	var i GamerProfile
+	err := row.Scan(
+		&i.FirstName,
+		&i.LastName,
+		&i.StudentNumber,
+		&i.MembershipTier,
+		&i.Banned,
+		&i.Notes,
+		&i.CreatedAt,
+		&i.ID,
+		&i.MembershipExpiryDate,
+		&i.DeletedAt,
+		&i.ErasedAt,
+		&i.Email,
+		&i.DiscordHandle,
+		&i.MarketingConsent,
+		&i.NotificationConsent,
+	)
+	return i, err
+}
+
+const setProfileMembership = `-- name: SetProfileMembership :exec
+UPDATE gamer_profile
+SET membership_tier = $2,
+    membership_expiry_date = $3
+WHERE student_number = $1
+`
+
+type SetProfileMembershipParams struct {
+	StudentNumber        string
+	MembershipTier       int32
+	MembershipExpiryDate sql.NullTime
+}
+
+func (q *Queries) SetProfileMembership(ctx context.Context, arg SetProfileMembershipParams) error {
+	_, err := q.db.ExecContext(ctx, setProfileMembership, arg.StudentNumber, arg.MembershipTier, arg.MembershipExpiryDate)
+	return err
+}
+
+const softDeleteGamerProfile = `-- name: SoftDeleteGamerProfile :execrows
+UPDATE gamer_profile
+SET deleted_at = NOW()
+WHERE student_number = $1
+  AND deleted_at IS NULL
 `
 
-func (q *Queries) DeleteGamerProfile(ctx context.Context, studentNumber string) (int64, error) {
-	result, err := q.db.ExecContext(ctx, deleteGamerProfile, studentNumber)
+func (q *Queries) SoftDeleteGamerProfile(ctx context.Context, studentNumber string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, softDeleteGamerProfile, studentNumber)
 	if err != nil {
 		return 0, err
 	}
 	return result.RowsAffected()
 }
 
-const getGamerProfile = `-- name: GetGamerProfile :one
-SELECT first_name, last_name, student_number, membership_tier, banned, notes, created_at, id, membership_expiry_date
-FROM gamer_profile
+const updateGamerProfile = `-- name: UpdateGamerProfile :one
+UPDATE gamer_profile
+SET first_name = $2,
+    last_name = $3,
+    email = $4,
+    discord_handle = $5,
+    marketing_consent = $6,
+    notification_consent = $7
 WHERE student_number = $1
+  AND deleted_at IS NULL
+RETURNING first_name, last_name, student_number, membership_tier, banned, notes, created_at, id, membership_expiry_date, deleted_at, erased_at, email, discord_handle, marketing_consent, notification_consent
 `
 
-func (q *Queries) GetGamerProfile(ctx context.Context, studentNumber string) (GamerProfile, error) {
-	row := q.db.QueryRowContext(ctx, getGamerProfile, studentNumber)
+type UpdateGamerProfileParams struct {
+	StudentNumber       string
+	FirstName           string
+	LastName            string
+	Email               sql.NullString
+	DiscordHandle       sql.NullString
+	MarketingConsent    bool
+	NotificationConsent bool
+}
+
+func (q *Queries) UpdateGamerProfile(ctx context.Context, arg UpdateGamerProfileParams) (GamerProfile, error) {
+	row := q.db.QueryRowContext(ctx, updateGamerProfile,
+		arg.StudentNumber,
+		arg.FirstName,
+		arg.LastName,
+		arg.Email,
+		arg.DiscordHandle,
+		arg.MarketingConsent,
+		arg.NotificationConsent,
+	)
 	var i GamerProfile
 	err := row.Scan(
 		&i.FirstName,
@@ -59,23 +486,29 @@ func (q *Queries) GetGamerProfile(ctx context.Context, studentNumber string) (Ga
 		&i.CreatedAt,
 		&i.ID,
 		&i.MembershipExpiryDate,
+		&i.DeletedAt,
+		&i.ErasedAt,
+		&i.Email,
+		&i.DiscordHandle,
+		&i.MarketingConsent,
+		&i.NotificationConsent,
 	)
 	return i, err
 }
 
 const upsertGamerProfile = `-- name: UpsertGamerProfile :one
-INSERT INTO gamer_profile (first_name, last_name, student_number, membership_tier, banned, notes, created_at, membership_expiry_date)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+INSERT INTO gamer_profile (first_name, last_name, student_number, membership_tier, banned, created_at, membership_expiry_date, email, discord_handle, marketing_consent, notification_consent)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 ON CONFLICT (student_number)
 DO UPDATE SET
     first_name = EXCLUDED.first_name,
     last_name = EXCLUDED.last_name,
-    membership_tier = EXCLUDED.membership_tier,
-    banned = EXCLUDED.banned,
-    notes = EXCLUDED.notes,
-    created_at = EXCLUDED.created_at,
-    membership_expiry_date = EXCLUDED.membership_expiry_date
-RETURNING first_name, last_name, student_number, membership_tier, banned, notes, created_at, id, membership_expiry_date
+    email = EXCLUDED.email,
+    discord_handle = EXCLUDED.discord_handle,
+    marketing_consent = EXCLUDED.marketing_consent,
+    notification_consent = EXCLUDED.notification_consent
+WHERE gamer_profile.deleted_at IS NULL
+RETURNING first_name, last_name, student_number, membership_tier, banned, notes, created_at, id, membership_expiry_date, deleted_at, erased_at, email, discord_handle, marketing_consent, notification_consent
 `
 
 type UpsertGamerProfileParams struct {
@@ -84,11 +517,15 @@ type UpsertGamerProfileParams struct {
 	StudentNumber        string
 	MembershipTier       int32
 	Banned               sql.NullBool
-	Notes                sql.NullString
 	CreatedAt            sql.NullTime
 	MembershipExpiryDate sql.NullTime
+	Email                sql.NullString
+	DiscordHandle        sql.NullString
+	MarketingConsent     bool
+	NotificationConsent  bool
 }
 
+// Deleted profiles are left alone. They are brought back by RestoreGamerProfile.
 func (q *Queries) UpsertGamerProfile(ctx context.Context, arg UpsertGamerProfileParams) (GamerProfile, error) {
 	row := q.db.QueryRowContext(ctx, upsertGamerProfile,
 		arg.FirstName,
@@ -96,9 +533,12 @@ func (q *Queries) UpsertGamerProfile(ctx context.Context, arg UpsertGamerProfile
 		arg.StudentNumber,
 		arg.MembershipTier,
 		arg.Banned,
-		arg.Notes,
 		arg.CreatedAt,
 		arg.MembershipExpiryDate,
+		arg.Email,
+		arg.DiscordHandle,
+		arg.MarketingConsent,
+		arg.NotificationConsent,
 	)
 	var i GamerProfile
 	err := row.Scan(
@@ -111,6 +551,12 @@ func (q *Queries) UpsertGamerProfile(ctx context.Context, arg UpsertGamerProfile
 		&i.CreatedAt,
 		&i.ID,
 		&i.MembershipExpiryDate,
+		&i.DeletedAt,
+		&i.ErasedAt,
+		&i.Email,
+		&i.DiscordHandle,
+		&i.MarketingConsent,
+		&i.NotificationConsent,
 	)
 	return i, err
 }
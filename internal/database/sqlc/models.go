@@ -6,28 +6,79 @@ package sqlc
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
 type Application struct {
-	ID         uuid.UUID
-	AppName    string
-	KeyID      string
-	HashedKey  []byte
-	CreatedAt  time.Time
-	LastUsedAt sql.NullTime
+	ID                   uuid.UUID
+	AppName              string
+	KeyID                string
+	HashedKey            []byte
+	CreatedAt            time.Time
+	LastUsedAt           sql.NullTime
+	Scopes               []string
+	ExpiresAt            sql.NullTime
+	RevokedAt            sql.NullTime
+	PreviousHashedKey    []byte
+	PreviousKeyExpiresAt sql.NullTime
+	RateLimitPerMinute   sql.NullInt32
+	RateLimitBurst       sql.NullInt32
+}
+
+type AuditLog struct {
+	ID          uuid.UUID
+	ActorApp    sql.NullString
+	Method      string
+	Route       string
+	Action      string
+	EntityType  string
+	EntityID    string
+	Before      json.RawMessage
+	After       json.RawMessage
+	CreatedAt   time.Time
+	ActorExecID uuid.NullUUID
+}
+
+type Ban struct {
+	ID             uuid.UUID
+	StudentNumber  string
+	Reason         string
+	StartsAt       time.Time
+	EndsAt         sql.NullTime
+	IssuedByApp    sql.NullString
+	IssuedByExecID uuid.NullUUID
+	LiftedAt       sql.NullTime
+	LiftedByApp    sql.NullString
+	LiftedByExecID uuid.NullUUID
+	LiftReason     sql.NullString
+	CreatedAt      time.Time
+}
+
+type Exec struct {
+	ID           uuid.UUID
+	Name         string
+	Email        string
+	Role         string
+	PasswordHash string
+	Active       bool
+	CreatedAt    time.Time
 }
 
 type GamerActivity struct {
-	StudentNumber string
-	PcNumber      sql.NullInt32
-	Game          sql.NullString
-	StartedAt     sql.NullTime
-	EndedAt       sql.NullTime
-	ExecName      sql.NullString
-	ID            uuid.UUID
+	StudentNumber    string
+	PcNumber         sql.NullInt32
+	Game             sql.NullString
+	StartedAt        sql.NullTime
+	EndedAt          sql.NullTime
+	ExecName         sql.NullString
+	ID               uuid.UUID
+	ExecID           uuid.NullUUID
+	OverdueFlaggedAt sql.NullTime
+	ExpiresAt        sql.NullTime
+	EndedBySystem    bool
 }
 
 type GamerProfile struct {
@@ -40,4 +91,75 @@ type GamerProfile struct {
 	CreatedAt            sql.NullTime
 	ID                   uuid.NullUUID
 	MembershipExpiryDate sql.NullTime
+	DeletedAt            sql.NullTime
+	ErasedAt             sql.NullTime
+	Email                sql.NullString
+	DiscordHandle        sql.NullString
+	MarketingConsent     bool
+	NotificationConsent  bool
+}
+
+type MemberNote struct {
+	ID               uuid.UUID
+	StudentNumber    string
+	Body             string
+	Category         string
+	Pinned           bool
+	AuthorApp        sql.NullString
+	AuthorExecID     uuid.NullUUID
+	CreatedAt        time.Time
+	ArchivedAt       sql.NullTime
+	ArchivedByApp    sql.NullString
+	ArchivedByExecID uuid.NullUUID
+}
+
+type Membership struct {
+	ID               uuid.UUID
+	StudentNumber    string
+	MembershipTier   int32
+	Source           string
+	PurchasedAt      time.Time
+	StartsAt         time.Time
+	ExpiresAt        sql.NullTime
+	RecordedByApp    sql.NullString
+	RecordedByExecID uuid.NullUUID
+	CreatedAt        time.Time
+	ExternalRef      sql.NullString
+}
+
+type Reservation struct {
+	ID          uuid.UUID
+	TeamName    sql.NullString
+	StartsAt    time.Time
+	EndsAt      time.Time
+	Status      string
+	Note        sql.NullString
+	CreatedAt   time.Time
+	CheckedInAt sql.NullTime
+	CancelledAt sql.NullTime
+	ReleasedAt  sql.NullTime
+}
+
+type ReservationMember struct {
+	ReservationID uuid.UUID
+	StudentNumber string
+}
+
+type ReservationStation struct {
+	ReservationID uuid.UUID
+	StationNumber int32
+	StartsAt      time.Time
+	EndsAt        time.Time
+	Active        bool
+}
+
+type Station struct {
+	Number       int32
+	Zone         sql.NullString
+	Type         string
+	Specs        json.RawMessage
+	Status       string
+	StatusReason sql.NullString
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
@@ -0,0 +1,151 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/ubcesports/echo-base/internal/database/sqlc"
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/interfaces/station"
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+type StationRepository struct {
+	db *sql.DB
+}
+
+func NewStationRepository(db *sql.DB) station.StationRepository {
+	return &StationRepository{db: db}
+}
+
+func (r *StationRepository) Create(ctx context.Context, s *models.Station) (*models.Station, error) {
+	specs, err := json.Marshal(s.Specs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode station specs: %w", err)
+	}
+
+	queries := sqlc.New(r.db)
+	row, err := queries.CreateStation(ctx, sqlc.CreateStationParams{
+		Number:       int32(s.Number),
+		Zone:         nullString(s.Zone),
+		Type:         string(s.Type),
+		Specs:        specs,
+		Status:       string(s.Status),
+		StatusReason: nullString(s.StatusReason),
+	})
+	if isUniqueViolation(err) {
+		return nil, errors.NewConflictError(fmt.Sprintf("station %d already exists", s.Number))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create station: %w", err)
+	}
+
+	return toStation(row)
+}
+
+func (r *StationRepository) Get(ctx context.Context, number int) (*models.Station, error) {
+	queries := sqlc.New(r.db)
+	row, err := queries.GetStation(ctx, int32(number))
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("station", strconv.Itoa(number))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get station: %w", err)
+	}
+
+	return toStation(row)
+}
+
+func (r *StationRepository) List(ctx context.Context) ([]models.Station, error) {
+	queries := sqlc.New(r.db)
+	rows, err := queries.ListStations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stations: %w", err)
+	}
+
+	stations := make([]models.Station, len(rows))
+	for i, row := range rows {
+		s, err := toStation(row)
+		if err != nil {
+			return nil, err
+		}
+		stations[i] = *s
+	}
+	return stations, nil
+}
+
+func (r *StationRepository) Update(ctx context.Context, s *models.Station) (*models.Station, error) {
+	specs, err := json.Marshal(s.Specs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode station specs: %w", err)
+	}
+
+	queries := sqlc.New(r.db)
+	row, err := queries.UpdateStation(ctx, sqlc.UpdateStationParams{
+		Number:       int32(s.Number),
+		Zone:         nullString(s.Zone),
+		Type:         string(s.Type),
+		Specs:        specs,
+		Status:       string(s.Status),
+		StatusReason: nullString(s.StatusReason),
+	})
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFoundError("station", strconv.Itoa(s.Number))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update station: %w", err)
+	}
+
+	return toStation(row)
+}
+
+func (r *StationRepository) Delete(ctx context.Context, number int) error {
+	queries := sqlc.New(r.db)
+	deleted, err := queries.DeleteStation(ctx, int32(number))
+	if isForeignKeyViolation(err) {
+		return errors.NewConflictError(fmt.Sprintf("station %d has reservations, retire it instead", number))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete station: %w", err)
+	}
+	if deleted == 0 {
+		return errors.NewNotFoundError("station", strconv.Itoa(number))
+	}
+	return nil
+}
+
+// HasSessions reports whether a session was ever played on the station
+func (r *StationRepository) HasSessions(ctx context.Context, number int) (bool, error) {
+	queries := sqlc.New(r.db)
+	exists, err := queries.StationHasSessions(ctx, sql.NullInt32{Int32: int32(number), Valid: true})
+	if err != nil {
+		return false, fmt.Errorf("failed to check station sessions: %w", err)
+	}
+	return exists, nil
+}
+
+func toStation(row sqlc.Station) (*models.Station, error) {
+	s := &models.Station{
+		Number:    int(row.Number),
+		Type:      models.StationType(row.Type),
+		Status:    models.StationStatus(row.Status),
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+	if err := json.Unmarshal(row.Specs, &s.Specs); err != nil {
+		return nil, fmt.Errorf("failed to decode station specs: %w", err)
+	}
+	if s.Specs == nil {
+		s.Specs = map[string]string{}
+	}
+	if row.Zone.Valid {
+		s.Zone = &row.Zone.String
+	}
+	if row.StatusReason.Valid {
+		s.StatusReason = &row.StatusReason.String
+	}
+	return s, nil
+}
@@ -1,6 +1,9 @@
 package errors
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 type ValidationError struct {
 	Field   string
@@ -45,3 +48,39 @@ func (e *ForbiddenError) Error() string {
 func NewForbiddenError(message string) error {
 	return &ForbiddenError{Message: message}
 }
+
+type ConflictError struct {
+	Message string
+}
+
+func (e *ConflictError) Error() string {
+	return e.Message
+}
+
+func NewConflictError(message string) error {
+	return &ConflictError{Message: message}
+}
+
+type UnauthorizedError struct {
+	Message string
+}
+
+func (e *UnauthorizedError) Error() string {
+	return e.Message
+}
+
+func NewUnauthorizedError(message string) error {
+	return &UnauthorizedError{Message: message}
+}
+
+type LockedOutError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedOutError) Error() string {
+	return fmt.Sprintf("too many failed attempts, retry after %s", e.RetryAfter)
+}
+
+func NewLockedOutError(retryAfter time.Duration) error {
+	return &LockedOutError{RetryAfter: retryAfter}
+}
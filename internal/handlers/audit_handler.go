@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/models"
+	"github.com/ubcesports/echo-base/internal/services"
+)
+
+func ListAuditEntries(service services.AuditService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := r.URL.Query()
+		filter := models.AuditFilter{
+			EntityType: query.Get("entity_type"),
+			EntityID:   query.Get("entity_id"),
+			ActorApp:   query.Get("actor"),
+			Page:       1,
+			Limit:      20,
+		}
+
+		if pageStr := query.Get("page"); pageStr != "" {
+			var err error
+			filter.Page, err = strconv.Atoi(pageStr)
+			if err != nil {
+				http.Error(w, "Invalid page parameter", http.StatusBadRequest)
+				return
+			}
+		}
+
+		if limitStr := query.Get("limit"); limitStr != "" {
+			var err error
+			filter.Limit, err = strconv.Atoi(limitStr)
+			if err != nil {
+				http.Error(w, "Invalid limit parameter", http.StatusBadRequest)
+				return
+			}
+		}
+
+		var err error
+		if filter.From, err = parseTimeParam(query.Get("from")); err != nil {
+			http.Error(w, "Invalid from parameter, use RFC 3339 or YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		if filter.To, err = parseTimeParam(query.Get("to")); err != nil {
+			http.Error(w, "Invalid to parameter, use RFC 3339 or YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+
+		entries, err := service.ListEntries(r.Context(), filter)
+		if err != nil {
+			var validationErr *errors.ValidationError
+
+			if goerrors.As(err, &validationErr) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if entries == nil {
+			entries = []models.AuditEntry{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(entries)
+	})
+}
+
+// parseTimeParam accepts an RFC 3339 timestamp or a UTC date. An empty value
+// returns nil.
+func parseTimeParam(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -2,13 +2,41 @@ package handlers
 
 import (
 	"encoding/json"
+	goerrors "errors"
 	"net/http"
+	"time"
 
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
 	"github.com/ubcesports/echo-base/internal/services"
 )
 
 type GenerateKeyRequest struct {
-	AppName string `json:"app_name"`
+	AppName      string          `json:"app_name"`
+	Scopes       []auth.Scope    `json:"scopes"`
+	ExpiresAt    *time.Time      `json:"expires_at,omitempty"`
+	RateLimit    *auth.RateLimit `json:"rate_limit,omitempty"`
+	AuthMode     auth.AuthMode   `json:"auth_mode,omitempty"`
+	AllowedCIDRs []string        `json:"allowed_cidrs,omitempty"`
+}
+
+type UpdateAuthModeRequest struct {
+	AuthMode auth.AuthMode `json:"auth_mode"`
+}
+
+// UpdateAuthModeResponse carries the signing key a client switched to HMAC
+// mode must sign requests with from now on
+type UpdateAuthModeResponse struct {
+	AuthMode   auth.AuthMode `json:"auth_mode"`
+	SigningKey string        `json:"signing_key,omitempty"`
+}
+
+type UpdateAllowedCIDRsRequest struct {
+	AllowedCIDRs []string `json:"allowed_cidrs"`
+}
+
+type RotateKeyRequest struct {
+	OverlapHours *int `json:"overlap_hours,omitempty"`
 }
 
 func GenerateAPIKey(authService services.AuthService) http.Handler {
@@ -25,8 +53,19 @@ func GenerateAPIKey(authService services.AuthService) http.Handler {
 				return
 			}
 
-			apiKey, err := authService.GenerateAPIKey(r.Context(), req.AppName)
+			apiKey, err := authService.GenerateAPIKey(r.Context(), req.AppName, auth.KeyOptions{
+				Scopes:       req.Scopes,
+				ExpiresAt:    req.ExpiresAt,
+				RateLimit:    req.RateLimit,
+				AuthMode:     req.AuthMode,
+				AllowedCIDRs: req.AllowedCIDRs,
+			})
 			if err != nil {
+				var validationErr *errors.ValidationError
+				if goerrors.As(err, &validationErr) {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
 				http.Error(w, "Error generating API key", http.StatusInternalServerError)
 				return
 			}
@@ -36,3 +75,190 @@ func GenerateAPIKey(authService services.AuthService) http.Handler {
 		},
 	)
 }
+
+func ListAPIKeys(authService services.AuthService) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+
+			keys, err := authService.ListAPIKeys(r.Context())
+			if err != nil {
+				http.Error(w, "Error listing API keys", http.StatusInternalServerError)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(keys)
+		},
+	)
+}
+
+func RevokeAPIKey(authService services.AuthService) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+
+			keyId := r.PathValue("key_id")
+			if err := authService.RevokeAPIKey(r.Context(), keyId); err != nil {
+				writeKeyError(w, err, "Error revoking API key")
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("API key revoked successfully"))
+		},
+	)
+}
+
+func RotateAPIKey(authService services.AuthService) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+
+			var req RotateKeyRequest
+			if r.ContentLength != 0 {
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					http.Error(w, "Invalid request body", http.StatusBadRequest)
+					return
+				}
+			}
+
+			overlap := services.DefaultRotationOverlap
+			if req.OverlapHours != nil {
+				overlap = time.Duration(*req.OverlapHours) * time.Hour
+			}
+
+			keyId := r.PathValue("key_id")
+			apiKey, err := authService.RotateAPIKey(r.Context(), keyId, overlap)
+			if err != nil {
+				writeKeyError(w, err, "Error rotating API key")
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(apiKey)
+		},
+	)
+}
+
+func UpdateAPIKeyRateLimit(authService services.AuthService) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPut {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+
+			var req auth.RateLimit
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "Invalid request body", http.StatusBadRequest)
+				return
+			}
+
+			keyId := r.PathValue("key_id")
+			if err := authService.UpdateRateLimit(r.Context(), keyId, &req); err != nil {
+				writeKeyError(w, err, "Error updating rate limit")
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(req)
+		},
+	)
+}
+
+func ResetAPIKeyRateLimit(authService services.AuthService) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodDelete {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+
+			keyId := r.PathValue("key_id")
+			if err := authService.UpdateRateLimit(r.Context(), keyId, nil); err != nil {
+				writeKeyError(w, err, "Error resetting rate limit")
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("Rate limit reset to default"))
+		},
+	)
+}
+
+func UpdateAPIKeyAuthMode(authService services.AuthService) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPut {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+
+			var req UpdateAuthModeRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "Invalid request body", http.StatusBadRequest)
+				return
+			}
+
+			keyId := r.PathValue("key_id")
+			signingKey, err := authService.UpdateAuthMode(r.Context(), keyId, req.AuthMode)
+			if err != nil {
+				writeKeyError(w, err, "Error updating auth mode")
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(UpdateAuthModeResponse{AuthMode: req.AuthMode, SigningKey: signingKey})
+		},
+	)
+}
+
+func UpdateAPIKeyAllowedCIDRs(authService services.AuthService) http.Handler {
+	return http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPut {
+				http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+				return
+			}
+
+			var req UpdateAllowedCIDRsRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "Invalid request body", http.StatusBadRequest)
+				return
+			}
+
+			keyId := r.PathValue("key_id")
+			if err := authService.UpdateAllowedCIDRs(r.Context(), keyId, req.AllowedCIDRs); err != nil {
+				writeKeyError(w, err, "Error updating allowed CIDRs")
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(req)
+		},
+	)
+}
+
+func writeKeyError(w http.ResponseWriter, err error, message string) {
+	var validationErr *errors.ValidationError
+	var notFoundErr *errors.NotFoundError
+	if goerrors.As(err, &validationErr) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if goerrors.As(err, &notFoundErr) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	http.Error(w, message, http.StatusInternalServerError)
+}
@@ -8,23 +8,55 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	apperrors "github.com/ubcesports/echo-base/internal/errors"
 	"github.com/ubcesports/echo-base/internal/interfaces/auth"
 )
 
 // MockAuthService implements services.AuthService for testing
 type MockAuthService struct {
-	GenerateAPIKeyFunc func(ctx context.Context, appName string) (*auth.APIKey, error)
+	GenerateAPIKeyFunc func(ctx context.Context, appName string, opts auth.KeyOptions) (*auth.APIKey, error)
+	RevokeAPIKeyFunc   func(ctx context.Context, keyId string) error
+	RotateAPIKeyFunc   func(ctx context.Context, keyId string, overlap time.Duration) (*auth.APIKey, error)
 }
 
-func (m *MockAuthService) GenerateAPIKey(ctx context.Context, appName string) (*auth.APIKey, error) {
-	return m.GenerateAPIKeyFunc(ctx, appName)
+func (m *MockAuthService) GenerateAPIKey(ctx context.Context, appName string, opts auth.KeyOptions) (*auth.APIKey, error) {
+	return m.GenerateAPIKeyFunc(ctx, appName, opts)
 }
 
-func (m *MockAuthService) ValidateAPIKey(ctx context.Context, apiKey string) (string, error) {
+func (m *MockAuthService) ListAPIKeys(ctx context.Context) ([]auth.KeyInfo, error) {
+	return nil, nil
+}
+
+func (m *MockAuthService) RevokeAPIKey(ctx context.Context, keyId string) error {
+	return m.RevokeAPIKeyFunc(ctx, keyId)
+}
+
+func (m *MockAuthService) UpdateRateLimit(ctx context.Context, keyId string, limit *auth.RateLimit) error {
+	return nil
+}
+
+func (m *MockAuthService) RotateAPIKey(ctx context.Context, keyId string, overlap time.Duration) (*auth.APIKey, error) {
+	return m.RotateAPIKeyFunc(ctx, keyId, overlap)
+}
+
+func (m *MockAuthService) UpdateAuthMode(ctx context.Context, keyId string, mode auth.AuthMode) (string, error) {
 	return "", nil
 }
 
+func (m *MockAuthService) UpdateAllowedCIDRs(ctx context.Context, keyId string, cidrs []string) error {
+	return nil
+}
+
+func (m *MockAuthService) ValidateAPIKey(ctx context.Context, apiKey string, clientIP string) (*auth.Application, error) {
+	return nil, nil
+}
+
+func (m *MockAuthService) ValidateSignedRequest(ctx context.Context, req *auth.SignedRequest, clientIP string, hashBody func() (string, error)) (*auth.Application, error) {
+	return nil, nil
+}
+
 func TestGenerateAPIKeyHandler(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -38,7 +70,7 @@ func TestGenerateAPIKeyHandler(t *testing.T) {
 		{
 			name:           "valid POST",
 			method:         http.MethodPost,
-			body:           GenerateKeyRequest{AppName: "testapp"},
+			body:           GenerateKeyRequest{AppName: "testapp", Scopes: []auth.Scope{auth.ScopeActivityWrite}},
 			mockReturn:     &auth.APIKey{KeyId: "abc123", APIKey: "api_abc123.secret", AppName: "testapp"},
 			wantStatus:     http.StatusOK,
 			wantBodySubstr: `"api_abc123.secret"`,
@@ -57,6 +89,14 @@ func TestGenerateAPIKeyHandler(t *testing.T) {
 			wantStatus:     http.StatusBadRequest,
 			wantBodySubstr: "Invalid request body",
 		},
+		{
+			name:           "validation error",
+			method:         http.MethodPost,
+			body:           GenerateKeyRequest{AppName: "testapp"},
+			mockErr:        apperrors.NewValidationError("scopes", "at least one scope is required"),
+			wantStatus:     http.StatusBadRequest,
+			wantBodySubstr: "scopes",
+		},
 		{
 			name:           "service error",
 			method:         http.MethodPost,
@@ -70,7 +110,7 @@ func TestGenerateAPIKeyHandler(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mockService := &MockAuthService{
-				GenerateAPIKeyFunc: func(ctx context.Context, appName string) (*auth.APIKey, error) {
+				GenerateAPIKeyFunc: func(ctx context.Context, appName string, opts auth.KeyOptions) (*auth.APIKey, error) {
 					return tt.mockReturn, tt.mockErr
 				},
 			}
@@ -100,3 +140,80 @@ func TestGenerateAPIKeyHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestRevokeAPIKeyHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		mockErr    error
+		wantStatus int
+	}{
+		{"revoked", nil, http.StatusOK},
+		{"unknown key", apperrors.NewNotFoundError("api key", "abc123"), http.StatusNotFound},
+		{"service error", errors.New("service error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotKeyId string
+			mockService := &MockAuthService{
+				RevokeAPIKeyFunc: func(ctx context.Context, keyId string) error {
+					gotKeyId = keyId
+					return tt.mockErr
+				},
+			}
+
+			mux := http.NewServeMux()
+			mux.Handle("POST /admin/keys/{key_id}/revoke", RevokeAPIKey(mockService))
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/keys/abc123/revoke", nil)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("got status %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if gotKeyId != "abc123" {
+				t.Errorf("got key id %q, want %q", gotKeyId, "abc123")
+			}
+		})
+	}
+}
+
+func TestRotateAPIKeyHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantOverlap time.Duration
+		wantStatus  int
+	}{
+		{"default overlap", "", 24 * time.Hour, http.StatusOK},
+		{"custom overlap", `{"overlap_hours": 2}`, 2 * time.Hour, http.StatusOK},
+		{"invalid JSON", "{invalid-json}", 0, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotOverlap time.Duration
+			mockService := &MockAuthService{
+				RotateAPIKeyFunc: func(ctx context.Context, keyId string, overlap time.Duration) (*auth.APIKey, error) {
+					gotOverlap = overlap
+					return &auth.APIKey{KeyId: keyId, APIKey: "api_abc123.newsecret"}, nil
+				},
+			}
+
+			mux := http.NewServeMux()
+			mux.Handle("POST /admin/keys/{key_id}/rotate", RotateAPIKey(mockService))
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/keys/abc123/rotate", bytes.NewReader([]byte(tt.body)))
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("got status %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK && gotOverlap != tt.wantOverlap {
+				t.Errorf("got overlap %v, want %v", gotOverlap, tt.wantOverlap)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"io"
+	"net/http"
+
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/models"
+	"github.com/ubcesports/echo-base/internal/services"
+)
+
+func IssueBan(service services.GamerProfileService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req models.IssueBanRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		ban, err := service.IssueBan(r.Context(), r.PathValue("student_number"), &req)
+		if err != nil {
+			writeBanError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(ban)
+	})
+}
+
+// LiftBan ends a ban early. The body, with an optional reason, may be left
+// out.
+func LiftBan(service services.GamerProfileService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req models.LiftBanRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		ban, err := service.LiftBan(r.Context(), r.PathValue("student_number"), r.PathValue("ban_id"), &req)
+		if err != nil {
+			writeBanError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(ban)
+	})
+}
+
+func ListBans(service services.GamerProfileService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		bans, err := service.ListBans(r.Context(), r.PathValue("student_number"))
+		if err != nil {
+			writeBanError(w, err)
+			return
+		}
+
+		if bans == nil {
+			bans = []models.Ban{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(bans)
+	})
+}
+
+func writeBanError(w http.ResponseWriter, err error) {
+	var validationErr *errors.ValidationError
+	var notFoundErr *errors.NotFoundError
+	if goerrors.As(err, &validationErr) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if goerrors.As(err, &notFoundErr) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/middleware"
+	"github.com/ubcesports/echo-base/internal/models"
+	"github.com/ubcesports/echo-base/internal/services"
+)
+
+func ExecLogin(service services.ExecService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req models.ExecLoginRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		token, err := service.Login(r.Context(), &req, middleware.ClientIP(r))
+		if err != nil {
+			writeExecError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(token)
+	})
+}
+
+func CreateExec(service services.ExecService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req models.CreateExecRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		exec, err := service.CreateExec(r.Context(), &req)
+		if err != nil {
+			writeExecError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(exec)
+	})
+}
+
+func ListExecs(service services.ExecService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		execs, err := service.ListExecs(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(execs)
+	})
+}
+
+func UpdateExec(service services.ExecService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req models.UpdateExecRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		exec, err := service.UpdateExec(r.Context(), r.PathValue("exec_id"), &req)
+		if err != nil {
+			writeExecError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(exec)
+	})
+}
+
+func writeExecError(w http.ResponseWriter, err error) {
+	var validationErr *errors.ValidationError
+	var notFoundErr *errors.NotFoundError
+	var unauthorizedErr *errors.UnauthorizedError
+	var lockedOutErr *errors.LockedOutError
+	if goerrors.As(err, &validationErr) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if goerrors.As(err, &notFoundErr) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if goerrors.As(err, &unauthorizedErr) {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+	if goerrors.As(err, &lockedOutErr) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(lockedOutErr.RetryAfter.Seconds()))))
+		http.Error(w, "Too many failed login attempts", http.StatusTooManyRequests)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/services"
+)
+
+// ExportGamerProfile returns everything stored about a member as JSON, or
+// as a zip of CSV files with ?format=zip
+func ExportGamerProfile(service services.MemberExportService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		format := r.URL.Query().Get("format")
+		if format != "" && format != "json" && format != "zip" {
+			http.Error(w, "Invalid format, use json or zip", http.StatusBadRequest)
+			return
+		}
+
+		studentNumber := r.PathValue("student_number")
+		export, err := service.ExportMember(r.Context(), studentNumber)
+		if err != nil {
+			var notFoundErr *errors.NotFoundError
+			var validationErr *errors.ValidationError
+
+			if goerrors.As(err, &notFoundErr) {
+				http.Error(w, "Student not found", http.StatusNotFound)
+				return
+			}
+			if goerrors.As(err, &validationErr) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if format == "zip" {
+			var buf bytes.Buffer
+			if err := services.WriteMemberExportZip(&buf, export); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/zip")
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "member-"+studentNumber+".zip"))
+			w.WriteHeader(http.StatusOK)
+			w.Write(buf.Bytes())
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(export)
+	})
+}
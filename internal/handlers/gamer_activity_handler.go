@@ -70,6 +70,36 @@ func GetTodayActivityByStudent(service services.GamerActivityService) http.Handl
 	})
 }
 
+func GetPlayQuota(service services.GamerActivityService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		quota, err := service.GetQuota(r.Context(), r.PathValue("student_number"))
+		if err != nil {
+			var validationErr *errors.ValidationError
+			var notFoundErr *errors.NotFoundError
+
+			if goerrors.As(err, &notFoundErr) {
+				http.Error(w, err.Error(), http.StatusNotFound)
+				return
+			}
+			if goerrors.As(err, &validationErr) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(quota)
+	})
+}
+
 func GetRecentActivities(service services.GamerActivityService) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
@@ -139,6 +169,7 @@ func StartActivity(service services.GamerActivityService) http.Handler {
 			var validationErr *errors.ValidationError
 			var notFoundErr *errors.NotFoundError
 			var forbiddenErr *errors.ForbiddenError
+			var conflictErr *errors.ConflictError
 
 			if goerrors.As(err, &notFoundErr) {
 				http.Error(w, "Foreign key "+req.StudentNumber+" not found.", http.StatusNotFound)
@@ -148,6 +179,10 @@ func StartActivity(service services.GamerActivityService) http.Handler {
 				http.Error(w, err.Error(), http.StatusForbidden)
 				return
 			}
+			if goerrors.As(err, &conflictErr) {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
 			if goerrors.As(err, &validationErr) {
 				http.Error(w, err.Error(), http.StatusBadRequest)
 				return
@@ -180,11 +215,16 @@ func EndActivity(service services.GamerActivityService) http.Handler {
 		activity, err := service.EndActivity(r.Context(), studentNumber, &req)
 		if err != nil {
 			var validationErr *errors.ValidationError
+			var unauthorizedErr *errors.UnauthorizedError
 
 			if goerrors.As(err, &validationErr) {
 				http.Error(w, err.Error(), http.StatusBadRequest)
 				return
 			}
+			if goerrors.As(err, &unauthorizedErr) {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
 			if activity == nil {
 				http.Error(w, "Student not active.", http.StatusNotFound)
 				return
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	goerrors "errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/ubcesports/echo-base/internal/errors"
 	"github.com/ubcesports/echo-base/internal/models"
@@ -42,6 +44,96 @@ func GetGamerProfile(service services.GamerProfileService) http.Handler {
 	})
 }
 
+func ListGamerProfiles(service services.GamerProfileService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := r.URL.Query()
+		filter := models.ProfileFilter{
+			Search: query.Get("search"),
+			Page:   1,
+			Limit:  20,
+		}
+
+		if pageStr := query.Get("page"); pageStr != "" {
+			var err error
+			filter.Page, err = strconv.Atoi(pageStr)
+			if err != nil {
+				http.Error(w, "Invalid page parameter", http.StatusBadRequest)
+				return
+			}
+		}
+
+		if limitStr := query.Get("limit"); limitStr != "" {
+			var err error
+			filter.Limit, err = strconv.Atoi(limitStr)
+			if err != nil {
+				http.Error(w, "Invalid limit parameter", http.StatusBadRequest)
+				return
+			}
+		}
+
+		if tierStr := query.Get("membership_tier"); tierStr != "" {
+			tier, err := strconv.Atoi(tierStr)
+			if err != nil {
+				http.Error(w, "Invalid membership_tier parameter", http.StatusBadRequest)
+				return
+			}
+			filter.MembershipTier = &tier
+		}
+
+		if bannedStr := query.Get("banned"); bannedStr != "" {
+			banned, err := strconv.ParseBool(bannedStr)
+			if err != nil {
+				http.Error(w, "Invalid banned parameter, use true or false", http.StatusBadRequest)
+				return
+			}
+			filter.Banned = &banned
+		}
+
+		timeParams := []struct {
+			name string
+			dest **time.Time
+		}{
+			{"expires_before", &filter.ExpiresBefore},
+			{"expires_after", &filter.ExpiresAfter},
+			{"created_from", &filter.CreatedFrom},
+			{"created_to", &filter.CreatedTo},
+		}
+		for _, param := range timeParams {
+			t, err := parseTimeParam(query.Get(param.name))
+			if err != nil {
+				http.Error(w, "Invalid "+param.name+" parameter, use RFC 3339 or YYYY-MM-DD", http.StatusBadRequest)
+				return
+			}
+			*param.dest = t
+		}
+
+		page, err := service.ListProfiles(r.Context(), filter)
+		if err != nil {
+			var validationErr *errors.ValidationError
+
+			if goerrors.As(err, &validationErr) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		if page.Profiles == nil {
+			page.Profiles = []models.GamerProfile{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(page)
+	})
+}
+
 func CreateOrUpdateGamerProfile(service services.GamerProfileService) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
@@ -58,11 +150,16 @@ func CreateOrUpdateGamerProfile(service services.GamerProfileService) http.Handl
 		profile, err := service.CreateOrUpdateProfile(r.Context(), &req)
 		if err != nil {
 			var validationErr *errors.ValidationError
+			var conflictErr *errors.ConflictError
 
 			if goerrors.As(err, &validationErr) {
 				http.Error(w, err.Error(), http.StatusBadRequest)
 				return
 			}
+			if goerrors.As(err, &conflictErr) {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
@@ -73,6 +170,43 @@ func CreateOrUpdateGamerProfile(service services.GamerProfileService) http.Handl
 	})
 }
 
+// UpdateGamerProfile applies a JSON merge patch to a profile
+func UpdateGamerProfile(service services.GamerProfileService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req models.UpdateGamerProfileRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		profile, err := service.UpdateProfile(r.Context(), r.PathValue("student_number"), &req)
+		if err != nil {
+			var notFoundErr *errors.NotFoundError
+			var validationErr *errors.ValidationError
+
+			if goerrors.As(err, &notFoundErr) {
+				http.Error(w, "Student not found", http.StatusNotFound)
+				return
+			}
+			if goerrors.As(err, &validationErr) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(profile)
+	})
+}
+
 func DeleteGamerProfile(service services.GamerProfileService) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodDelete {
@@ -103,3 +237,64 @@ func DeleteGamerProfile(service services.GamerProfileService) http.Handler {
 		w.Write([]byte("Gamer profile deleted successfully"))
 	})
 }
+
+func RestoreGamerProfile(service services.GamerProfileService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		profile, err := service.RestoreProfile(r.Context(), r.PathValue("student_number"))
+		if err != nil {
+			var notFoundErr *errors.NotFoundError
+			var validationErr *errors.ValidationError
+
+			if goerrors.As(err, &notFoundErr) {
+				http.Error(w, "Deleted student not found", http.StatusNotFound)
+				return
+			}
+			if goerrors.As(err, &validationErr) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(profile)
+	})
+}
+
+// EraseGamerProfile anonymizes a profile. Unlike a delete this can't be
+// undone.
+func EraseGamerProfile(service services.GamerProfileService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		err := service.EraseProfile(r.Context(), r.PathValue("student_number"))
+		if err != nil {
+			var notFoundErr *errors.NotFoundError
+			var validationErr *errors.ValidationError
+
+			if goerrors.As(err, &notFoundErr) {
+				http.Error(w, "Student not found", http.StatusNotFound)
+				return
+			}
+			if goerrors.As(err, &validationErr) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("Gamer profile erased successfully"))
+	})
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/models"
+	"github.com/ubcesports/echo-base/internal/services"
+)
+
+// MaxImportSize is the largest CSV upload accepted, comfortably more than a
+// year of Showpass purchases
+const MaxImportSize = 10 << 20
+
+// ImportGamerProfiles registers members from a CSV uploaded as the "file"
+// part of a multipart form. The optional "columns" and "tiers" parts are
+// JSON objects overriding the Showpass column mapping and ticket types, and
+// "dry_run" and "atomic" are booleans.
+func ImportGamerProfiles(service services.GamerProfileService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, MaxImportSize)
+		if err := r.ParseMultipartForm(MaxImportSize); err != nil {
+			http.Error(w, "Invalid multipart form, upload the CSV as file", http.StatusBadRequest)
+			return
+		}
+
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "Missing file", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+
+		var opts models.ImportOptions
+		if columns := r.FormValue("columns"); columns != "" {
+			if err := json.Unmarshal([]byte(columns), &opts.Columns); err != nil {
+				http.Error(w, "Invalid columns, use a JSON object of field to header", http.StatusBadRequest)
+				return
+			}
+		}
+
+		if tiers := r.FormValue("tiers"); tiers != "" {
+			if err := json.Unmarshal([]byte(tiers), &opts.Tiers); err != nil {
+				http.Error(w, "Invalid tiers, use a JSON object of ticket type to tier", http.StatusBadRequest)
+				return
+			}
+		}
+
+		flags := []struct {
+			name string
+			dest *bool
+		}{
+			{"dry_run", &opts.DryRun},
+			{"atomic", &opts.Atomic},
+		}
+		for _, flag := range flags {
+			value := r.FormValue(flag.name)
+			if value == "" {
+				continue
+			}
+			*flag.dest, err = strconv.ParseBool(value)
+			if err != nil {
+				http.Error(w, "Invalid "+flag.name+", use true or false", http.StatusBadRequest)
+				return
+			}
+		}
+
+		report, err := service.ImportProfiles(r.Context(), file, opts)
+		if err != nil {
+			var validationErr *errors.ValidationError
+
+			if goerrors.As(err, &validationErr) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(report)
+	})
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"net/http"
+
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/models"
+	"github.com/ubcesports/echo-base/internal/services"
+)
+
+func RenewMembership(service services.GamerProfileService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req models.RenewMembershipRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		membership, err := service.RenewMembership(r.Context(), r.PathValue("student_number"), &req)
+		if err != nil {
+			writeMembershipError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(membership)
+	})
+}
+
+func ListMemberships(service services.GamerProfileService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		memberships, err := service.ListMemberships(r.Context(), r.PathValue("student_number"))
+		if err != nil {
+			writeMembershipError(w, err)
+			return
+		}
+
+		if memberships == nil {
+			memberships = []models.Membership{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(memberships)
+	})
+}
+
+func writeMembershipError(w http.ResponseWriter, err error) {
+	var validationErr *errors.ValidationError
+	var notFoundErr *errors.NotFoundError
+	if goerrors.As(err, &validationErr) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if goerrors.As(err, &notFoundErr) {
+		http.Error(w, "Student not found", http.StatusNotFound)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/models"
+	"github.com/ubcesports/echo-base/internal/services"
+)
+
+func AddNote(service services.GamerProfileService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req models.CreateMemberNoteRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		note, err := service.AddNote(r.Context(), r.PathValue("student_number"), &req)
+		if err != nil {
+			writeNoteError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(note)
+	})
+}
+
+// ListNotes returns a member's notes timeline. Archived notes are included
+// with ?archived=true.
+func ListNotes(service services.GamerProfileService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		includeArchived := false
+		if value := r.URL.Query().Get("archived"); value != "" {
+			var err error
+			includeArchived, err = strconv.ParseBool(value)
+			if err != nil {
+				http.Error(w, "Invalid archived, use true or false", http.StatusBadRequest)
+				return
+			}
+		}
+
+		notes, err := service.ListNotes(r.Context(), r.PathValue("student_number"), includeArchived)
+		if err != nil {
+			writeNoteError(w, err)
+			return
+		}
+
+		if notes == nil {
+			notes = []models.MemberNote{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(notes)
+	})
+}
+
+func ArchiveNote(service services.GamerProfileService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		note, err := service.ArchiveNote(r.Context(), r.PathValue("student_number"), r.PathValue("note_id"))
+		if err != nil {
+			writeNoteError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(note)
+	})
+}
+
+func writeNoteError(w http.ResponseWriter, err error) {
+	var validationErr *errors.ValidationError
+	var notFoundErr *errors.NotFoundError
+	if goerrors.As(err, &validationErr) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if goerrors.As(err, &notFoundErr) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
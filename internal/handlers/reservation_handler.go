@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/models"
+	"github.com/ubcesports/echo-base/internal/services"
+)
+
+// ListReservations lists reservations that haven't ended yet, or those
+// overlapping from and to when given, a page at a time
+func ListReservations(service services.ReservationService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := r.URL.Query()
+		filter := models.ReservationFilter{
+			Status:        models.ReservationStatus(query.Get("status")),
+			StudentNumber: query.Get("student_number"),
+			Page:          1,
+			Limit:         20,
+		}
+
+		if pageStr := query.Get("page"); pageStr != "" {
+			var err error
+			filter.Page, err = strconv.Atoi(pageStr)
+			if err != nil {
+				http.Error(w, "Invalid page parameter", http.StatusBadRequest)
+				return
+			}
+		}
+
+		if limitStr := query.Get("limit"); limitStr != "" {
+			var err error
+			filter.Limit, err = strconv.Atoi(limitStr)
+			if err != nil {
+				http.Error(w, "Invalid limit parameter", http.StatusBadRequest)
+				return
+			}
+		}
+
+		if stationStr := query.Get("station"); stationStr != "" {
+			station, err := strconv.Atoi(stationStr)
+			if err != nil {
+				http.Error(w, "Invalid station parameter", http.StatusBadRequest)
+				return
+			}
+			filter.StationNumber = &station
+		}
+
+		var err error
+		if filter.From, err = parseTimeParam(query.Get("from")); err != nil {
+			http.Error(w, "Invalid from parameter, use RFC 3339 or YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		if filter.To, err = parseTimeParam(query.Get("to")); err != nil {
+			http.Error(w, "Invalid to parameter, use RFC 3339 or YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		if filter.From == nil {
+			now := time.Now()
+			filter.From = &now
+		}
+
+		reservations, err := service.ListReservations(r.Context(), filter)
+		if err != nil {
+			writeReservationError(w, err)
+			return
+		}
+
+		if reservations == nil {
+			reservations = []models.Reservation{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(reservations)
+	})
+}
+
+func GetReservation(service services.ReservationService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		reservation, err := service.GetReservation(r.Context(), r.PathValue("reservation_id"))
+		if err != nil {
+			writeReservationError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(reservation)
+	})
+}
+
+func CreateReservation(service services.ReservationService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req models.CreateReservationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		reservation, err := service.CreateReservation(r.Context(), &req)
+		if err != nil {
+			writeReservationError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(reservation)
+	})
+}
+
+func CancelReservation(service services.ReservationService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		reservation, err := service.CancelReservation(r.Context(), r.PathValue("reservation_id"))
+		if err != nil {
+			writeReservationError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(reservation)
+	})
+}
+
+func writeReservationError(w http.ResponseWriter, err error) {
+	var validationErr *errors.ValidationError
+	var notFoundErr *errors.NotFoundError
+	var forbiddenErr *errors.ForbiddenError
+	var conflictErr *errors.ConflictError
+	if goerrors.As(err, &validationErr) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if goerrors.As(err, &notFoundErr) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if goerrors.As(err, &forbiddenErr) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	if goerrors.As(err, &conflictErr) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
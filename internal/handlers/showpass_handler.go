@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"io"
+	"net/http"
+
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/services"
+)
+
+// MaxWebhookSize is the largest webhook body accepted
+const MaxWebhookSize = 1 << 20
+
+// ShowpassWebhook receives purchases from Showpass. It is public, so every
+// delivery must be signed with the webhook secret.
+func ShowpassWebhook(service services.ShowpassService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxWebhookSize))
+		if err != nil {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		result, err := service.HandleWebhook(r.Context(), body, r.Header.Get(services.ShowpassSignatureHeader))
+		if err != nil {
+			var unauthorizedErr *errors.UnauthorizedError
+			var validationErr *errors.ValidationError
+			var conflictErr *errors.ConflictError
+
+			if goerrors.As(err, &unauthorizedErr) {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+			if goerrors.As(err, &validationErr) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if goerrors.As(err, &conflictErr) {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(result)
+	})
+}
@@ -0,0 +1,22 @@
+package audit
+
+import "context"
+
+const routeContextKey = "route"
+
+type Route struct {
+	Method  string
+	Pattern string
+}
+
+// WithRoute stores the route handling the request on ctx so audit entries
+// can say which endpoint made a change
+func WithRoute(ctx context.Context, method, pattern string) context.Context {
+	return context.WithValue(ctx, routeContextKey, Route{Method: method, Pattern: pattern})
+}
+
+// RouteFromContext returns the route handling the request, or a zero Route if none
+func RouteFromContext(ctx context.Context) Route {
+	route, _ := ctx.Value(routeContextKey).(Route)
+	return route
+}
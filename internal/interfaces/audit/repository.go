@@ -0,0 +1,13 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+type AuditRepository interface {
+	Create(ctx context.Context, entry *models.AuditEntry) error
+	List(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error)
+	ListForStudent(ctx context.Context, studentNumber string) ([]models.AuditEntry, error)
+}
@@ -0,0 +1,52 @@
+package auth
+
+import "context"
+
+const (
+	appNameContextKey     = "appName"
+	applicationContextKey = "application"
+	execContextKey        = "exec"
+)
+
+// WithApplication stores the authenticated application on ctx
+func WithApplication(ctx context.Context, app *Application) context.Context {
+	ctx = context.WithValue(ctx, appNameContextKey, app.AppName)
+	return context.WithValue(ctx, applicationContextKey, app)
+}
+
+// WithAppName attributes changes to a caller that isn't an API key
+// application, such as a webhook
+func WithAppName(ctx context.Context, appName string) context.Context {
+	return context.WithValue(ctx, appNameContextKey, appName)
+}
+
+// ApplicationFromContext returns the authenticated application, or nil if none
+func ApplicationFromContext(ctx context.Context) *Application {
+	app, _ := ctx.Value(applicationContextKey).(*Application)
+	return app
+}
+
+// AppNameFromContext returns the authenticated application's name, or "" if none
+func AppNameFromContext(ctx context.Context) string {
+	appName, _ := ctx.Value(appNameContextKey).(string)
+	return appName
+}
+
+// ScopesFromContext returns the authenticated application's scopes, or nil if none
+func ScopesFromContext(ctx context.Context) []Scope {
+	if app := ApplicationFromContext(ctx); app != nil {
+		return app.Scopes
+	}
+	return nil
+}
+
+// WithExec stores the exec identified by an exec token on ctx
+func WithExec(ctx context.Context, exec *ExecIdentity) context.Context {
+	return context.WithValue(ctx, execContextKey, exec)
+}
+
+// ExecFromContext returns the exec making the request, or nil if none
+func ExecFromContext(ctx context.Context) *ExecIdentity {
+	exec, _ := ctx.Value(execContextKey).(*ExecIdentity)
+	return exec
+}
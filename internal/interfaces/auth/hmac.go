@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// HMACScheme is the Authorization scheme of signed requests:
+//
+//	Authorization: EB-HMAC-SHA256 KeyId=<key id>, Timestamp=<unix seconds>, Nonce=<nonce>, Signature=<hex>
+const HMACScheme = "EB-HMAC-SHA256"
+
+// SignedRequest is a request authenticated by an HMAC signature rather than
+// by presenting the API key itself
+type SignedRequest struct {
+	KeyId     string
+	Timestamp int64
+	Nonce     string
+	Method    string
+	// Path is the request URI including any query string
+	Path string
+	// BodyHash is the hex encoded SHA-256 of the request body
+	BodyHash  string
+	Signature string
+}
+
+// StringToSign is the canonical form of the request covered by the signature
+func (r *SignedRequest) StringToSign() string {
+	return strings.Join([]string{
+		HMACScheme,
+		strconv.FormatInt(r.Timestamp, 10),
+		r.Nonce,
+		strings.ToUpper(r.Method),
+		r.Path,
+		r.BodyHash,
+	}, "\n")
+}
+
+// Sign returns the hex encoded signature of r under signingKey, the
+// SigningKey issued with an HMAC mode API key
+func (r *SignedRequest) Sign(signingKey []byte) string {
+	mac := hmac.New(sha256.New, signingKey)
+	mac.Write([]byte(r.StringToSign()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// HashBody returns the hex encoded SHA-256 of body, as used in BodyHash
+func HashBody(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
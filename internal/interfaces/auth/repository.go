@@ -1,9 +1,19 @@
 package auth
 
-import "context"
+import (
+	"context"
+	"net/netip"
+	"time"
+)
 
 type AuthRepository interface {
 	Store(ctx context.Context, app *Application) error
 	FindKeyById(ctx context.Context, keyId string) (*Application, error)
-	UpdateLastUsed(ctx context.Context, keyId string) error
+	UpdateLastUsed(ctx context.Context, lastUsed map[string]time.Time) error
+	List(ctx context.Context) ([]Application, error)
+	Revoke(ctx context.Context, keyId string) error
+	Rotate(ctx context.Context, keyId string, hashedKey []byte, previousValidUntil time.Time) error
+	UpdateRateLimit(ctx context.Context, keyId string, limit *RateLimit) error
+	UpdateAuthMode(ctx context.Context, keyId string, mode AuthMode) error
+	UpdateAllowedCIDRs(ctx context.Context, keyId string, cidrs []netip.Prefix) error
 }
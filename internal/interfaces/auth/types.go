@@ -1,13 +1,186 @@
 package auth
 
+import (
+	"net/netip"
+	"strings"
+	"time"
+)
+
+type Scope string
+
+const (
+	ScopeProfilesRead  Scope = "profiles:read"
+	ScopeProfilesWrite Scope = "profiles:write"
+	ScopeActivityRead  Scope = "activity:read"
+	ScopeActivityWrite Scope = "activity:write"
+	ScopeAuditRead     Scope = "audit:read"
+	ScopeAdmin         Scope = "admin"
+)
+
+// AllScopes lists every scope that can be granted to an application
+var AllScopes = []Scope{
+	ScopeProfilesRead,
+	ScopeProfilesWrite,
+	ScopeActivityRead,
+	ScopeActivityWrite,
+	ScopeAuditRead,
+	ScopeAdmin,
+}
+
+// IsValid reports whether s is one of the known scopes
+func (s Scope) IsValid() bool {
+	for _, known := range AllScopes {
+		if s == known {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope reports whether granted satisfies required.
+// The admin scope satisfies every other scope.
+func HasScope(granted []Scope, required Scope) bool {
+	for _, s := range granted {
+		if s == required || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthMode is how an application proves it holds its key
+type AuthMode string
+
+const (
+	// AuthModeBearer sends the full API key on every request
+	AuthModeBearer AuthMode = "bearer"
+	// AuthModeHMAC signs every request with the key, see SignedRequest
+	AuthModeHMAC AuthMode = "hmac"
+)
+
+// IsValid reports whether m is a known auth mode
+func (m AuthMode) IsValid() bool {
+	return m == AuthModeBearer || m == AuthModeHMAC
+}
+
 type Application struct {
-	AppName   string
-	KeyId     string
-	HashedKey []byte
+	AppName    string
+	KeyId      string
+	HashedKey  []byte
+	Scopes     []Scope
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	ExpiresAt  *time.Time
+	RevokedAt  *time.Time
+
+	// PreviousHashedKey keeps the secret replaced by the last rotation valid
+	// until PreviousKeyExpiresAt so clients can be moved over gradually
+	PreviousHashedKey    []byte
+	PreviousKeyExpiresAt *time.Time
+
+	// RateLimit overrides the default request rate when set
+	RateLimit *RateLimit
+
+	AuthMode AuthMode
+
+	// AllowedCIDRs restricts the addresses the key may be used from. Empty
+	// means anywhere.
+	AllowedCIDRs []netip.Prefix
+}
+
+// AllowsAddr reports whether the key may be used from addr
+func (a *Application) AllowsAddr(addr netip.Addr) bool {
+	if len(a.AllowedCIDRs) == 0 {
+		return true
+	}
+	for _, prefix := range a.AllowedCIDRs {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Info returns the parts of the application that are safe to show to admins
+func (a *Application) Info() KeyInfo {
+	return KeyInfo{
+		AppName:      a.AppName,
+		KeyId:        a.KeyId,
+		Scopes:       a.Scopes,
+		CreatedAt:    a.CreatedAt,
+		LastUsedAt:   a.LastUsedAt,
+		ExpiresAt:    a.ExpiresAt,
+		RevokedAt:    a.RevokedAt,
+		RateLimit:    a.RateLimit,
+		AuthMode:     a.AuthMode,
+		AllowedCIDRs: a.AllowedCIDRs,
+	}
+}
+
+// RateLimit is a token bucket refilled at PerMinute tokens per minute that
+// holds at most Burst tokens
+type RateLimit struct {
+	PerMinute int `json:"per_minute"`
+	Burst     int `json:"burst"`
+}
+
+// KeyOptions are the settings chosen when a key is issued
+type KeyOptions struct {
+	Scopes    []Scope
+	ExpiresAt *time.Time
+	RateLimit *RateLimit
+	// AuthMode defaults to AuthModeBearer
+	AuthMode AuthMode
+	// AllowedCIDRs are CIDR ranges or single addresses, see ParseCIDR
+	AllowedCIDRs []string
 }
 
 type APIKey struct {
 	KeyId   string
 	APIKey  string
 	AppName string
+	Scopes  []Scope
+	// SigningKey is what HMAC mode clients sign requests with. It is only
+	// set for keys in HMAC mode.
+	SigningKey string `json:",omitempty"`
+}
+
+type KeyInfo struct {
+	AppName      string         `json:"app_name"`
+	KeyId        string         `json:"key_id"`
+	Scopes       []Scope        `json:"scopes"`
+	CreatedAt    time.Time      `json:"created_at"`
+	LastUsedAt   *time.Time     `json:"last_used_at,omitempty"`
+	ExpiresAt    *time.Time     `json:"expires_at,omitempty"`
+	RevokedAt    *time.Time     `json:"revoked_at,omitempty"`
+	RateLimit    *RateLimit     `json:"rate_limit,omitempty"`
+	AuthMode     AuthMode       `json:"auth_mode"`
+	AllowedCIDRs []netip.Prefix `json:"allowed_cidrs,omitempty"`
+}
+
+// ParseCIDR parses a CIDR range such as "10.0.0.0/24", or a single address
+// which is treated as a range of one. Host bits are cleared.
+func ParseCIDR(value string) (netip.Prefix, error) {
+	if !strings.Contains(value, "/") {
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		addr = addr.Unmap()
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+
+	prefix, err := netip.ParsePrefix(value)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return prefix.Masked(), nil
+}
+
+// ExecIdentity is the exec a request is made on behalf of, as asserted by a
+// signed exec token
+type ExecIdentity struct {
+	ID   string
+	Name string
+	Role string
 }
@@ -0,0 +1,15 @@
+package exec
+
+import (
+	"context"
+
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+type ExecRepository interface {
+	Create(ctx context.Context, exec *models.Exec) (*models.Exec, error)
+	GetByID(ctx context.Context, id string) (*models.Exec, error)
+	GetByEmail(ctx context.Context, email string) (*models.Exec, error)
+	List(ctx context.Context) ([]models.Exec, error)
+	SetActive(ctx context.Context, id string, active bool) (*models.Exec, error)
+}
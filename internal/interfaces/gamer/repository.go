@@ -9,17 +9,40 @@ import (
 
 type GamerProfileRepository interface {
 	GetByStudentNumber(ctx context.Context, studentNumber string) (*models.GamerProfile, error)
+	GetByStudentNumberForUpdate(ctx context.Context, studentNumber string) (*models.GamerProfile, error)
+	GetByStudentNumberIncludingDeleted(ctx context.Context, studentNumber string) (*models.GamerProfile, error)
+	GetByEmail(ctx context.Context, email string) (*models.GamerProfile, error)
+	List(ctx context.Context, filter models.ProfileFilter) ([]models.GamerProfile, int, error)
 	Upsert(ctx context.Context, profile *models.GamerProfile) (*models.GamerProfile, error)
+	Update(ctx context.Context, profile *models.GamerProfile) (*models.GamerProfile, error)
 	Delete(ctx context.Context, studentNumber string) error
+	Restore(ctx context.Context, studentNumber string) (*models.GamerProfile, error)
+	Erase(ctx context.Context, studentNumber string) (string, error)
 	CheckMembershipValidity(ctx context.Context, studentNumber string) (tier int, expiryDate *time.Time, err error)
+	AddMembership(ctx context.Context, membership *models.Membership) (*models.Membership, error)
+	GetMembershipByExternalRef(ctx context.Context, externalRef string) (*models.Membership, error)
+	ListMemberships(ctx context.Context, studentNumber string) ([]models.Membership, error)
+	CreateBan(ctx context.Context, ban *models.Ban) (*models.Ban, error)
+	GetBan(ctx context.Context, banID string) (*models.Ban, error)
+	GetActiveBan(ctx context.Context, studentNumber string) (*models.Ban, error)
+	ListBans(ctx context.Context, studentNumber string) ([]models.Ban, error)
+	LiftBan(ctx context.Context, ban *models.Ban) (*models.Ban, error)
+	CreateNote(ctx context.Context, note *models.MemberNote) (*models.MemberNote, error)
+	GetNote(ctx context.Context, noteID string) (*models.MemberNote, error)
+	ListNotes(ctx context.Context, studentNumber string, includeArchived bool) ([]models.MemberNote, error)
+	ArchiveNote(ctx context.Context, note *models.MemberNote) (*models.MemberNote, error)
+	WithTx(ctx context.Context, fn func(repo GamerProfileRepository) error) error
 }
 
 type GamerActivityRepository interface {
 	GetByStudentNumber(ctx context.Context, studentNumber string) ([]models.GamerActivity, error)
 	GetTodayActivitiesByStudent(ctx context.Context, studentNumber string) ([]models.GamerActivity, error)
+	GetBetween(ctx context.Context, studentNumber string, windowStart, windowEnd time.Time) ([]models.GamerActivity, error)
 	GetRecentActivities(ctx context.Context, page, limit int, search string) ([]models.GamerActivity, error)
 	GetExecLeaderboard(ctx context.Context, windowStart, windowEnd time.Time) ([]models.ExecLeaderboardEntry, error)
 	Create(ctx context.Context, activity *models.GamerActivity) (*models.GamerActivity, error)
-	UpdateEndTime(ctx context.Context, studentNumber string, pcNumber int, endedAt time.Time, execName string) (*models.GamerActivity, error)
+	UpdateEndTime(ctx context.Context, studentNumber string, pcNumber int, endedAt time.Time, execID, execName string) (*models.GamerActivity, error)
 	GetActiveSessions(ctx context.Context) ([]models.GamerActivity, error)
+	FlagOverdue(ctx context.Context, id string, flaggedAt time.Time) (bool, error)
+	EndOverdue(ctx context.Context, id string, endedAt time.Time) (*models.GamerActivity, error)
 }
@@ -0,0 +1,19 @@
+package reservation
+
+import (
+	"context"
+	"time"
+
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+type ReservationRepository interface {
+	Create(ctx context.Context, reservation *models.Reservation) (*models.Reservation, error)
+	Get(ctx context.Context, id string) (*models.Reservation, error)
+	List(ctx context.Context, filter models.ReservationFilter) ([]models.Reservation, error)
+	GetOverlapping(ctx context.Context, stationNumbers []int, windowStart, windowEnd time.Time) ([]models.Reservation, error)
+	ListNoShows(ctx context.Context, startedBefore time.Time) ([]models.Reservation, error)
+	CheckIn(ctx context.Context, id string, checkedInAt time.Time) (bool, error)
+	Cancel(ctx context.Context, id string, cancelledAt time.Time) (*models.Reservation, error)
+	ReleaseNoShow(ctx context.Context, id string, releasedAt time.Time) (*models.Reservation, error)
+}
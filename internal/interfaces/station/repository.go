@@ -0,0 +1,16 @@
+package station
+
+import (
+	"context"
+
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+type StationRepository interface {
+	Create(ctx context.Context, station *models.Station) (*models.Station, error)
+	Get(ctx context.Context, number int) (*models.Station, error)
+	List(ctx context.Context) ([]models.Station, error)
+	Update(ctx context.Context, station *models.Station) (*models.Station, error)
+	Delete(ctx context.Context, number int) error
+	HasSessions(ctx context.Context, number int) (bool, error)
+}
@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/ubcesports/echo-base/internal/interfaces/audit"
+)
+
+// AuditRoute makes the matched route available to audit entries recorded
+// while handling the request. It must wrap a handler registered on a mux.
+func AuditRoute(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := audit.WithRoute(r.Context(), r.Method, r.Pattern)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -1,30 +1,143 @@
 package middleware
 
 import (
-	"context"
+	"bytes"
+	goerrors "errors"
+	"fmt"
+	"io"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
 	"github.com/ubcesports/echo-base/internal/services"
 )
 
+// MaxSignedBodySize bounds the request body read to verify a signed request
+const MaxSignedBodySize = 10 << 20
+
+// AuthMiddleware authenticates the calling application either from a bearer
+// API key or, for applications in HMAC mode, from a request signature. Keys
+// restricted to certain networks are refused from anywhere else.
 func AuthMiddleware(next http.Handler, authService services.AuthService) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
+
+		var app *auth.Application
+		var err error
+		switch {
+		case strings.HasPrefix(authHeader, "Bearer "):
+			apiKey := strings.TrimPrefix(authHeader, "Bearer ")
+			app, err = authService.ValidateAPIKey(r.Context(), apiKey, ClientIP(r))
+		case strings.HasPrefix(authHeader, auth.HMACScheme+" "):
+			var req *auth.SignedRequest
+			req, err = parseSignedRequest(r, strings.TrimPrefix(authHeader, auth.HMACScheme+" "))
+			if err != nil {
+				http.Error(w, "Invalid signed request: "+err.Error(), http.StatusUnauthorized)
+				return
+			}
+			app, err = authService.ValidateSignedRequest(r.Context(), req, ClientIP(r), func() (string, error) {
+				return hashRequestBody(w, r)
+			})
+			var tooLarge *http.MaxBytesError
+			if goerrors.As(err, &tooLarge) {
+				http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+		default:
 			http.Error(w, "Missing or invalid Authorization header", http.StatusUnauthorized)
 			return
 		}
 
-		apiKey := strings.TrimPrefix(authHeader, "Bearer ")
-
-		appName, err := authService.ValidateAPIKey(r.Context(), apiKey)
+		var lockedOut *errors.LockedOutError
+		if goerrors.As(err, &lockedOut) {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(lockedOut.RetryAfter.Seconds()))))
+			http.Error(w, "Too many failed authentication attempts", http.StatusTooManyRequests)
+			return
+		}
 		if err != nil {
 			http.Error(w, "Unauthorized: Invalid API Key", http.StatusUnauthorized)
 			return
 		}
 
-		ctx := context.WithValue(r.Context(), "appName", appName)
+		if addr, ok := clientAddr(r); !ok || !app.AllowsAddr(addr) {
+			http.Error(w, "Forbidden: API key is not allowed from this address", http.StatusForbidden)
+			return
+		}
+
+		ctx := auth.WithApplication(r.Context(), app)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// parseSignedRequest parses the parameters of an EB-HMAC-SHA256 Authorization
+// header. The body is hashed separately by hashRequestBody.
+func parseSignedRequest(r *http.Request, params string) (*auth.SignedRequest, error) {
+	req := &auth.SignedRequest{
+		Method: r.Method,
+		Path:   r.URL.RequestURI(),
+	}
+
+	seen := make(map[string]bool)
+	for _, param := range strings.Split(params, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || seen[name] {
+			return nil, fmt.Errorf("malformed parameter %q", param)
+		}
+		seen[name] = true
+
+		switch name {
+		case "KeyId":
+			req.KeyId = value
+		case "Timestamp":
+			timestamp, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("timestamp must be unix seconds")
+			}
+			req.Timestamp = timestamp
+		case "Nonce":
+			req.Nonce = value
+		case "Signature":
+			req.Signature = value
+		default:
+			return nil, fmt.Errorf("unknown parameter %q", name)
+		}
+	}
+
+	for _, name := range []string{"KeyId", "Timestamp", "Nonce", "Signature"} {
+		if !seen[name] {
+			return nil, fmt.Errorf("missing %s", name)
+		}
+	}
+
+	return req, nil
+}
+
+// hashRequestBody hashes the request body for a signed request, leaving the
+// body readable for next
+func hashRequestBody(w http.ResponseWriter, r *http.Request) (string, error) {
+	var body []byte
+	if r.Body != nil {
+		var err error
+		body, err = io.ReadAll(http.MaxBytesReader(w, r.Body, MaxSignedBodySize))
+		if err != nil {
+			return "", err
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	return auth.HashBody(body), nil
+}
+
+// RequireScope rejects requests whose authenticated application was not granted scope
+func RequireScope(scope auth.Scope, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !auth.HasScope(auth.ScopesFromContext(r.Context()), scope) {
+			http.Error(w, "Forbidden: API key is missing the "+string(scope)+" scope", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -3,33 +3,82 @@ package middleware
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/ubcesports/echo-base/internal/errors"
 	"github.com/ubcesports/echo-base/internal/interfaces/auth"
 )
 
 type mockAuthService struct {
-	validKeys map[string]string // apiKey -> appName
+	validKeys   map[string]*auth.Application // apiKey -> application
+	lockedOut   map[string]time.Duration     // clientIP -> retry after
+	signingKeys map[string][]byte            // keyId -> signing key
 }
 
-func (m *mockAuthService) ValidateAPIKey(ctx context.Context, apiKey string) (string, error) {
-	if appName, exists := m.validKeys[apiKey]; exists {
-		return appName, nil
+func (m *mockAuthService) ValidateAPIKey(ctx context.Context, apiKey string, clientIP string) (*auth.Application, error) {
+	if retryAfter, locked := m.lockedOut[clientIP]; locked {
+		return nil, errors.NewLockedOutError(retryAfter)
 	}
-	return "", fmt.Errorf("invalid API key")
+	if app, exists := m.validKeys[apiKey]; exists {
+		return app, nil
+	}
+	return nil, fmt.Errorf("invalid API key")
+}
+
+func (m *mockAuthService) ValidateSignedRequest(ctx context.Context, req *auth.SignedRequest, clientIP string, hashBody func() (string, error)) (*auth.Application, error) {
+	key, exists := m.signingKeys[req.KeyId]
+	if !exists {
+		return nil, fmt.Errorf("unknown key id")
+	}
+	bodyHash, err := hashBody()
+	if err != nil {
+		return nil, err
+	}
+	req.BodyHash = bodyHash
+	if req.Signature != req.Sign(key) {
+		return nil, fmt.Errorf("invalid request signature")
+	}
+	return &auth.Application{AppName: "signed-" + req.KeyId}, nil
+}
+
+func (m *mockAuthService) GenerateAPIKey(ctx context.Context, appName string, opts auth.KeyOptions) (*auth.APIKey, error) {
+	return nil, nil
+}
+
+func (m *mockAuthService) ListAPIKeys(ctx context.Context) ([]auth.KeyInfo, error) {
+	return nil, nil
+}
+
+func (m *mockAuthService) RevokeAPIKey(ctx context.Context, keyId string) error {
+	return nil
+}
+
+func (m *mockAuthService) UpdateRateLimit(ctx context.Context, keyId string, limit *auth.RateLimit) error {
+	return nil
 }
 
-func (m *mockAuthService) GenerateAPIKey(ctx context.Context, appName string) (*auth.APIKey, error) {
+func (m *mockAuthService) UpdateAuthMode(ctx context.Context, keyId string, mode auth.AuthMode) (string, error) {
+	return "", nil
+}
+
+func (m *mockAuthService) UpdateAllowedCIDRs(ctx context.Context, keyId string, cidrs []string) error {
+	return nil
+}
+
+func (m *mockAuthService) RotateAPIKey(ctx context.Context, keyId string, overlap time.Duration) (*auth.APIKey, error) {
 	return nil, nil
 }
 
 func TestAuthMiddleware(t *testing.T) {
 	mockService := &mockAuthService{
-		validKeys: map[string]string{
-			"valid-api-key": "test-app",
+		validKeys: map[string]*auth.Application{
+			"valid-api-key": {AppName: "test-app"},
 		},
 	}
 
@@ -86,3 +135,216 @@ func TestAuthMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthMiddlewareSignedRequest(t *testing.T) {
+	signingKey := []byte("signing-key")
+	mockService := &mockAuthService{
+		signingKeys: map[string][]byte{"kiosk1": signingKey},
+	}
+
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(auth.AppNameFromContext(r.Context()) + ": " + string(body)))
+	})
+	handler := AuthMiddleware(testHandler, mockService)
+
+	sign := func(method, path, body string) string {
+		req := &auth.SignedRequest{
+			KeyId:     "kiosk1",
+			Timestamp: 1700000000,
+			Nonce:     "0123456789abcdef",
+			Method:    method,
+			Path:      path,
+			BodyHash:  auth.HashBody([]byte(body)),
+		}
+		return fmt.Sprintf("%s KeyId=%s, Timestamp=%d, Nonce=%s, Signature=%s",
+			auth.HMACScheme, req.KeyId, req.Timestamp, req.Nonce, req.Sign(signingKey))
+	}
+
+	largeBody := strings.Repeat("a", MaxSignedBodySize+1)
+
+	testCases := []struct {
+		name           string
+		authHeader     string
+		method         string
+		path           string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "Valid signature",
+			authHeader:     sign("POST", "/test?pc=1", `{"a":1}`),
+			method:         "POST",
+			path:           "/test?pc=1",
+			body:           `{"a":1}`,
+			expectedStatus: http.StatusOK,
+			expectedBody:   `signed-kiosk1: {"a":1}`,
+		},
+		{
+			name:           "Body changed",
+			authHeader:     sign("POST", "/test", `{"a":1}`),
+			method:         "POST",
+			path:           "/test",
+			body:           `{"a":2}`,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Query changed",
+			authHeader:     sign("GET", "/test?page=1", ""),
+			method:         "GET",
+			path:           "/test?page=2",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Method changed",
+			authHeader:     sign("GET", "/test", ""),
+			method:         "DELETE",
+			path:           "/test",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Missing parameter",
+			authHeader:     auth.HMACScheme + " KeyId=kiosk1, Timestamp=1700000000, Nonce=0123456789abcdef",
+			method:         "GET",
+			path:           "/test",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Malformed timestamp",
+			authHeader:     auth.HMACScheme + " KeyId=kiosk1, Timestamp=yesterday, Nonce=0123456789abcdef, Signature=00",
+			method:         "GET",
+			path:           "/test",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Body too large",
+			authHeader:     sign("POST", "/test", ""),
+			method:         "POST",
+			path:           "/test",
+			body:           largeBody,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			// The body isn't read until the key has been found
+			name:           "Unknown key with a large body",
+			authHeader:     auth.HMACScheme + " KeyId=unknown, Timestamp=1700000000, Nonce=0123456789abcdef, Signature=00",
+			method:         "POST",
+			path:           "/test",
+			body:           largeBody,
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Authorization", tc.authHeader)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+			if tc.expectedBody != "" {
+				assert.Equal(t, tc.expectedBody, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestAuthMiddlewareLockout(t *testing.T) {
+	mockService := &mockAuthService{
+		validKeys: map[string]*auth.Application{
+			"valid-api-key": {AppName: "test-app"},
+		},
+		lockedOut: map[string]time.Duration{
+			"10.0.0.9": 90 * time.Second,
+		},
+	}
+
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := AuthMiddleware(testHandler, mockService)
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.RemoteAddr = "10.0.0.9:5555"
+	req.Header.Set("Authorization", "Bearer valid-api-key")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "90", rr.Header().Get("Retry-After"))
+
+	req = httptest.NewRequest("GET", "/test", nil)
+	req.RemoteAddr = "10.0.0.1:5555"
+	req.Header.Set("Authorization", "Bearer valid-api-key")
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestRequireScope(t *testing.T) {
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	testCases := []struct {
+		name           string
+		granted        []auth.Scope
+		required       auth.Scope
+		expectedStatus int
+	}{
+		{
+			name:           "No scopes",
+			granted:        nil,
+			required:       auth.ScopeProfilesRead,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Matching scope",
+			granted:        []auth.Scope{auth.ScopeActivityWrite},
+			required:       auth.ScopeActivityWrite,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Different scope",
+			granted:        []auth.Scope{auth.ScopeActivityWrite},
+			required:       auth.ScopeProfilesWrite,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Kiosk scopes cannot reach admin",
+			granted:        []auth.Scope{auth.ScopeActivityRead, auth.ScopeActivityWrite},
+			required:       auth.ScopeAdmin,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Admin satisfies everything",
+			granted:        []auth.Scope{auth.ScopeAdmin},
+			required:       auth.ScopeProfilesWrite,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := &mockAuthService{
+				validKeys: map[string]*auth.Application{
+					"valid-api-key": {AppName: "test-app", Scopes: tc.granted},
+				},
+			}
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.Header.Set("Authorization", "Bearer valid-api-key")
+
+			rr := httptest.NewRecorder()
+			handler := AuthMiddleware(RequireScope(tc.required, testHandler), mockService)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
+)
+
+const clientIPContextKey = "clientIP"
+
+// ParseTrustedProxies parses a comma separated list of CIDR ranges or single
+// addresses, such as the value of EB_TRUSTED_PROXIES
+func ParseTrustedProxies(value string) ([]netip.Prefix, error) {
+	var proxies []netip.Prefix
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		prefix, err := auth.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", entry, err)
+		}
+		proxies = append(proxies, prefix)
+	}
+	return proxies, nil
+}
+
+// ClientIPMiddleware works out the address of the client behind any trusted
+// proxies. X-Forwarded-For is only believed when the peer is a trusted proxy,
+// and is walked from the right so a client can't spoof its address by
+// sending the header itself: the first hop that isn't a trusted proxy is the
+// client.
+func ClientIPMiddleware(next http.Handler, trustedProxies []netip.Prefix) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		addr, ok := resolveClientIP(r, trustedProxies)
+		if ok {
+			r = r.WithContext(context.WithValue(r.Context(), clientIPContextKey, addr))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func resolveClientIP(r *http.Request, trustedProxies []netip.Prefix) (netip.Addr, bool) {
+	peer, ok := parseAddr(r.RemoteAddr)
+	if !ok {
+		return netip.Addr{}, false
+	}
+
+	client := peer
+	if !isTrusted(client, trustedProxies) {
+		return client, true
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, ok := parseAddr(strings.TrimSpace(hops[i]))
+		if !ok {
+			// Anything left of a garbled entry can't be trusted, so the last
+			// proxy is as far back as we can see
+			break
+		}
+		client = hop
+		if !isTrusted(client, trustedProxies) {
+			break
+		}
+	}
+	return client, true
+}
+
+func isTrusted(addr netip.Addr, trustedProxies []netip.Prefix) bool {
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseAddr accepts an address with or without a port
+func parseAddr(value string) (netip.Addr, bool) {
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
+
+// clientAddr returns the client address resolved by ClientIPMiddleware,
+// falling back to the peer address
+func clientAddr(r *http.Request) (netip.Addr, bool) {
+	if addr, ok := r.Context().Value(clientIPContextKey).(netip.Addr); ok {
+		return addr, true
+	}
+	return parseAddr(r.RemoteAddr)
+}
+
+// ClientIP returns the address of the client that sent the request
+func ClientIP(r *http.Request) string {
+	if addr, ok := clientAddr(r); ok {
+		return addr.String()
+	}
+	return r.RemoteAddr
+}
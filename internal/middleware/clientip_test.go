@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+	proxies, err := ParseTrustedProxies(" 10.0.0.0/8, 192.168.1.5 ,,fd00::/8")
+	assert.NoError(t, err)
+	assert.Equal(t, []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("192.168.1.5/32"),
+		netip.MustParsePrefix("fd00::/8"),
+	}, proxies)
+
+	proxies, err = ParseTrustedProxies("")
+	assert.NoError(t, err)
+	assert.Empty(t, proxies)
+
+	_, err = ParseTrustedProxies("10.0.0.0/8,load-balancer")
+	assert.Error(t, err)
+}
+
+func TestClientIPMiddleware(t *testing.T) {
+	trusted := []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/8"),
+	}
+
+	testCases := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		expectedIP   string
+	}{
+		{
+			name:       "Direct client",
+			remoteAddr: "203.0.113.7:5555",
+			expectedIP: "203.0.113.7",
+		},
+		{
+			name:         "Untrusted peer cannot forward",
+			remoteAddr:   "203.0.113.7:5555",
+			forwardedFor: []string{"198.51.100.1"},
+			expectedIP:   "203.0.113.7",
+		},
+		{
+			name:         "Trusted proxy",
+			remoteAddr:   "10.0.0.2:5555",
+			forwardedFor: []string{"198.51.100.1"},
+			expectedIP:   "198.51.100.1",
+		},
+		{
+			name:         "Spoofed hops left of the client are ignored",
+			remoteAddr:   "10.0.0.2:5555",
+			forwardedFor: []string{"1.2.3.4, 198.51.100.1, 10.0.0.3"},
+			expectedIP:   "198.51.100.1",
+		},
+		{
+			name:         "Multiple headers",
+			remoteAddr:   "10.0.0.2:5555",
+			forwardedFor: []string{"1.2.3.4", "198.51.100.1"},
+			expectedIP:   "198.51.100.1",
+		},
+		{
+			name:         "Garbled hop stops the walk",
+			remoteAddr:   "10.0.0.2:5555",
+			forwardedFor: []string{"198.51.100.1, unknown, 10.0.0.3"},
+			expectedIP:   "10.0.0.3",
+		},
+		{
+			name:         "Only proxies",
+			remoteAddr:   "10.0.0.2:5555",
+			forwardedFor: []string{"10.0.0.4, 10.0.0.3"},
+			expectedIP:   "10.0.0.4",
+		},
+		{
+			name:       "IPv4 mapped IPv6 peer",
+			remoteAddr: "[::ffff:203.0.113.7]:5555",
+			expectedIP: "203.0.113.7",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got string
+			handler := ClientIPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = ClientIP(r)
+			}), trusted)
+
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.RemoteAddr = tc.remoteAddr
+			for _, value := range tc.forwardedFor {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tc.expectedIP, got)
+		})
+	}
+}
+
+func TestAuthMiddlewareAllowedCIDRs(t *testing.T) {
+	mockService := &mockAuthService{
+		validKeys: map[string]*auth.Application{
+			"lounge-key": {
+				AppName:      "kiosk",
+				AllowedCIDRs: []netip.Prefix{netip.MustParsePrefix("198.51.100.0/24")},
+			},
+		},
+	}
+
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+	handler := ClientIPMiddleware(AuthMiddleware(testHandler, mockService), trusted)
+
+	testCases := []struct {
+		name           string
+		remoteAddr     string
+		forwardedFor   string
+		expectedStatus int
+	}{
+		{"From the lounge", "198.51.100.20:5555", "", http.StatusOK},
+		{"From the lounge through the proxy", "10.0.0.2:5555", "198.51.100.20", http.StatusOK},
+		{"From elsewhere", "203.0.113.7:5555", "", http.StatusForbidden},
+		{"Spoofing the lounge", "203.0.113.7:5555", "198.51.100.20", http.StatusForbidden},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.RemoteAddr = tc.remoteAddr
+			req.Header.Set("Authorization", "Bearer lounge-key")
+			if tc.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tc.forwardedFor)
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+		})
+	}
+}
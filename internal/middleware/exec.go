@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	goerrors "errors"
+	"net/http"
+
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
+	"github.com/ubcesports/echo-base/internal/services"
+)
+
+// ExecTokenHeader carries the signed token of the exec using the app
+const ExecTokenHeader = "X-Exec-Token"
+
+// ExecTokenMiddleware identifies the exec behind a request from an optional
+// exec token. The token supplements the application's API key, so this must
+// run after AuthMiddleware. Requests without a token carry no exec, while
+// tokens of execs who have since been deactivated are rejected.
+func ExecTokenMiddleware(next http.Handler, execService services.ExecService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get(ExecTokenHeader)
+		if token == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		exec, err := execService.VerifyToken(r.Context(), token)
+		var unauthorizedErr *errors.UnauthorizedError
+		if goerrors.As(err, &unauthorizedErr) {
+			http.Error(w, "Unauthorized: Invalid exec token", http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			http.Error(w, "Failed to verify exec token", http.StatusInternalServerError)
+			return
+		}
+
+		ctx := auth.WithExec(r.Context(), exec)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
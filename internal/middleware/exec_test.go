@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+type mockExecService struct {
+	tokens map[string]*auth.ExecIdentity
+}
+
+func (m *mockExecService) CreateExec(ctx context.Context, req *models.CreateExecRequest) (*models.Exec, error) {
+	return nil, nil
+}
+
+func (m *mockExecService) ListExecs(ctx context.Context) ([]models.Exec, error) {
+	return nil, nil
+}
+
+func (m *mockExecService) UpdateExec(ctx context.Context, id string, req *models.UpdateExecRequest) (*models.Exec, error) {
+	return nil, nil
+}
+
+func (m *mockExecService) Login(ctx context.Context, req *models.ExecLoginRequest, clientIP string) (*models.ExecToken, error) {
+	return nil, nil
+}
+
+func (m *mockExecService) VerifyToken(ctx context.Context, token string) (*auth.ExecIdentity, error) {
+	if token == "unverifiable-token" {
+		return nil, fmt.Errorf("database is down")
+	}
+	if exec, ok := m.tokens[token]; ok {
+		return exec, nil
+	}
+	return nil, errors.NewUnauthorizedError("invalid exec token")
+}
+
+func TestExecTokenMiddleware(t *testing.T) {
+	mockService := &mockExecService{
+		tokens: map[string]*auth.ExecIdentity{
+			"valid-token": {ID: "exec-1", Name: "Alex"},
+		},
+	}
+
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := "none"
+		if exec := auth.ExecFromContext(r.Context()); exec != nil {
+			name = exec.Name
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("exec: " + name))
+	})
+
+	testCases := []struct {
+		name           string
+		token          string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "No exec token",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "exec: none",
+		},
+		{
+			name:           "Valid exec token",
+			token:          "valid-token",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "exec: Alex",
+		},
+		{
+			name:           "Invalid exec token",
+			token:          "forged-token",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Exec lookup fails",
+			token:          "unverifiable-token",
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tc.token != "" {
+				req.Header.Set(ExecTokenHeader, tc.token)
+			}
+
+			rr := httptest.NewRecorder()
+			ExecTokenMiddleware(testHandler, mockService).ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatus, rr.Code)
+			if tc.expectedBody != "" {
+				assert.Equal(t, tc.expectedBody, rr.Body.String())
+			}
+		})
+	}
+}
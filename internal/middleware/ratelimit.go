@@ -0,0 +1,181 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
+)
+
+var (
+	// DefaultAppRateLimit applies to applications without their own limit
+	DefaultAppRateLimit = auth.RateLimit{PerMinute: 300, Burst: 60}
+
+	// FailedAuthRateLimit bounds how many failed authentication attempts a
+	// single client IP can make
+	FailedAuthRateLimit = auth.RateLimit{PerMinute: 10, Burst: 10}
+)
+
+// maxIdleBuckets is the bucket count above which full, idle buckets are dropped
+const maxIdleBuckets = 10000
+
+type RateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	now     func() time.Time
+}
+
+type tokenBucket struct {
+	tokens        float64
+	last          time.Time
+	ratePerSecond float64
+	burst         float64
+}
+
+func (b *tokenBucket) refill(now time.Time) float64 {
+	return math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.ratePerSecond)
+}
+
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+	Reset      time.Time
+}
+
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// Take consumes a token from the bucket identified by key
+func (l *RateLimiter) Take(key string, limit auth.RateLimit) RateLimitResult {
+	return l.check(key, limit, true)
+}
+
+// Peek reports whether a token is available without consuming it
+func (l *RateLimiter) Peek(key string, limit auth.RateLimit) RateLimitResult {
+	return l.check(key, limit, false)
+}
+
+func (l *RateLimiter) check(key string, limit auth.RateLimit, consume bool) RateLimitResult {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	ratePerSecond := float64(limit.PerMinute) / 60
+	burst := float64(limit.Burst)
+
+	bucket, exists := l.buckets[key]
+	if !exists {
+		if len(l.buckets) >= maxIdleBuckets {
+			l.prune(now)
+		}
+		bucket = &tokenBucket{tokens: burst, last: now}
+		l.buckets[key] = bucket
+	}
+
+	// Limits can change while a bucket is live, so always use the latest
+	bucket.ratePerSecond = ratePerSecond
+	bucket.burst = burst
+	bucket.tokens = bucket.refill(now)
+	bucket.last = now
+
+	result := RateLimitResult{Limit: limit.PerMinute}
+	if bucket.tokens >= 1 {
+		result.Allowed = true
+		if consume {
+			bucket.tokens--
+		}
+	} else {
+		result.RetryAfter = secondsToDuration((1 - bucket.tokens) / ratePerSecond)
+	}
+
+	result.Remaining = int(bucket.tokens)
+	result.Reset = now.Add(secondsToDuration((burst - bucket.tokens) / ratePerSecond))
+	return result
+}
+
+// prune drops buckets that would have refilled completely, since a new
+// bucket starts full anyway. Callers must hold l.mu.
+func (l *RateLimiter) prune(now time.Time) {
+	for key, bucket := range l.buckets {
+		if bucket.refill(now) >= bucket.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// RateLimitMiddleware limits requests per authenticated API key. Keys are
+// limited separately even when issued under the same app name. It must run
+// after AuthMiddleware so the application is available on the context.
+func RateLimitMiddleware(next http.Handler, limiter *RateLimiter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		app := auth.ApplicationFromContext(r.Context())
+		if app == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		limit := DefaultAppRateLimit
+		if app.RateLimit != nil {
+			limit = *app.RateLimit
+		}
+
+		result := limiter.Take("key:"+app.KeyId, limit)
+		setRateLimitHeaders(w, result)
+		if !result.Allowed {
+			writeTooManyRequests(w, result)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// FailedAuthRateLimitMiddleware limits unauthenticated clients by IP. Only
+// requests rejected with 401 count against the limit, so a client that
+// keeps presenting bad keys is cut off before reaching the database.
+func FailedAuthRateLimitMiddleware(next http.Handler, limiter *RateLimiter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := "ip:" + ClientIP(r)
+
+		if result := limiter.Peek(key, FailedAuthRateLimit); !result.Allowed {
+			setRateLimitHeaders(w, result)
+			writeTooManyRequests(w, result)
+			return
+		}
+
+		recorder := newStatusRecorder(w)
+		next.ServeHTTP(recorder, r)
+
+		if recorder.status == http.StatusUnauthorized {
+			limiter.Take(key, FailedAuthRateLimit)
+		}
+	})
+}
+
+func setRateLimitHeaders(w http.ResponseWriter, result RateLimitResult) {
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(result.Reset.Unix(), 10))
+}
+
+func writeTooManyRequests(w http.ResponseWriter, result RateLimitResult) {
+	retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	http.Error(w, "Too many requests", http.StatusTooManyRequests)
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
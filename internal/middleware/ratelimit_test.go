@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
+)
+
+func newTestRateLimiter(now *time.Time) *RateLimiter {
+	limiter := NewRateLimiter()
+	limiter.now = func() time.Time { return *now }
+	return limiter
+}
+
+func TestRateLimiterTokenBucket(t *testing.T) {
+	now := time.Now()
+	limiter := newTestRateLimiter(&now)
+	limit := auth.RateLimit{PerMinute: 60, Burst: 3}
+
+	for i := 0; i < 3; i++ {
+		result := limiter.Take("app:kiosk", limit)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 2-i, result.Remaining)
+	}
+
+	result := limiter.Take("app:kiosk", limit)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+
+	// Other keys have their own bucket
+	assert.True(t, limiter.Take("app:frontend", limit).Allowed)
+
+	// One token per second at 60 per minute
+	now = now.Add(time.Second)
+	assert.True(t, limiter.Take("app:kiosk", limit).Allowed)
+	assert.False(t, limiter.Take("app:kiosk", limit).Allowed)
+}
+
+func TestRateLimiterPeekDoesNotConsume(t *testing.T) {
+	now := time.Now()
+	limiter := newTestRateLimiter(&now)
+	limit := auth.RateLimit{PerMinute: 60, Burst: 1}
+
+	assert.True(t, limiter.Peek("ip:10.0.0.1", limit).Allowed)
+	assert.True(t, limiter.Peek("ip:10.0.0.1", limit).Allowed)
+	assert.True(t, limiter.Take("ip:10.0.0.1", limit).Allowed)
+	assert.False(t, limiter.Peek("ip:10.0.0.1", limit).Allowed)
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	now := time.Now()
+	limiter := newTestRateLimiter(&now)
+	mockService := &mockAuthService{
+		validKeys: map[string]*auth.Application{
+			"frontend-key": {AppName: "frontend", KeyId: "frontend", RateLimit: &auth.RateLimit{PerMinute: 60, Burst: 2}},
+			"kiosk-key":    {AppName: "kiosk", KeyId: "kiosk-1"},
+			"kiosk-key-2":  {AppName: "kiosk", KeyId: "kiosk-2", RateLimit: &auth.RateLimit{PerMinute: 60, Burst: 1}},
+		},
+	}
+
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := AuthMiddleware(RateLimitMiddleware(testHandler, limiter), mockService)
+
+	send := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.Header.Set("Authorization", "Bearer "+key)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := send("frontend-key")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "60", rr.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", rr.Header().Get("X-RateLimit-Remaining"))
+	assert.NotEmpty(t, rr.Header().Get("X-RateLimit-Reset"))
+
+	assert.Equal(t, http.StatusOK, send("frontend-key").Code)
+
+	rr = send("frontend-key")
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "1", rr.Header().Get("Retry-After"))
+
+	// A misbehaving frontend does not starve the kiosk
+	rr = send("kiosk-key")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "300", rr.Header().Get("X-RateLimit-Limit"))
+
+	// Keys sharing an app name have their own budgets
+	assert.Equal(t, http.StatusOK, send("kiosk-key-2").Code)
+	assert.Equal(t, http.StatusTooManyRequests, send("kiosk-key-2").Code)
+	assert.Equal(t, http.StatusOK, send("kiosk-key").Code)
+}
+
+func TestFailedAuthRateLimitMiddleware(t *testing.T) {
+	now := time.Now()
+	limiter := newTestRateLimiter(&now)
+	mockService := &mockAuthService{
+		validKeys: map[string]*auth.Application{
+			"valid-api-key": {AppName: "test-app"},
+		},
+	}
+
+	testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := FailedAuthRateLimitMiddleware(AuthMiddleware(testHandler, mockService), limiter)
+
+	send := func(remoteAddr, key string) int {
+		req := httptest.NewRequest("GET", "/test", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("Authorization", "Bearer "+key)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	// Successful requests don't count against the IP
+	for i := 0; i < FailedAuthRateLimit.Burst+5; i++ {
+		assert.Equal(t, http.StatusOK, send("10.0.0.1:1234", "valid-api-key"))
+	}
+
+	for i := 0; i < FailedAuthRateLimit.Burst; i++ {
+		assert.Equal(t, http.StatusUnauthorized, send("10.0.0.2:1234", "guess"))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, send("10.0.0.2:1234", "guess"))
+	assert.Equal(t, http.StatusTooManyRequests, send("10.0.0.2:1234", "valid-api-key"))
+
+	// Other clients are unaffected
+	assert.Equal(t, http.StatusUnauthorized, send("10.0.0.3:1234", "guess"))
+}
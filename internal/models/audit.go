@@ -0,0 +1,32 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditEntry struct {
+	ID          string          `json:"id"`
+	ActorApp    *string         `json:"actor_app,omitempty"`
+	ActorExecID *string         `json:"actor_exec_id,omitempty"`
+	Method      string          `json:"method"`
+	Route       string          `json:"route"`
+	Action      string          `json:"action"`
+	EntityType  string          `json:"entity_type"`
+	EntityID    string          `json:"entity_id"`
+	Before      json.RawMessage `json:"before"`
+	After       json.RawMessage `json:"after"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// AuditFilter narrows an audit log listing. Empty fields match everything.
+type AuditFilter struct {
+	EntityType  string
+	EntityID    string
+	ActorApp    string
+	ActorExecID string
+	From        *time.Time
+	To          *time.Time
+	Page        int
+	Limit       int
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Ban keeps a member off the PCs. A ban without an end date is permanent;
+// one with an end date is a suspension.
+type Ban struct {
+	ID             string     `json:"id"`
+	StudentNumber  string     `json:"student_number"`
+	Reason         string     `json:"reason"`
+	StartsAt       time.Time  `json:"starts_at"`
+	EndsAt         *time.Time `json:"ends_at,omitempty"`
+	IssuedByApp    *string    `json:"issued_by_app,omitempty"`
+	IssuedByExecID *string    `json:"issued_by_exec_id,omitempty"`
+	LiftedAt       *time.Time `json:"lifted_at,omitempty"`
+	LiftedByApp    *string    `json:"lifted_by_app,omitempty"`
+	LiftedByExecID *string    `json:"lifted_by_exec_id,omitempty"`
+	LiftReason     *string    `json:"lift_reason,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// IsActive reports whether the ban is in force at t
+func (b *Ban) IsActive(t time.Time) bool {
+	if b.LiftedAt != nil || b.StartsAt.After(t) {
+		return false
+	}
+	return b.EndsAt == nil || b.EndsAt.After(t)
+}
+
+// IssueBanRequest bans a member. StartsAt defaults to now and EndsAt is left
+// out for a permanent ban.
+type IssueBanRequest struct {
+	Reason   string     `json:"reason"`
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"`
+}
+
+type LiftBanRequest struct {
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+type ExecRole string
+
+const (
+	ExecRoleExec     ExecRole = "exec"
+	ExecRoleDirector ExecRole = "director"
+)
+
+func (r ExecRole) IsValid() bool {
+	return r == ExecRoleExec || r == ExecRoleDirector
+}
+
+type Exec struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Email        string    `json:"email"`
+	Role         ExecRole  `json:"role"`
+	Active       bool      `json:"active"`
+	CreatedAt    time.Time `json:"created_at"`
+	PasswordHash string    `json:"-"`
+}
+
+type CreateExecRequest struct {
+	Name     string   `json:"name"`
+	Email    string   `json:"email"`
+	Role     ExecRole `json:"role"`
+	Password string   `json:"password"`
+}
+
+type UpdateExecRequest struct {
+	Active *bool `json:"active"`
+}
+
+type ExecLoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type ExecToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Exec      Exec      `json:"exec"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// MemberExport is everything stored about a member, for answering a privacy
+// access request
+type MemberExport struct {
+	ExportedAt   time.Time       `json:"exported_at"`
+	Profile      GamerProfile    `json:"profile"`
+	Memberships  []Membership    `json:"memberships"`
+	Activity     []GamerActivity `json:"activity"`
+	Notes        []MemberNote    `json:"notes"`
+	Bans         []Ban           `json:"bans"`
+	AuditEntries []AuditEntry    `json:"audit_entries"`
+}
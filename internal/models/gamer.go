@@ -1,17 +1,56 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type GamerProfile struct {
-	ID                   string     `json:"id"`
-	StudentNumber        string     `json:"student_number"`
-	FirstName            string     `json:"first_name"`
-	LastName             string     `json:"last_name"`
-	MembershipTier       int        `json:"membership_tier"`
-	Banned               *bool      `json:"banned,omitempty"`
+	ID             string `json:"id"`
+	StudentNumber  string `json:"student_number"`
+	FirstName      string `json:"first_name"`
+	LastName       string `json:"last_name"`
+	MembershipTier int    `json:"membership_tier"`
+	Banned         *bool  `json:"banned,omitempty"`
+	// Notes is the latest pinned note on the member, kept for clients from
+	// before notes were a timeline
 	Notes                *string    `json:"notes,omitempty"`
 	CreatedAt            time.Time  `json:"created_at"`
 	MembershipExpiryDate *time.Time `json:"membership_expiry_date,omitempty"`
+	DeletedAt            *time.Time `json:"deleted_at,omitempty"`
+	Email                *string    `json:"email,omitempty"`
+	DiscordHandle        *string    `json:"discord_handle,omitempty"`
+	// MarketingConsent is whether the member opted in to club news and
+	// promotions
+	MarketingConsent bool `json:"marketing_consent"`
+	// NotificationConsent is whether the member can be told about their own
+	// membership, such as an upcoming expiry or a ban
+	NotificationConsent bool `json:"notification_consent"`
+}
+
+// ProfileFilter narrows a profile listing. Nil and empty fields match
+// everything.
+type ProfileFilter struct {
+	MembershipTier *int
+	Banned         *bool
+	ExpiresBefore  *time.Time
+	ExpiresAfter   *time.Time
+	CreatedFrom    *time.Time
+	CreatedTo      *time.Time
+	// Search matches part of a student number, first name, last name, full
+	// name, email or Discord handle
+	Search string
+	Page   int
+	Limit  int
+}
+
+// GamerProfilePage is one page of a profile listing along with the number of
+// profiles matching the filter across all pages
+type GamerProfilePage struct {
+	Profiles []GamerProfile `json:"profiles"`
+	Total    int            `json:"total"`
+	Page     int            `json:"page"`
+	Limit    int            `json:"limit"`
 }
 
 type GamerActivity struct {
@@ -23,22 +62,82 @@ type GamerActivity struct {
 	StartedAt      time.Time  `json:"started_at"`
 	EndedAt        *time.Time `json:"ended_at,omitempty"`
 	ExecName       *string    `json:"exec_name,omitempty"`
+	ExecID         *string    `json:"exec_id,omitempty"`
 	FirstName      *string    `json:"first_name,omitempty"`
 	LastName       *string    `json:"last_name,omitempty"`
+	// ExpiresAt, TimeRemainingMs and Overdue are worked out from the member's
+	// tier for active sessions. Tiers without a session limit leave them out.
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	TimeRemainingMs  *int64     `json:"time_remaining_ms,omitempty"`
+	Overdue          *bool      `json:"overdue,omitempty"`
+	OverdueFlaggedAt *time.Time `json:"overdue_flagged_at,omitempty"`
+	// EndedBySystem is set on sessions the server ended rather than an exec
+	EndedBySystem bool `json:"ended_by_system,omitempty"`
 }
 
 type ExecLeaderboardEntry struct {
-	ExecName     string `json:"exec_name"`
-	SignoutCount int    `json:"signout_count"`
+	ExecID       *string `json:"exec_id,omitempty"`
+	ExecName     string  `json:"exec_name"`
+	SignoutCount int     `json:"signout_count"`
 }
 
 type CreateGamerProfileRequest struct {
-	FirstName      string  `json:"first_name"`
-	LastName       string  `json:"last_name"`
-	StudentNumber  string  `json:"student_number"`
-	MembershipTier int     `json:"membership_tier"`
-	Banned         *bool   `json:"banned,omitempty"`
-	Notes          *string `json:"notes,omitempty"`
+	FirstName      string `json:"first_name"`
+	LastName       string `json:"last_name"`
+	StudentNumber  string `json:"student_number"`
+	MembershipTier int    `json:"membership_tier"`
+	Banned         *bool  `json:"banned,omitempty"`
+	// Notes that differ from the profile's current notes are added as a
+	// pinned note
+	Notes *string `json:"notes,omitempty"`
+	// MembershipSource is recorded with the membership for a paid tier and
+	// defaults to unknown
+	MembershipSource MembershipSource `json:"membership_source,omitempty"`
+	// Contact details are left as they were when an existing member
+	// registers again without them. Use a PATCH to clear them.
+	Email         *string `json:"email,omitempty"`
+	DiscordHandle *string `json:"discord_handle,omitempty"`
+	// The consents default to no marketing and to notifications for a new
+	// member, and are left as they were when an existing member registers
+	// again without them
+	MarketingConsent    *bool `json:"marketing_consent,omitempty"`
+	NotificationConsent *bool `json:"notification_consent,omitempty"`
+}
+
+// UpdateGamerProfileRequest is a JSON merge patch (RFC 7386) of a profile.
+// Fields left out of the body are unchanged and a null clears the field.
+// The student number identifies the profile and can't be patched.
+// MembershipTier is only accepted to reject it, since the tier comes from
+// the membership history.
+type UpdateGamerProfileRequest struct {
+	FirstName      PatchField[string] `json:"first_name"`
+	LastName       PatchField[string] `json:"last_name"`
+	MembershipTier PatchField[int]    `json:"membership_tier"`
+	Banned         PatchField[bool]   `json:"banned"`
+	// Notes are added as a pinned note, and clearing them archives the
+	// pinned notes
+	Notes         PatchField[string] `json:"notes"`
+	Email         PatchField[string] `json:"email"`
+	DiscordHandle PatchField[string] `json:"discord_handle"`
+	// The consents can't be cleared, only set to true or false
+	MarketingConsent    PatchField[bool] `json:"marketing_consent"`
+	NotificationConsent PatchField[bool] `json:"notification_consent"`
+}
+
+// PatchField is a field of a merge patch. Set tells a field sent as null
+// apart from one that wasn't sent.
+type PatchField[T any] struct {
+	Set   bool
+	Value *T
+}
+
+func (f *PatchField[T]) UnmarshalJSON(data []byte) error {
+	f.Set = true
+	if string(data) == "null" {
+		f.Value = nil
+		return nil
+	}
+	return json.Unmarshal(data, &f.Value)
 }
 
 type CreateActivityRequest struct {
@@ -47,7 +146,8 @@ type CreateActivityRequest struct {
 	Game          string `json:"game"`
 }
 
+// UpdateActivityRequest ends a session. The exec signing it out is taken
+// from the exec token on the request.
 type UpdateActivityRequest struct {
-	PCNumber int    `json:"pc_number"`
-	ExecName string `json:"exec_name"`
+	PCNumber int `json:"pc_number"`
 }
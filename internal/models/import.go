@@ -0,0 +1,77 @@
+package models
+
+// ImportColumns maps profile fields to the CSV headers they are read from.
+// Notes, Email and DiscordHandle are optional; an empty header leaves them
+// out.
+type ImportColumns struct {
+	StudentNumber  string `json:"student_number"`
+	FirstName      string `json:"first_name"`
+	LastName       string `json:"last_name"`
+	MembershipTier string `json:"membership_tier"`
+	Notes          string `json:"notes"`
+	Email          string `json:"email"`
+	DiscordHandle  string `json:"discord_handle"`
+}
+
+// DefaultImportColumns matches the headers of a Showpass attendee export
+func DefaultImportColumns() ImportColumns {
+	return ImportColumns{
+		StudentNumber:  "Student Number",
+		FirstName:      "First Name",
+		LastName:       "Last Name",
+		MembershipTier: "Ticket Type",
+	}
+}
+
+// ImportOptions controls a CSV import of member profiles. Tiers maps
+// membership tier values that aren't numbers, such as ticket type names, to
+// tier numbers. A dry run writes nothing. An atomic import writes every row
+// or none of them.
+type ImportOptions struct {
+	Columns ImportColumns  `json:"columns"`
+	Tiers   map[string]int `json:"tiers"`
+	DryRun  bool           `json:"dry_run"`
+	Atomic  bool           `json:"atomic"`
+}
+
+// ImportRowStatus is what happened, or in a dry run would happen, to a row
+type ImportRowStatus string
+
+const (
+	ImportRowCreate ImportRowStatus = "create"
+	ImportRowUpdate ImportRowStatus = "update"
+	// ImportRowSkip marks a row for a member who already has a current
+	// membership of the same tier and source, so there's nothing to record
+	ImportRowSkip ImportRowStatus = "skip"
+	// ImportRowConflict marks a row whose student number or email is repeated
+	// in the file, or that clashes with another member's name or email
+	ImportRowConflict ImportRowStatus = "conflict"
+	ImportRowInvalid  ImportRowStatus = "invalid"
+	// ImportRowFailed marks a valid row that couldn't be saved
+	ImportRowFailed ImportRowStatus = "failed"
+)
+
+// ImportRowResult reports on one CSV row. Rows are numbered from 1, not
+// counting the header.
+type ImportRowResult struct {
+	Row           int             `json:"row"`
+	StudentNumber string          `json:"student_number,omitempty"`
+	Status        ImportRowStatus `json:"status"`
+	Field         string          `json:"field,omitempty"`
+	Message       string          `json:"message,omitempty"`
+}
+
+// ImportReport summarizes an import. Committed is false for dry runs and
+// for atomic imports that were abandoned.
+type ImportReport struct {
+	DryRun    bool              `json:"dry_run"`
+	Atomic    bool              `json:"atomic"`
+	Committed bool              `json:"committed"`
+	Created   int               `json:"created"`
+	Updated   int               `json:"updated"`
+	Skipped   int               `json:"skipped"`
+	Conflicts int               `json:"conflicts"`
+	Invalid   int               `json:"invalid"`
+	Failed    int               `json:"failed"`
+	Rows      []ImportRowResult `json:"rows"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// MembershipSource is how a member paid for a membership
+type MembershipSource string
+
+const (
+	MembershipSourceShowpass MembershipSource = "showpass"
+	MembershipSourceCash     MembershipSource = "cash"
+	MembershipSourceComp     MembershipSource = "comp"
+	// MembershipSourceUnknown marks memberships recorded before sources
+	// were tracked, or registered without one
+	MembershipSourceUnknown MembershipSource = "unknown"
+)
+
+func (s MembershipSource) IsValid() bool {
+	switch s {
+	case MembershipSourceShowpass, MembershipSourceCash, MembershipSourceComp, MembershipSourceUnknown:
+		return true
+	}
+	return false
+}
+
+// Membership is one purchased membership in a member's history
+type Membership struct {
+	ID               string           `json:"id"`
+	StudentNumber    string           `json:"student_number"`
+	MembershipTier   int              `json:"membership_tier"`
+	Source           MembershipSource `json:"source"`
+	PurchasedAt      time.Time        `json:"purchased_at"`
+	StartsAt         time.Time        `json:"starts_at"`
+	ExpiresAt        *time.Time       `json:"expires_at,omitempty"`
+	RecordedByApp    *string          `json:"recorded_by_app,omitempty"`
+	RecordedByExecID *string          `json:"recorded_by_exec_id,omitempty"`
+	// ExternalRef is the id of the purchase the membership came from, such
+	// as a Showpass purchase id
+	ExternalRef *string   `json:"external_ref,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// RenewMembershipRequest records a new membership for an existing member.
+// PurchasedAt defaults to now.
+type RenewMembershipRequest struct {
+	MembershipTier int              `json:"membership_tier"`
+	Source         MembershipSource `json:"source"`
+	PurchasedAt    *time.Time       `json:"purchased_at,omitempty"`
+}
+
+// ProvisionMembershipRequest records a membership bought outside the lounge,
+// registering the member if they're new. ExternalRef identifies the
+// purchase so it is only recorded once.
+type ProvisionMembershipRequest struct {
+	ExternalRef    string
+	StudentNumber  string
+	FirstName      string
+	LastName       string
+	MembershipTier int
+	Source         MembershipSource
+	PurchasedAt    time.Time
+}
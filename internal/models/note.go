@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// MaxNoteLength is the longest note body accepted, in characters
+const MaxNoteLength = 2000
+
+// NoteCategory is what kind of note an exec left on a member
+type NoteCategory string
+
+const (
+	NoteCategoryInfo     NoteCategory = "info"
+	NoteCategoryWarning  NoteCategory = "warning"
+	NoteCategoryIncident NoteCategory = "incident"
+)
+
+func (c NoteCategory) IsValid() bool {
+	switch c {
+	case NoteCategoryInfo, NoteCategoryWarning, NoteCategoryIncident:
+		return true
+	}
+	return false
+}
+
+// MemberNote is one entry in a member's notes timeline. Notes are archived
+// rather than edited or deleted. The latest pinned note that isn't archived
+// is also shown as the notes of the profile.
+type MemberNote struct {
+	ID               string       `json:"id"`
+	StudentNumber    string       `json:"student_number"`
+	Body             string       `json:"body"`
+	Category         NoteCategory `json:"category"`
+	Pinned           bool         `json:"pinned"`
+	AuthorApp        *string      `json:"author_app,omitempty"`
+	AuthorExecID     *string      `json:"author_exec_id,omitempty"`
+	CreatedAt        time.Time    `json:"created_at"`
+	ArchivedAt       *time.Time   `json:"archived_at,omitempty"`
+	ArchivedByApp    *string      `json:"archived_by_app,omitempty"`
+	ArchivedByExecID *string      `json:"archived_by_exec_id,omitempty"`
+}
+
+// CreateMemberNoteRequest adds a note to a member. Category defaults to
+// info.
+type CreateMemberNoteRequest struct {
+	Body     string       `json:"body"`
+	Category NoteCategory `json:"category,omitempty"`
+	Pinned   bool         `json:"pinned"`
+}
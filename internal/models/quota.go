@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// PlayQuota is how much of a member's daily play time is used on the
+// lounge's current day. LimitMs and RemainingMs are null for tiers without
+// a daily limit.
+type PlayQuota struct {
+	StudentNumber  string    `json:"student_number"`
+	MembershipTier int       `json:"membership_tier"`
+	DailyLimit     bool      `json:"daily_limit"`
+	UsedMs         int64     `json:"used_ms"`
+	LimitMs        *int64    `json:"limit_ms"`
+	RemainingMs    *int64    `json:"remaining_ms"`
+	ResetsAt       time.Time `json:"resets_at"`
+}
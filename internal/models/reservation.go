@@ -0,0 +1,84 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// ReservationStatus is where a reservation is in its life. Booked and
+// checked in reservations hold their stations; cancelled and no-show ones
+// have given them up.
+type ReservationStatus string
+
+const (
+	ReservationStatusBooked    ReservationStatus = "booked"
+	ReservationStatusCheckedIn ReservationStatus = "checked_in"
+	ReservationStatusCancelled ReservationStatus = "cancelled"
+	ReservationStatusNoShow    ReservationStatus = "no_show"
+)
+
+func (s ReservationStatus) IsValid() bool {
+	switch s {
+	case ReservationStatusBooked, ReservationStatusCheckedIn, ReservationStatusCancelled, ReservationStatusNoShow:
+		return true
+	}
+	return false
+}
+
+// IsActive reports whether a reservation in this status still holds its
+// stations
+func (s ReservationStatus) IsActive() bool {
+	return s == ReservationStatusBooked || s == ReservationStatusCheckedIn
+}
+
+// Reservation books one or more stations ahead of time for a member, or for
+// a team of members. Only the members it is held for can start sessions on
+// its stations during its window.
+type Reservation struct {
+	ID             string            `json:"id"`
+	StationNumbers []int             `json:"station_numbers"`
+	StudentNumbers []string          `json:"student_numbers"`
+	TeamName       *string           `json:"team_name,omitempty"`
+	StartsAt       time.Time         `json:"starts_at"`
+	EndsAt         time.Time         `json:"ends_at"`
+	Status         ReservationStatus `json:"status"`
+	Note           *string           `json:"note,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	CheckedInAt    *time.Time        `json:"checked_in_at,omitempty"`
+	CancelledAt    *time.Time        `json:"cancelled_at,omitempty"`
+	ReleasedAt     *time.Time        `json:"released_at,omitempty"`
+}
+
+// IsHeldFor reports whether the member can use the reservation
+func (r *Reservation) IsHeldFor(studentNumber string) bool {
+	return slices.Contains(r.StudentNumbers, studentNumber)
+}
+
+// Covers reports whether t falls within the reservation's window
+func (r *Reservation) Covers(t time.Time) bool {
+	return !t.Before(r.StartsAt) && t.Before(r.EndsAt)
+}
+
+// CreateReservationRequest books stations for a member, or for a team when
+// a team name is given along with every member on it
+type CreateReservationRequest struct {
+	StationNumbers []int     `json:"station_numbers"`
+	StudentNumbers []string  `json:"student_numbers"`
+	TeamName       *string   `json:"team_name"`
+	StartsAt       time.Time `json:"starts_at"`
+	EndsAt         time.Time `json:"ends_at"`
+	Note           *string   `json:"note"`
+}
+
+// ReservationFilter narrows a reservation listing. Empty fields match
+// everything.
+type ReservationFilter struct {
+	// From and To match reservations whose window overlaps them
+	From          *time.Time
+	To            *time.Time
+	Status        ReservationStatus
+	StationNumber *int
+	StudentNumber string
+	Page          int
+	Limit         int
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// ShowpassTicketTiers maps the Showpass membership ticket types to
+// membership tiers
+func ShowpassTicketTiers() map[string]int {
+	return map[string]int{
+		"Tier 1 Membership":  1,
+		"Tier 2 Membership":  2,
+		"Premier Membership": 3,
+	}
+}
+
+// ShowpassEventPurchase is sent by Showpass when a purchase is completed
+const ShowpassEventPurchase = "purchase.completed"
+
+// ShowpassWebhook is the body of a Showpass webhook delivery
+type ShowpassWebhook struct {
+	Event    string           `json:"event"`
+	Purchase ShowpassPurchase `json:"purchase"`
+}
+
+// ShowpassPurchase is one ticket bought through Showpass. The student number
+// is answered by the buyer at checkout.
+type ShowpassPurchase struct {
+	ID            string     `json:"id"`
+	TicketType    string     `json:"ticket_type"`
+	StudentNumber string     `json:"student_number"`
+	FirstName     string     `json:"first_name"`
+	LastName      string     `json:"last_name"`
+	PurchasedAt   *time.Time `json:"purchased_at,omitempty"`
+}
+
+// ShowpassWebhookStatus is what a webhook delivery did
+type ShowpassWebhookStatus string
+
+const (
+	ShowpassWebhookProvisioned ShowpassWebhookStatus = "provisioned"
+	// ShowpassWebhookDuplicate marks a purchase that was already recorded
+	ShowpassWebhookDuplicate ShowpassWebhookStatus = "duplicate"
+	// ShowpassWebhookIgnored marks events and tickets that aren't
+	// memberships
+	ShowpassWebhookIgnored ShowpassWebhookStatus = "ignored"
+)
+
+type ShowpassWebhookResult struct {
+	Status     ShowpassWebhookStatus `json:"status"`
+	Membership *Membership           `json:"membership,omitempty"`
+}
@@ -0,0 +1,96 @@
+package models
+
+import "time"
+
+// StationType is the kind of machine at a station
+type StationType string
+
+const (
+	StationTypePC      StationType = "pc"
+	StationTypeConsole StationType = "console"
+	StationTypeVR      StationType = "vr"
+)
+
+func (t StationType) IsValid() bool {
+	switch t {
+	case StationTypePC, StationTypeConsole, StationTypeVR:
+		return true
+	}
+	return false
+}
+
+// StationStatus is whether a station can be played on. Retired stations are
+// kept so their past sessions still make sense.
+type StationStatus string
+
+const (
+	StationStatusAvailable   StationStatus = "available"
+	StationStatusMaintenance StationStatus = "maintenance"
+	StationStatusRetired     StationStatus = "retired"
+)
+
+func (s StationStatus) IsValid() bool {
+	switch s {
+	case StationStatusAvailable, StationStatusMaintenance, StationStatusRetired:
+		return true
+	}
+	return false
+}
+
+// Station is a PC, console or VR setup in the lounge. Its number is the
+// pc_number sessions are started on.
+type Station struct {
+	Number       int               `json:"number"`
+	Zone         *string           `json:"zone,omitempty"`
+	Type         StationType       `json:"type"`
+	Specs        map[string]string `json:"specs"`
+	Status       StationStatus     `json:"status"`
+	StatusReason *string           `json:"status_reason,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+// CreateStationRequest adds a station. Type defaults to pc and status to
+// available.
+type CreateStationRequest struct {
+	Number       int               `json:"number"`
+	Zone         *string           `json:"zone"`
+	Type         StationType       `json:"type"`
+	Specs        map[string]string `json:"specs"`
+	Status       StationStatus     `json:"status"`
+	StatusReason *string           `json:"status_reason"`
+}
+
+// UpdateStationRequest is a merge patch of a station. The status reason is
+// cleared when a station is made available again unless a new one is sent.
+type UpdateStationRequest struct {
+	Zone         PatchField[string]            `json:"zone"`
+	Type         PatchField[StationType]       `json:"type"`
+	Specs        PatchField[map[string]string] `json:"specs"`
+	Status       PatchField[StationStatus]     `json:"status"`
+	StatusReason PatchField[string]            `json:"status_reason"`
+}
+
+// UpdateStationStatusRequest takes a station in or out of service. The status
+// reason is cleared when a station is made available again unless a new one
+// is sent.
+type UpdateStationStatusRequest struct {
+	Status       StationStatus `json:"status"`
+	StatusReason *string       `json:"status_reason"`
+}
+
+// StationState is what a station looks like from the front desk
+type StationState string
+
+const (
+	StationStateFree       StationState = "free"
+	StationStateOccupied   StationState = "occupied"
+	StationStateOutOfOrder StationState = "out_of_order"
+)
+
+// StationView is a station with the session being played on it, if any
+type StationView struct {
+	Station
+	State   StationState   `json:"state"`
+	Session *GamerActivity `json:"session,omitempty"`
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/ubcesports/echo-base/internal/handlers"
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
+	"github.com/ubcesports/echo-base/internal/middleware"
 	"github.com/ubcesports/echo-base/internal/services"
 )
 
@@ -12,20 +14,84 @@ func AddRoutes(
 	authService services.AuthService,
 	gamerProfileService services.GamerProfileService,
 	gamerActivityService services.GamerActivityService,
+	auditService services.AuditService,
+	execService services.ExecService,
+	showpassService services.ShowpassService,
+	memberExportService services.MemberExportService,
+	stationService services.StationService,
+	reservationService services.ReservationService,
+	limiter *middleware.RateLimiter,
 ) {
+	// protected requires a valid API key granted scope. Routes registered
+	// without it are public and must do their own authentication if needed,
+	// and are never rejected by the failed auth limit.
+	protected := func(scope auth.Scope, h http.Handler) http.Handler {
+		h = middleware.AuditRoute(h)
+		h = middleware.RequireScope(scope, h)
+		h = middleware.RateLimitMiddleware(h, limiter)
+		h = middleware.ExecTokenMiddleware(h, execService)
+		h = middleware.AuthMiddleware(h, authService)
+		return middleware.FailedAuthRateLimitMiddleware(h, limiter)
+	}
+
+	// Public routes
 	mux.HandleFunc("/health", handlers.HealthCheck)
 	mux.HandleFunc("/db/ping", handlers.DatabasePing)
-	mux.Handle("POST /admin/generate-key", handlers.GenerateAPIKey(authService))
-
-	mux.Handle("GET /v1/api/gamer/{student_number}", handlers.GetGamerProfile(gamerProfileService))
-	mux.Handle("POST /v1/api/gamer", handlers.CreateOrUpdateGamerProfile(gamerProfileService))
-	mux.Handle("DELETE /v1/api/gamer/{student_number}", handlers.DeleteGamerProfile(gamerProfileService))
-
-	mux.Handle("GET /v1/api/activity/{student_number}", handlers.GetActivityByStudent(gamerActivityService))
-	mux.Handle("GET /v1/api/activity/today/{student_number}", handlers.GetTodayActivityByStudent(gamerActivityService))
-	mux.Handle("GET /v1/api/activity/all/recent", handlers.GetRecentActivities(gamerActivityService))
-	mux.Handle("POST /v1/api/activity", handlers.StartActivity(gamerActivityService))
-	mux.Handle("PATCH /v1/api/activity/update/{student_number}", handlers.EndActivity(gamerActivityService))
-	mux.Handle("GET /v1/api/activity/all/get-active-pcs", handlers.GetActiveSessions(gamerActivityService))
-	mux.Handle("GET /v1/api/activity/all/leaderboard", handlers.GetExecLeaderboard(gamerActivityService))
+	mux.Handle("POST /webhooks/showpass", middleware.AuditRoute(handlers.ShowpassWebhook(showpassService)))
+
+	mux.Handle("POST /admin/generate-key", protected(auth.ScopeAdmin, handlers.GenerateAPIKey(authService)))
+	mux.Handle("GET /admin/keys", protected(auth.ScopeAdmin, handlers.ListAPIKeys(authService)))
+	mux.Handle("POST /admin/keys/{key_id}/revoke", protected(auth.ScopeAdmin, handlers.RevokeAPIKey(authService)))
+	mux.Handle("POST /admin/keys/{key_id}/rotate", protected(auth.ScopeAdmin, handlers.RotateAPIKey(authService)))
+	mux.Handle("PUT /admin/keys/{key_id}/rate-limit", protected(auth.ScopeAdmin, handlers.UpdateAPIKeyRateLimit(authService)))
+	mux.Handle("DELETE /admin/keys/{key_id}/rate-limit", protected(auth.ScopeAdmin, handlers.ResetAPIKeyRateLimit(authService)))
+	mux.Handle("PUT /admin/keys/{key_id}/auth-mode", protected(auth.ScopeAdmin, handlers.UpdateAPIKeyAuthMode(authService)))
+	mux.Handle("PUT /admin/keys/{key_id}/allowed-cidrs", protected(auth.ScopeAdmin, handlers.UpdateAPIKeyAllowedCIDRs(authService)))
+
+	mux.Handle("POST /admin/execs", protected(auth.ScopeAdmin, handlers.CreateExec(execService)))
+	mux.Handle("GET /admin/execs", protected(auth.ScopeAdmin, handlers.ListExecs(execService)))
+	mux.Handle("PATCH /admin/execs/{exec_id}", protected(auth.ScopeAdmin, handlers.UpdateExec(execService)))
+
+	mux.Handle("POST /v1/api/exec/login", protected(auth.ScopeActivityWrite, handlers.ExecLogin(execService)))
+
+	mux.Handle("GET /v1/api/gamer", protected(auth.ScopeProfilesRead, handlers.ListGamerProfiles(gamerProfileService)))
+	mux.Handle("GET /v1/api/gamer/{student_number}", protected(auth.ScopeProfilesRead, handlers.GetGamerProfile(gamerProfileService)))
+	mux.Handle("POST /v1/api/gamer", protected(auth.ScopeProfilesWrite, handlers.CreateOrUpdateGamerProfile(gamerProfileService)))
+	mux.Handle("POST /v1/api/gamer/import", protected(auth.ScopeProfilesWrite, handlers.ImportGamerProfiles(gamerProfileService)))
+	mux.Handle("PATCH /v1/api/gamer/{student_number}", protected(auth.ScopeProfilesWrite, handlers.UpdateGamerProfile(gamerProfileService)))
+	mux.Handle("DELETE /v1/api/gamer/{student_number}", protected(auth.ScopeProfilesWrite, handlers.DeleteGamerProfile(gamerProfileService)))
+	mux.Handle("POST /v1/api/gamer/{student_number}/restore", protected(auth.ScopeProfilesWrite, handlers.RestoreGamerProfile(gamerProfileService)))
+	mux.Handle("POST /v1/api/gamer/{student_number}/erase", protected(auth.ScopeAdmin, handlers.EraseGamerProfile(gamerProfileService)))
+	mux.Handle("GET /v1/api/gamer/{student_number}/export", protected(auth.ScopeAdmin, handlers.ExportGamerProfile(memberExportService)))
+	mux.Handle("GET /v1/api/gamer/{student_number}/memberships", protected(auth.ScopeProfilesRead, handlers.ListMemberships(gamerProfileService)))
+	mux.Handle("POST /v1/api/gamer/{student_number}/memberships", protected(auth.ScopeProfilesWrite, handlers.RenewMembership(gamerProfileService)))
+	mux.Handle("GET /v1/api/gamer/{student_number}/bans", protected(auth.ScopeProfilesRead, handlers.ListBans(gamerProfileService)))
+	mux.Handle("POST /v1/api/gamer/{student_number}/bans", protected(auth.ScopeProfilesWrite, handlers.IssueBan(gamerProfileService)))
+	mux.Handle("POST /v1/api/gamer/{student_number}/bans/{ban_id}/lift", protected(auth.ScopeProfilesWrite, handlers.LiftBan(gamerProfileService)))
+	mux.Handle("GET /v1/api/gamer/{student_number}/quota", protected(auth.ScopeActivityRead, handlers.GetPlayQuota(gamerActivityService)))
+	mux.Handle("GET /v1/api/gamer/{student_number}/notes", protected(auth.ScopeProfilesRead, handlers.ListNotes(gamerProfileService)))
+	mux.Handle("POST /v1/api/gamer/{student_number}/notes", protected(auth.ScopeProfilesWrite, handlers.AddNote(gamerProfileService)))
+	mux.Handle("POST /v1/api/gamer/{student_number}/notes/{note_id}/archive", protected(auth.ScopeProfilesWrite, handlers.ArchiveNote(gamerProfileService)))
+
+	mux.Handle("GET /v1/api/activity/{student_number}", protected(auth.ScopeActivityRead, handlers.GetActivityByStudent(gamerActivityService)))
+	mux.Handle("GET /v1/api/activity/today/{student_number}", protected(auth.ScopeActivityRead, handlers.GetTodayActivityByStudent(gamerActivityService)))
+	mux.Handle("GET /v1/api/activity/all/recent", protected(auth.ScopeActivityRead, handlers.GetRecentActivities(gamerActivityService)))
+	mux.Handle("POST /v1/api/activity", protected(auth.ScopeActivityWrite, handlers.StartActivity(gamerActivityService)))
+	mux.Handle("PATCH /v1/api/activity/update/{student_number}", protected(auth.ScopeActivityWrite, handlers.EndActivity(gamerActivityService)))
+	mux.Handle("GET /v1/api/activity/all/get-active-pcs", protected(auth.ScopeActivityRead, handlers.GetActiveSessions(gamerActivityService)))
+	mux.Handle("GET /v1/api/activity/all/leaderboard", protected(auth.ScopeActivityRead, handlers.GetExecLeaderboard(gamerActivityService)))
+
+	mux.Handle("GET /v1/api/stations", protected(auth.ScopeActivityRead, handlers.GetStationOverview(stationService)))
+	mux.Handle("GET /v1/api/stations/{number}", protected(auth.ScopeActivityRead, handlers.GetStation(stationService)))
+	mux.Handle("POST /v1/api/stations", protected(auth.ScopeAdmin, handlers.CreateStation(stationService)))
+	mux.Handle("PATCH /v1/api/stations/{number}", protected(auth.ScopeAdmin, handlers.UpdateStation(stationService)))
+	mux.Handle("POST /v1/api/stations/{number}/status", protected(auth.ScopeActivityWrite, handlers.SetStationStatus(stationService)))
+	mux.Handle("DELETE /v1/api/stations/{number}", protected(auth.ScopeAdmin, handlers.DeleteStation(stationService)))
+
+	mux.Handle("GET /v1/api/reservations", protected(auth.ScopeActivityRead, handlers.ListReservations(reservationService)))
+	mux.Handle("GET /v1/api/reservations/{reservation_id}", protected(auth.ScopeActivityRead, handlers.GetReservation(reservationService)))
+	mux.Handle("POST /v1/api/reservations", protected(auth.ScopeActivityWrite, handlers.CreateReservation(reservationService)))
+	mux.Handle("POST /v1/api/reservations/{reservation_id}/cancel", protected(auth.ScopeActivityWrite, handlers.CancelReservation(reservationService)))
+
+	mux.Handle("GET /v1/api/audit", protected(auth.ScopeAuditRead, handlers.ListAuditEntries(auditService)))
 }
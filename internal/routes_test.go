@@ -0,0 +1,134 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
+	"github.com/ubcesports/echo-base/internal/services"
+)
+
+// rejectingAuthService fails every key. Other methods are never reached
+// because the requests never get past authentication.
+type rejectingAuthService struct {
+	services.AuthService
+}
+
+func (s *rejectingAuthService) ValidateAPIKey(ctx context.Context, apiKey string, clientIP string) (*auth.Application, error) {
+	return nil, fmt.Errorf("invalid api key")
+}
+
+func TestProtectedRoutesRequireAuth(t *testing.T) {
+	server := NewServer(&rejectingAuthService{}, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	routes := []struct {
+		method string
+		path   string
+	}{
+		{"POST", "/admin/generate-key"},
+		{"GET", "/admin/keys"},
+		{"POST", "/admin/keys/abc/revoke"},
+		{"POST", "/admin/keys/abc/rotate"},
+		{"PUT", "/admin/keys/abc/rate-limit"},
+		{"DELETE", "/admin/keys/abc/rate-limit"},
+		{"PUT", "/admin/keys/abc/auth-mode"},
+		{"PUT", "/admin/keys/abc/allowed-cidrs"},
+		{"POST", "/admin/execs"},
+		{"GET", "/admin/execs"},
+		{"PATCH", "/admin/execs/abc"},
+		{"POST", "/v1/api/exec/login"},
+		{"GET", "/v1/api/gamer"},
+		{"GET", "/v1/api/gamer/12345678"},
+		{"POST", "/v1/api/gamer"},
+		{"POST", "/v1/api/gamer/import"},
+		{"PATCH", "/v1/api/gamer/12345678"},
+		{"DELETE", "/v1/api/gamer/12345678"},
+		{"POST", "/v1/api/gamer/12345678/restore"},
+		{"POST", "/v1/api/gamer/12345678/erase"},
+		{"GET", "/v1/api/gamer/12345678/memberships"},
+		{"POST", "/v1/api/gamer/12345678/memberships"},
+		{"GET", "/v1/api/gamer/12345678/bans"},
+		{"POST", "/v1/api/gamer/12345678/bans"},
+		{"POST", "/v1/api/gamer/12345678/bans/abc/lift"},
+		{"GET", "/v1/api/gamer/12345678/notes"},
+		{"POST", "/v1/api/gamer/12345678/notes"},
+		{"POST", "/v1/api/gamer/12345678/notes/abc/archive"},
+		{"GET", "/v1/api/gamer/12345678/export"},
+		{"GET", "/v1/api/gamer/12345678/quota"},
+		{"GET", "/v1/api/activity/12345678"},
+		{"GET", "/v1/api/activity/today/12345678"},
+		{"GET", "/v1/api/activity/all/recent"},
+		{"POST", "/v1/api/activity"},
+		{"PATCH", "/v1/api/activity/update/12345678"},
+		{"GET", "/v1/api/activity/all/get-active-pcs"},
+		{"GET", "/v1/api/activity/all/leaderboard"},
+		{"GET", "/v1/api/stations"},
+		{"GET", "/v1/api/stations/1"},
+		{"POST", "/v1/api/stations"},
+		{"PATCH", "/v1/api/stations/1"},
+		{"POST", "/v1/api/stations/1/status"},
+		{"DELETE", "/v1/api/stations/1"},
+		{"GET", "/v1/api/reservations"},
+		{"GET", "/v1/api/reservations/abc"},
+		{"POST", "/v1/api/reservations"},
+		{"POST", "/v1/api/reservations/abc/cancel"},
+		{"GET", "/v1/api/audit"},
+	}
+
+	for i, route := range routes {
+		t.Run(route.method+" "+route.path, func(t *testing.T) {
+			for _, header := range []string{"", "Bearer api_abc.wrong"} {
+				req := httptest.NewRequest(route.method, route.path, nil)
+				// Spread requests over addresses so the failed auth limit isn't hit
+				req.RemoteAddr = fmt.Sprintf("10.0.0.%d:1234", i+1)
+				if header != "" {
+					req.Header.Set("Authorization", header)
+				}
+				rr := httptest.NewRecorder()
+				server.ServeHTTP(rr, req)
+
+				assert.Equal(t, http.StatusUnauthorized, rr.Code)
+			}
+		})
+	}
+}
+
+func TestPublicRoutesSkipAuth(t *testing.T) {
+	server := NewServer(&rejectingAuthService{}, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	for _, path := range []string{"/health", "/db/ping"} {
+		t.Run(path, func(t *testing.T) {
+			req := httptest.NewRequest("GET", path, nil)
+			rr := httptest.NewRecorder()
+			server.ServeHTTP(rr, req)
+
+			assert.NotEqual(t, http.StatusUnauthorized, rr.Code)
+			assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		})
+	}
+}
+
+func TestPublicRoutesIgnoreFailedAuthLimit(t *testing.T) {
+	server := NewServer(&rejectingAuthService{}, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	send := func(method, path string) int {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = "10.0.1.1:1234"
+		req.Header.Set("Authorization", "Bearer api_abc.wrong")
+		rr := httptest.NewRecorder()
+		server.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	for i := 0; i < 20; i++ {
+		send("GET", "/v1/api/gamer")
+	}
+	assert.Equal(t, http.StatusTooManyRequests, send("GET", "/v1/api/gamer"))
+
+	// A locked out client can still be health checked
+	assert.NotEqual(t, http.StatusTooManyRequests, send("GET", "/health"))
+}
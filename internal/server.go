@@ -2,6 +2,7 @@ package internal
 
 import (
 	"net/http"
+	"net/netip"
 
 	"github.com/ubcesports/echo-base/internal/middleware"
 	"github.com/ubcesports/echo-base/internal/services"
@@ -11,17 +12,35 @@ func NewServer(
 	authService services.AuthService,
 	gamerProfileService services.GamerProfileService,
 	gamerActivityService services.GamerActivityService,
+	auditService services.AuditService,
+	execService services.ExecService,
+	showpassService services.ShowpassService,
+	memberExportService services.MemberExportService,
+	stationService services.StationService,
+	reservationService services.ReservationService,
+	trustedProxies []netip.Prefix,
 ) http.Handler {
+	limiter := middleware.NewRateLimiter()
+
 	mux := http.NewServeMux()
 	AddRoutes(
 		mux,
 		authService,
 		gamerProfileService,
 		gamerActivityService,
+		auditService,
+		execService,
+		showpassService,
+		memberExportService,
+		stationService,
+		reservationService,
+		limiter,
 	)
 
+	// Authentication and the failed auth limit are applied per route in
+	// AddRoutes so public routes stay reachable without an API key
 	var handler http.Handler = mux
-	handler = middleware.AuthMiddleware(handler, authService)
+	handler = middleware.ClientIPMiddleware(handler, trustedProxies)
 
 	return handler
 
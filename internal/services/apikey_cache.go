@@ -0,0 +1,123 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
+)
+
+// apiKeyCache remembers recently validated API keys so that repeat requests
+// from the same client don't need a database round trip. Entries are keyed
+// by the hash of the full API key, so a cache hit implies the caller
+// presented a secret that has already been verified.
+//
+// Each key id has a generation that Invalidate bumps. Callers read it before
+// loading a key and pass it to Put, so a key loaded before it was revoked or
+// rotated is never cached afterwards.
+type apiKeyCache struct {
+	mu          sync.Mutex
+	ttl         time.Duration
+	capacity    int
+	entries     map[string]apiKeyCacheEntry
+	generations map[string]uint64
+}
+
+type apiKeyCacheEntry struct {
+	app        *auth.Application
+	validUntil time.Time
+}
+
+func newAPIKeyCache(ttl time.Duration, capacity int) *apiKeyCache {
+	return &apiKeyCache{
+		ttl:         ttl,
+		capacity:    capacity,
+		entries:     make(map[string]apiKeyCacheEntry),
+		generations: make(map[string]uint64),
+	}
+}
+
+func (c *apiKeyCache) Get(keyHash string, now time.Time) (*auth.Application, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, exists := c.entries[keyHash]
+	if !exists {
+		return nil, false
+	}
+	if !now.Before(entry.validUntil) {
+		delete(c.entries, keyHash)
+		return nil, false
+	}
+	return entry.app, true
+}
+
+// Generation returns the current generation of keyId, to be passed to Put
+// once the key has been loaded
+func (c *apiKeyCache) Generation(keyId string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.generations[keyId]
+}
+
+// Put caches app until the TTL elapses or validUntil, whichever is sooner.
+// Nothing is cached if the key was invalidated since generation was read.
+func (c *apiKeyCache) Put(keyHash string, app *auth.Application, generation uint64, now time.Time, validUntil *time.Time) {
+	if c.capacity <= 0 || c.ttl <= 0 {
+		return
+	}
+
+	expiry := now.Add(c.ttl)
+	if validUntil != nil && validUntil.Before(expiry) {
+		expiry = *validUntil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generations[app.KeyId] != generation {
+		return
+	}
+	if _, exists := c.entries[keyHash]; !exists && len(c.entries) >= c.capacity {
+		c.evict(now)
+	}
+	c.entries[keyHash] = apiKeyCacheEntry{app: app, validUntil: expiry}
+}
+
+// Invalidate drops every cached secret belonging to keyId and stops copies
+// of the key loaded before now from being cached
+func (c *apiKeyCache) Invalidate(keyId string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generations[keyId]++
+
+	for keyHash, entry := range c.entries {
+		if entry.app.KeyId == keyId {
+			delete(c.entries, keyHash)
+		}
+	}
+}
+
+// evict removes expired entries, falling back to the entry closest to
+// expiring when the cache is still full. Callers must hold c.mu.
+func (c *apiKeyCache) evict(now time.Time) {
+	var oldestHash string
+	var oldest time.Time
+
+	for keyHash, entry := range c.entries {
+		if !now.Before(entry.validUntil) {
+			delete(c.entries, keyHash)
+			continue
+		}
+		if oldestHash == "" || entry.validUntil.Before(oldest) {
+			oldestHash = keyHash
+			oldest = entry.validUntil
+		}
+	}
+
+	if len(c.entries) >= c.capacity && oldestHash != "" {
+		delete(c.entries, oldestHash)
+	}
+}
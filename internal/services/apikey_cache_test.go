@@ -0,0 +1,91 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
+)
+
+func TestAPIKeyCacheExpiry(t *testing.T) {
+	cache := newAPIKeyCache(time.Minute, 10)
+	now := time.Now()
+	app := &auth.Application{KeyId: "abc"}
+
+	cache.Put("hash", app, 0, now, nil)
+
+	got, ok := cache.Get("hash", now.Add(30*time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, app, got)
+
+	_, ok = cache.Get("hash", now.Add(time.Minute))
+	assert.False(t, ok)
+}
+
+func TestAPIKeyCacheRespectsValidUntil(t *testing.T) {
+	cache := newAPIKeyCache(time.Minute, 10)
+	now := time.Now()
+	keyExpiry := now.Add(10 * time.Second)
+
+	cache.Put("hash", &auth.Application{KeyId: "abc"}, 0, now, &keyExpiry)
+
+	_, ok := cache.Get("hash", now.Add(5*time.Second))
+	assert.True(t, ok)
+
+	_, ok = cache.Get("hash", now.Add(10*time.Second))
+	assert.False(t, ok)
+}
+
+func TestAPIKeyCacheEviction(t *testing.T) {
+	cache := newAPIKeyCache(time.Minute, 2)
+	now := time.Now()
+
+	cache.Put("first", &auth.Application{KeyId: "a"}, 0, now, nil)
+	cache.Put("second", &auth.Application{KeyId: "b"}, 0, now.Add(time.Second), nil)
+	cache.Put("third", &auth.Application{KeyId: "c"}, 0, now.Add(2*time.Second), nil)
+
+	assert.Len(t, cache.entries, 2)
+
+	_, ok := cache.Get("first", now.Add(2*time.Second))
+	assert.False(t, ok, "entry closest to expiry should be evicted")
+
+	_, ok = cache.Get("third", now.Add(2*time.Second))
+	assert.True(t, ok)
+}
+
+func TestAPIKeyCacheInvalidate(t *testing.T) {
+	cache := newAPIKeyCache(time.Minute, 10)
+	now := time.Now()
+
+	// A rotated key can have both its old and new secret cached
+	cache.Put("old-secret", &auth.Application{KeyId: "abc"}, 0, now, nil)
+	cache.Put("new-secret", &auth.Application{KeyId: "abc"}, 0, now, nil)
+	cache.Put("other", &auth.Application{KeyId: "xyz"}, 0, now, nil)
+
+	cache.Invalidate("abc")
+
+	_, ok := cache.Get("old-secret", now)
+	assert.False(t, ok)
+	_, ok = cache.Get("new-secret", now)
+	assert.False(t, ok)
+	_, ok = cache.Get("other", now)
+	assert.True(t, ok)
+}
+
+func TestAPIKeyCacheSkipsInvalidatedPut(t *testing.T) {
+	cache := newAPIKeyCache(time.Minute, 10)
+	now := time.Now()
+
+	// The key is invalidated between being loaded and being cached
+	generation := cache.Generation("abc")
+	cache.Invalidate("abc")
+	cache.Put("hash", &auth.Application{KeyId: "abc"}, generation, now, nil)
+
+	_, ok := cache.Get("hash", now)
+	assert.False(t, ok)
+
+	cache.Put("hash", &auth.Application{KeyId: "abc"}, cache.Generation("abc"), now, nil)
+	_, ok = cache.Get("hash", now)
+	assert.True(t, ok)
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/interfaces/audit"
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+// Audit actions
+const (
+	AuditProfileUpsert       = "profile.upsert"
+	AuditProfileUpdate       = "profile.update"
+	AuditProfileDelete       = "profile.delete"
+	AuditProfileRestore      = "profile.restore"
+	AuditProfileErase        = "profile.erase"
+	AuditProfileExport       = "profile.export"
+	AuditMembershipRenew     = "membership.renew"
+	AuditMembershipProvision = "membership.provision"
+	AuditBanIssue            = "ban.issue"
+	AuditBanLift             = "ban.lift"
+	AuditNoteAdd             = "note.add"
+	AuditNoteArchive         = "note.archive"
+	AuditSessionStart        = "session.start"
+	AuditSessionEnd          = "session.end"
+	AuditSessionOverdue      = "session.overdue"
+	AuditKeyGenerate         = "api_key.generate"
+	AuditKeyRevoke           = "api_key.revoke"
+	AuditKeyRotate           = "api_key.rotate"
+	AuditKeyRateLimit        = "api_key.rate_limit"
+	AuditKeyAuthMode         = "api_key.auth_mode"
+	AuditKeyAllowedCIDRs     = "api_key.allowed_cidrs"
+	AuditAuthLockout         = "auth.lockout"
+)
+
+const MaxAuditEntriesPerPage = 100
+
+// AuditRecorder records a change to an entity. Recording is best effort:
+// failures are logged rather than failing the change being audited.
+type AuditRecorder interface {
+	Record(ctx context.Context, action, entityType, entityID string, before, after any)
+}
+
+type AuditService interface {
+	AuditRecorder
+	ListEntries(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error)
+	ListEntriesForStudent(ctx context.Context, studentNumber string) ([]models.AuditEntry, error)
+}
+
+type auditService struct {
+	repo audit.AuditRepository
+}
+
+func NewAuditService(repo audit.AuditRepository) AuditService {
+	return &auditService{repo: repo}
+}
+
+func (s *auditService) Record(ctx context.Context, action, entityType, entityID string, before, after any) {
+	entry, err := newAuditEntry(ctx, action, entityType, entityID, before, after)
+	if err == nil {
+		err = s.repo.Create(ctx, entry)
+	}
+	if err != nil {
+		log.Printf("failed to record audit entry %s %s/%s: %v", action, entityType, entityID, err)
+	}
+}
+
+func (s *auditService) ListEntries(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	if filter.Page < 1 {
+		return nil, errors.NewValidationError("page", "must be >= 1")
+	}
+
+	if filter.Limit < 1 || filter.Limit > MaxAuditEntriesPerPage {
+		return nil, errors.NewValidationError("limit", fmt.Sprintf("must be between 1 and %d", MaxAuditEntriesPerPage))
+	}
+
+	if filter.ActorExecID != "" {
+		if _, err := uuid.Parse(filter.ActorExecID); err != nil {
+			return nil, errors.NewValidationError("exec", "must be an exec id")
+		}
+	}
+
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, errors.NewValidationError("from", "must be before to")
+	}
+
+	return s.repo.List(ctx, filter)
+}
+
+// ListEntriesForStudent returns every entry about a member, oldest first,
+// including changes to their memberships, bans, notes and sessions
+func (s *auditService) ListEntriesForStudent(ctx context.Context, studentNumber string) ([]models.AuditEntry, error) {
+	if err := validateStudentNumber(studentNumber); err != nil {
+		return nil, err
+	}
+
+	return s.repo.ListForStudent(ctx, studentNumber)
+}
+
+// deferredAudit holds audit entries until the changes they describe are
+// committed
+type deferredAudit struct {
+	entries []deferredAuditEntry
+}
+
+type deferredAuditEntry struct {
+	action, entityType, entityID string
+	before, after                any
+}
+
+func (a *deferredAudit) Record(ctx context.Context, action, entityType, entityID string, before, after any) {
+	a.entries = append(a.entries, deferredAuditEntry{action, entityType, entityID, before, after})
+}
+
+func (a *deferredAudit) flush(ctx context.Context, recorder AuditRecorder) {
+	for _, e := range a.entries {
+		recorder.Record(ctx, e.action, e.entityType, e.entityID, e.before, e.after)
+	}
+}
+
+// newAuditEntry attributes a change to the calling application, exec and route.
+// Only the fields that changed are kept in before and after.
+func newAuditEntry(ctx context.Context, action, entityType, entityID string, before, after any) (*models.AuditEntry, error) {
+	beforeJSON, afterJSON, err := diffJSON(before, after)
+	if err != nil {
+		return nil, err
+	}
+
+	route := audit.RouteFromContext(ctx)
+	entry := &models.AuditEntry{
+		Method:     route.Method,
+		Route:      route.Pattern,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     beforeJSON,
+		After:      afterJSON,
+		CreatedAt:  time.Now(),
+	}
+	if appName := auth.AppNameFromContext(ctx); appName != "" {
+		entry.ActorApp = &appName
+	}
+	if exec := auth.ExecFromContext(ctx); exec != nil {
+		entry.ActorExecID = &exec.ID
+	}
+	return entry, nil
+}
+
+// diffJSON marshals before and after, dropping top level fields that are the
+// same in both. A nil side is encoded as JSON null.
+func diffJSON(before, after any) (json.RawMessage, json.RawMessage, error) {
+	beforeFields, err := toJSONFields(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterFields, err := toJSONFields(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if beforeFields != nil && afterFields != nil {
+		for field, value := range beforeFields {
+			if otherValue, exists := afterFields[field]; exists && reflect.DeepEqual(value, otherValue) {
+				delete(beforeFields, field)
+				delete(afterFields, field)
+			}
+		}
+	}
+
+	beforeJSON, err := json.Marshal(beforeFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterJSON, err := json.Marshal(afterFields)
+	if err != nil {
+		return nil, nil, err
+	}
+	return beforeJSON, afterJSON, nil
+}
+
+func toJSONFields(v any) (map[string]any, error) {
+	if v == nil || reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil() {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
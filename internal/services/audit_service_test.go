@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ubcesports/echo-base/internal/interfaces/audit"
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+type mockAuditRecorder struct {
+	entries []*models.AuditEntry
+}
+
+func (m *mockAuditRecorder) Record(ctx context.Context, action, entityType, entityID string, before, after any) {
+	entry, err := newAuditEntry(ctx, action, entityType, entityID, before, after)
+	if err != nil {
+		panic(err)
+	}
+	m.entries = append(m.entries, entry)
+}
+
+type mockAuditRepository struct {
+	entries    []*models.AuditEntry
+	lastFilter models.AuditFilter
+}
+
+func (m *mockAuditRepository) Create(ctx context.Context, entry *models.AuditEntry) error {
+	m.entries = append(m.entries, entry)
+	return nil
+}
+
+func (m *mockAuditRepository) List(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	m.lastFilter = filter
+	return nil, nil
+}
+
+func (m *mockAuditRepository) ListForStudent(ctx context.Context, studentNumber string) ([]models.AuditEntry, error) {
+	var entries []models.AuditEntry
+	for _, entry := range m.entries {
+		if entry.EntityType == "profile" && entry.EntityID == studentNumber ||
+			mentionsStudent(entry.Before, studentNumber) || mentionsStudent(entry.After, studentNumber) {
+			entries = append(entries, *entry)
+		}
+	}
+	return entries, nil
+}
+
+func mentionsStudent(state json.RawMessage, studentNumber string) bool {
+	var fields struct {
+		StudentNumber string `json:"student_number"`
+	}
+	return json.Unmarshal(state, &fields) == nil && fields.StudentNumber == studentNumber
+}
+
+func TestAuditRecordAttributesCaller(t *testing.T) {
+	repo := &mockAuditRepository{}
+	service := NewAuditService(repo)
+
+	ctx := auth.WithApplication(context.Background(), &auth.Application{AppName: "frontend"})
+	ctx = audit.WithRoute(ctx, "POST", "POST /v1/api/gamer")
+
+	before := &models.GamerProfile{StudentNumber: "12345678", FirstName: "John", MembershipTier: 1}
+	after := &models.GamerProfile{StudentNumber: "12345678", FirstName: "John", MembershipTier: 2}
+	service.Record(ctx, AuditProfileUpsert, "profile", "12345678", before, after)
+
+	assert.Len(t, repo.entries, 1)
+	entry := repo.entries[0]
+	assert.Equal(t, "frontend", *entry.ActorApp)
+	assert.Equal(t, "POST", entry.Method)
+	assert.Equal(t, "POST /v1/api/gamer", entry.Route)
+	assert.Equal(t, AuditProfileUpsert, entry.Action)
+	assert.Equal(t, "profile", entry.EntityType)
+	assert.Equal(t, "12345678", entry.EntityID)
+
+	// Only changed fields are kept
+	assert.JSONEq(t, `{"membership_tier": 1}`, string(entry.Before))
+	assert.JSONEq(t, `{"membership_tier": 2}`, string(entry.After))
+}
+
+func TestAuditRecordCreateAndDelete(t *testing.T) {
+	repo := &mockAuditRepository{}
+	service := NewAuditService(repo)
+	profile := &models.GamerProfile{StudentNumber: "12345678", FirstName: "John"}
+
+	var noProfile *models.GamerProfile
+	service.Record(context.Background(), AuditProfileUpsert, "profile", "12345678", noProfile, profile)
+	service.Record(context.Background(), AuditProfileDelete, "profile", "12345678", profile, nil)
+
+	assert.Len(t, repo.entries, 2)
+	assert.Nil(t, repo.entries[0].ActorApp)
+	assert.Equal(t, json.RawMessage("null"), repo.entries[0].Before)
+	assert.Contains(t, string(repo.entries[0].After), `"first_name":"John"`)
+	assert.Contains(t, string(repo.entries[1].Before), `"first_name":"John"`)
+	assert.Equal(t, json.RawMessage("null"), repo.entries[1].After)
+}
+
+func TestListAuditEntriesValidation(t *testing.T) {
+	repo := &mockAuditRepository{}
+	service := NewAuditService(repo)
+	ctx := context.Background()
+
+	_, err := service.ListEntries(ctx, models.AuditFilter{Page: 0, Limit: 10})
+	assert.Error(t, err)
+
+	_, err = service.ListEntries(ctx, models.AuditFilter{Page: 1, Limit: MaxAuditEntriesPerPage + 1})
+	assert.Error(t, err)
+
+	from := time.Date(2026, time.October, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	_, err = service.ListEntries(ctx, models.AuditFilter{Page: 1, Limit: 10, From: &from, To: &to})
+	assert.Error(t, err)
+
+	filter := models.AuditFilter{EntityType: "profile", ActorApp: "frontend", Page: 2, Limit: 10, From: &to, To: &from}
+	_, err = service.ListEntries(ctx, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, filter, repo.lastFilter)
+}
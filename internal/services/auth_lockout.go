@@ -0,0 +1,124 @@
+package services
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// maxLockoutEntries is the entry count above which stale entries are dropped
+const maxLockoutEntries = 10000
+
+// authLockout tracks failed authentication attempts per subject (such as a
+// client IP) and locks a subject out once it fails too often. Each lockout
+// in a row doubles the previous one, up to maxDuration.
+type authLockout struct {
+	mu          sync.Mutex
+	entries     map[string]*lockoutEntry
+	maxFailures int
+	window      time.Duration
+	baseLockout time.Duration
+	maxLockout  time.Duration
+}
+
+type lockoutEntry struct {
+	failures     int
+	firstFailure time.Time
+	lastFailure  time.Time
+	lockouts     int
+	lockedUntil  time.Time
+}
+
+func newAuthLockout(config AuthServiceConfig) *authLockout {
+	return &authLockout{
+		entries:     make(map[string]*lockoutEntry),
+		maxFailures: config.MaxFailedAttempts,
+		window:      config.FailureWindow,
+		baseLockout: config.LockoutDuration,
+		maxLockout:  config.MaxLockoutDuration,
+	}
+}
+
+// Check returns how much longer subject is locked out for, or zero
+func (l *authLockout) Check(subject string, now time.Time) time.Duration {
+	if l.maxFailures <= 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, exists := l.entries[subject]
+	if !exists || !now.Before(entry.lockedUntil) {
+		return 0
+	}
+	return entry.lockedUntil.Sub(now)
+}
+
+// Fail records a failed attempt and returns the new lockout duration if this
+// failure triggered one
+func (l *authLockout) Fail(subject string, now time.Time) time.Duration {
+	if l.maxFailures <= 0 {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, exists := l.entries[subject]
+	if !exists {
+		if len(l.entries) >= maxLockoutEntries {
+			l.prune(now)
+		}
+		entry = &lockoutEntry{}
+		l.entries[subject] = entry
+	}
+
+	// Forget old failures, and the backoff once a subject has behaved for a while
+	if now.Sub(entry.lastFailure) > l.maxLockout+l.window {
+		entry.lockouts = 0
+	}
+	if now.Sub(entry.firstFailure) > l.window {
+		entry.failures = 0
+		entry.firstFailure = now
+	}
+
+	entry.failures++
+	entry.lastFailure = now
+	if entry.failures < l.maxFailures {
+		return 0
+	}
+
+	duration := l.baseLockout << entry.lockouts
+	if duration <= 0 || duration > l.maxLockout {
+		duration = l.maxLockout
+	} else {
+		entry.lockouts++
+	}
+	entry.failures = 0
+	entry.lockedUntil = now.Add(duration)
+	return duration
+}
+
+// Succeed clears the failure count of subject. Any backoff already earned is
+// kept so alternating good and bad attempts cannot reset it.
+func (l *authLockout) Succeed(subject string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if entry, exists := l.entries[subject]; exists {
+		entry.failures = 0
+	}
+}
+
+func (l *authLockout) prune(now time.Time) {
+	for subject, entry := range l.entries {
+		if now.Sub(entry.lastFailure) > l.maxLockout+l.window {
+			delete(l.entries, subject)
+		}
+	}
+}
+
+func logLockout(subject string, duration time.Duration) {
+	log.Printf("auth lockout: %s locked out for %s after repeated failed attempts", subject, duration)
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthLockoutBackoff(t *testing.T) {
+	config := DefaultAuthServiceConfig()
+	config.MaxFailedAttempts = 3
+	lockout := newAuthLockout(config)
+	now := time.Now()
+
+	fail := func() time.Duration {
+		var duration time.Duration
+		for i := 0; i < config.MaxFailedAttempts; i++ {
+			if d := lockout.Fail("ip:10.0.0.1", now); d > 0 {
+				duration = d
+			}
+		}
+		return duration
+	}
+
+	assert.Equal(t, time.Minute, fail())
+	assert.Equal(t, time.Minute, lockout.Check("ip:10.0.0.1", now))
+	assert.Zero(t, lockout.Check("ip:10.0.0.2", now))
+
+	// Each lockout in a row doubles
+	now = now.Add(time.Minute)
+	assert.Zero(t, lockout.Check("ip:10.0.0.1", now))
+	assert.Equal(t, 2*time.Minute, fail())
+
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, 4*time.Minute, fail())
+
+	// A success clears the failure count but not the backoff
+	now = now.Add(4 * time.Minute)
+	lockout.Fail("ip:10.0.0.1", now)
+	lockout.Succeed("ip:10.0.0.1")
+	assert.Zero(t, lockout.Fail("ip:10.0.0.1", now))
+	assert.Equal(t, 8*time.Minute, fail())
+
+	// Backoff is capped
+	for i := 0; i < 10; i++ {
+		now = now.Add(time.Hour)
+		fail()
+	}
+	assert.Equal(t, time.Hour, lockout.Check("ip:10.0.0.1", now))
+
+	// and forgotten once the subject behaves
+	now = now.Add(3 * time.Hour)
+	assert.Equal(t, time.Minute, fail())
+}
+
+func TestAuthLockoutFailuresExpire(t *testing.T) {
+	config := DefaultAuthServiceConfig()
+	lockout := newAuthLockout(config)
+	now := time.Now()
+
+	for i := 1; i < config.MaxFailedAttempts; i++ {
+		assert.Zero(t, lockout.Fail("key:abc", now))
+	}
+
+	now = now.Add(config.FailureWindow + time.Second)
+	assert.Zero(t, lockout.Fail("key:abc", now))
+	assert.Zero(t, lockout.Check("key:abc", now))
+}
+
+func TestAuthLockoutDisabled(t *testing.T) {
+	config := DefaultAuthServiceConfig()
+	config.MaxFailedAttempts = 0
+	lockout := newAuthLockout(config)
+
+	for i := 0; i < 100; i++ {
+		assert.Zero(t, lockout.Fail("ip:10.0.0.1", time.Now()))
+	}
+}
@@ -2,15 +2,20 @@ package services
 
 import (
 	"context"
+	"crypto/hmac"
 	"crypto/rand"
 	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/base32"
 	"encoding/base64"
+	goerrors "errors"
 	"fmt"
+	"net/netip"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/ubcesports/echo-base/internal/errors"
 	"github.com/ubcesports/echo-base/internal/interfaces/auth"
 )
 
@@ -19,6 +24,14 @@ const (
 	SecretLength     = 32
 	APIKeyPrefix     = "api_"
 	MaxAppNameLength = 100
+
+	// Nonces of signed requests must be long enough that clients picking
+	// them at random won't collide
+	MinNonceLength = 16
+	MaxNonceLength = 128
+
+	// DefaultRotationOverlap is how long the old secret keeps working after a rotation
+	DefaultRotationOverlap = 24 * time.Hour
 )
 
 var (
@@ -26,23 +39,122 @@ var (
 )
 
 type AuthService interface {
-	GenerateAPIKey(ctx context.Context, appName string) (*auth.APIKey, error)
-	ValidateAPIKey(ctx context.Context, apiKey string) (string, error)
+	GenerateAPIKey(ctx context.Context, appName string, opts auth.KeyOptions) (*auth.APIKey, error)
+	ValidateAPIKey(ctx context.Context, apiKey string, clientIP string) (*auth.Application, error)
+	ValidateSignedRequest(ctx context.Context, req *auth.SignedRequest, clientIP string, hashBody func() (string, error)) (*auth.Application, error)
+	ListAPIKeys(ctx context.Context) ([]auth.KeyInfo, error)
+	RevokeAPIKey(ctx context.Context, keyId string) error
+	RotateAPIKey(ctx context.Context, keyId string, overlap time.Duration) (*auth.APIKey, error)
+	UpdateRateLimit(ctx context.Context, keyId string, limit *auth.RateLimit) error
+	UpdateAuthMode(ctx context.Context, keyId string, mode auth.AuthMode) (string, error)
+	UpdateAllowedCIDRs(ctx context.Context, keyId string, cidrs []string) error
+}
+
+type AuthServiceConfig struct {
+	// CacheTTL is how long a validated key is trusted without a database lookup
+	CacheTTL time.Duration
+	// CacheSize bounds the number of validated keys held in memory
+	CacheSize int
+	// FlushInterval is how often batched last_used_at updates are written
+	FlushInterval time.Duration
+	// MaxFailedAttempts is how many failures within FailureWindow lock out a
+	// client IP. Zero disables lockouts.
+	MaxFailedAttempts int
+	FailureWindow     time.Duration
+	// LockoutDuration is the first lockout, each further lockout doubles it up
+	// to MaxLockoutDuration
+	LockoutDuration    time.Duration
+	MaxLockoutDuration time.Duration
+	// MaxClockSkew is how far a signed request's timestamp may be from the
+	// server clock. Nonces are remembered for this long.
+	MaxClockSkew time.Duration
+	// SigningPepper derives the keys HMAC mode clients sign with from the
+	// stored key hashes. It must never be stored in the database, so reading
+	// the api_key table is not enough to forge signed requests. HMAC mode is
+	// unavailable without it.
+	SigningPepper []byte
+}
+
+func DefaultAuthServiceConfig() AuthServiceConfig {
+	return AuthServiceConfig{
+		CacheTTL:      time.Minute,
+		CacheSize:     1024,
+		FlushInterval: 30 * time.Second,
+
+		MaxFailedAttempts:  5,
+		FailureWindow:      15 * time.Minute,
+		LockoutDuration:    time.Minute,
+		MaxLockoutDuration: time.Hour,
+
+		MaxClockSkew: 5 * time.Minute,
+	}
 }
 
 type authService struct {
-	repo auth.AuthRepository
+	repo     auth.AuthRepository
+	config   AuthServiceConfig
+	cache    *apiKeyCache
+	lastUsed *lastUsedFlusher
+	lockout  *authLockout
+	nonces   *nonceCache
+	audit    AuditRecorder
+}
+
+func NewAuthService(repo auth.AuthRepository, audit AuditRecorder) *authService {
+	return NewAuthServiceWithConfig(repo, audit, DefaultAuthServiceConfig())
+}
+
+func NewAuthServiceWithConfig(repo auth.AuthRepository, audit AuditRecorder, config AuthServiceConfig) *authService {
+	return &authService{
+		repo:     repo,
+		audit:    audit,
+		config:   config,
+		cache:    newAPIKeyCache(config.CacheTTL, config.CacheSize),
+		lastUsed: newLastUsedFlusher(repo),
+		lockout:  newAuthLockout(config),
+		nonces:   newNonceCache(),
+	}
 }
 
-func NewAuthService(repo auth.AuthRepository) *authService {
-	return &authService{repo: repo}
+// RunLastUsedFlusher periodically persists key usage until ctx is cancelled.
+// Call FlushLastUsed during shutdown to write whatever is still pending.
+func (s *authService) RunLastUsedFlusher(ctx context.Context) {
+	s.lastUsed.Run(ctx, s.config.FlushInterval)
 }
 
-func (s *authService) GenerateAPIKey(ctx context.Context, appName string) (*auth.APIKey, error) {
+func (s *authService) FlushLastUsed(ctx context.Context) error {
+	return s.lastUsed.Flush(ctx)
+}
+
+func (s *authService) GenerateAPIKey(ctx context.Context, appName string, opts auth.KeyOptions) (*auth.APIKey, error) {
 	if err := s.validateAppName(appName); err != nil {
 		return nil, err
 	}
 
+	if err := s.validateScopes(opts.Scopes); err != nil {
+		return nil, err
+	}
+
+	if opts.ExpiresAt != nil && !opts.ExpiresAt.After(time.Now()) {
+		return nil, errors.NewValidationError("expires_at", "must be in the future")
+	}
+
+	if err := s.validateRateLimit(opts.RateLimit); err != nil {
+		return nil, err
+	}
+
+	if opts.AuthMode == "" {
+		opts.AuthMode = auth.AuthModeBearer
+	}
+	if err := s.validateAuthMode(opts.AuthMode); err != nil {
+		return nil, err
+	}
+
+	allowedCIDRs, err := s.parseCIDRs(opts.AllowedCIDRs)
+	if err != nil {
+		return nil, err
+	}
+
 	keyId, secret, err := s.genereateCredentials()
 	if err != nil {
 		return nil, err
@@ -50,38 +162,372 @@ func (s *authService) GenerateAPIKey(ctx context.Context, appName string) (*auth
 
 	hashedSecret := s.hashSecret(secret)
 	app := &auth.Application{
-		AppName:   appName,
-		KeyId:     keyId,
-		HashedKey: hashedSecret,
+		AppName:      appName,
+		KeyId:        keyId,
+		HashedKey:    hashedSecret,
+		Scopes:       opts.Scopes,
+		ExpiresAt:    opts.ExpiresAt,
+		RateLimit:    opts.RateLimit,
+		AuthMode:     opts.AuthMode,
+		AllowedCIDRs: allowedCIDRs,
 	}
 
 	if err := s.repo.Store(ctx, app); err != nil {
 		return nil, fmt.Errorf("failed to store API key: %w", err)
 	}
-	return &auth.APIKey{
+	s.audit.Record(ctx, AuditKeyGenerate, "api_key", keyId, nil, app.Info())
+
+	apiKey := &auth.APIKey{
 		KeyId:   keyId,
 		APIKey:  fmt.Sprintf("api_%s.%s", keyId, secret),
 		AppName: appName,
-	}, nil
+		Scopes:  opts.Scopes,
+	}
+	if opts.AuthMode == auth.AuthModeHMAC {
+		apiKey.SigningKey = s.signingKey(keyId, hashedSecret)
+	}
+	return apiKey, nil
 }
 
-func (s *authService) ValidateAPIKey(ctx context.Context, apiKey string) (string, error) {
+// ValidateAPIKey authenticates apiKey on behalf of clientIP. Repeated failures
+// from the same IP lock that IP out, in which case a LockedOutError is
+// returned without looking at the key. Lockouts are never keyed on the key id
+// alone, since anyone who has seen a key id could then lock its real client
+// out by sending bad secrets.
+func (s *authService) ValidateAPIKey(ctx context.Context, apiKey string, clientIP string) (*auth.Application, error) {
+	now := time.Now()
+
+	ipSubject := ""
+	if clientIP != "" {
+		ipSubject = "ip:" + clientIP
+	}
+	if err := s.checkLockout(ipSubject, now); err != nil {
+		return nil, err
+	}
 
 	keyId, secret, err := s.parseAPIKey(apiKey)
+	if err != nil {
+		s.recordFailure(ctx, now, ipSubject)
+		return nil, err
+	}
+
+	cacheKey := string(s.hashSecret(apiKey))
+	if app, ok := s.cache.Get(cacheKey, now); ok {
+		s.lastUsed.Record(keyId, now)
+		return app, nil
+	}
 
+	generation := s.cache.Generation(keyId)
 	app, err := s.repo.FindKeyById(ctx, keyId)
 	if err != nil {
-		return "", err
+		var notFound *errors.NotFoundError
+		if goerrors.As(err, &notFound) {
+			s.recordFailure(ctx, now, ipSubject)
+		}
+		return nil, err
 	}
+	if err := s.checkUsable(app, auth.AuthModeBearer, now); err != nil {
+		s.recordFailure(ctx, now, ipSubject)
+		return nil, err
+	}
+
+	validUntil := app.ExpiresAt
 	if !s.verifySecret(secret, app.HashedKey) {
-		return "", fmt.Errorf("invalid api key")
+		if !s.verifyPreviousSecret(secret, app, now) {
+			s.recordFailure(ctx, now, ipSubject)
+			return nil, fmt.Errorf("invalid api key")
+		}
+		if validUntil == nil || app.PreviousKeyExpiresAt.Before(*validUntil) {
+			validUntil = app.PreviousKeyExpiresAt
+		}
+	}
+
+	s.lockout.Succeed(ipSubject)
+	s.cache.Put(cacheKey, app, generation, now, validUntil)
+	s.lastUsed.Record(keyId, now)
+
+	return app, nil
+}
+
+// ValidateSignedRequest authenticates a request signed by an application in
+// HMAC mode. The timestamp must be within MaxClockSkew of the server clock
+// and each nonce is only accepted once, so a captured request can't be
+// replayed. Bad signatures count towards lockouts like bad API keys do.
+//
+// hashBody, when given, fills in req.BodyHash. It is only called once the key
+// has been found and is usable, so unauthenticated clients can't make the
+// server read large bodies.
+func (s *authService) ValidateSignedRequest(ctx context.Context, req *auth.SignedRequest, clientIP string, hashBody func() (string, error)) (*auth.Application, error) {
+	now := time.Now()
+
+	ipSubject := ""
+	if clientIP != "" {
+		ipSubject = "ip:" + clientIP
+	}
+	if err := s.checkLockout(ipSubject, now); err != nil {
+		return nil, err
+	}
+
+	if len(s.config.SigningPepper) == 0 {
+		return nil, fmt.Errorf("signed requests are not enabled on this server")
+	}
+
+	if err := s.validateSignedRequest(req); err != nil {
+		s.recordFailure(ctx, now, ipSubject)
+		return nil, err
+	}
+
+	signedAt := time.Unix(req.Timestamp, 0)
+	if now.Sub(signedAt).Abs() > s.config.MaxClockSkew {
+		return nil, fmt.Errorf("request timestamp is too far from the server clock")
+	}
+
+	// Signed requests never repeat, so the application itself is cached by
+	// key id and the signature is checked against it every time
+	cacheKey := "hmac:" + req.KeyId
+	app, cached := s.cache.Get(cacheKey, now)
+	generation := s.cache.Generation(req.KeyId)
+	if !cached {
+		var err error
+		app, err = s.repo.FindKeyById(ctx, req.KeyId)
+		if err != nil {
+			var notFound *errors.NotFoundError
+			if goerrors.As(err, &notFound) {
+				s.recordFailure(ctx, now, ipSubject)
+			}
+			return nil, err
+		}
+		if err := s.checkUsable(app, auth.AuthModeHMAC, now); err != nil {
+			s.recordFailure(ctx, now, ipSubject)
+			return nil, err
+		}
+	}
+
+	if hashBody != nil {
+		bodyHash, err := hashBody()
+		if err != nil {
+			return nil, err
+		}
+		req.BodyHash = bodyHash
+	}
+
+	if !s.verifySignature(req, app.HashedKey) &&
+		!(s.previousKeyValid(app, now) && s.verifySignature(req, app.PreviousHashedKey)) {
+		s.recordFailure(ctx, now, ipSubject)
+		return nil, fmt.Errorf("invalid request signature")
+	}
+
+	if !s.nonces.Use(req.KeyId+":"+req.Nonce, signedAt.Add(s.config.MaxClockSkew), now) {
+		return nil, fmt.Errorf("request nonce has already been used")
+	}
+
+	s.lockout.Succeed(ipSubject)
+	if !cached {
+		s.cache.Put(cacheKey, app, generation, now, app.ExpiresAt)
+	}
+	s.lastUsed.Record(req.KeyId, now)
+
+	return app, nil
+}
+
+func (s *authService) ListAPIKeys(ctx context.Context) ([]auth.KeyInfo, error) {
+	apps, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+
+	keys := make([]auth.KeyInfo, len(apps))
+	for i := range apps {
+		keys[i] = apps[i].Info()
+	}
+	return keys, nil
+}
+
+func (s *authService) RevokeAPIKey(ctx context.Context, keyId string) error {
+	if keyId == "" {
+		return errors.NewValidationError("key_id", "is required")
+	}
+
+	if err := s.repo.Revoke(ctx, keyId); err != nil {
+		return err
+	}
+
+	s.cache.Invalidate(keyId)
+	s.audit.Record(ctx, AuditKeyRevoke, "api_key", keyId, nil, nil)
+	return nil
+}
+
+// RotateAPIKey issues a new secret for an existing key id. The old secret
+// keeps validating for overlap so clients can be updated without downtime.
+func (s *authService) RotateAPIKey(ctx context.Context, keyId string, overlap time.Duration) (*auth.APIKey, error) {
+	if keyId == "" {
+		return nil, errors.NewValidationError("key_id", "is required")
+	}
+
+	if overlap < 0 {
+		return nil, errors.NewValidationError("overlap", "must not be negative")
+	}
+
+	app, err := s.repo.FindKeyById(ctx, keyId)
+	if err != nil {
+		return nil, err
+	}
+
+	_, secret, err := s.genereateCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	hashedSecret := s.hashSecret(secret)
+	if err := s.repo.Rotate(ctx, keyId, hashedSecret, time.Now().Add(overlap)); err != nil {
+		return nil, err
+	}
+	s.cache.Invalidate(keyId)
+	s.audit.Record(ctx, AuditKeyRotate, "api_key", keyId, nil, nil)
+
+	apiKey := &auth.APIKey{
+		KeyId:   keyId,
+		APIKey:  fmt.Sprintf("api_%s.%s", keyId, secret),
+		AppName: app.AppName,
+		Scopes:  app.Scopes,
+	}
+	if app.AuthMode == auth.AuthModeHMAC {
+		apiKey.SigningKey = s.signingKey(keyId, hashedSecret)
+	}
+	return apiKey, nil
+}
+
+// UpdateRateLimit changes the request rate allowed for a key. A nil limit
+// returns the key to the server default.
+func (s *authService) UpdateRateLimit(ctx context.Context, keyId string, limit *auth.RateLimit) error {
+	if keyId == "" {
+		return errors.NewValidationError("key_id", "is required")
+	}
+
+	if err := s.validateRateLimit(limit); err != nil {
+		return err
 	}
 
-	go func() {
-		s.repo.UpdateLastUsed(context.Background(), keyId)
-	}()
+	app, err := s.repo.FindKeyById(ctx, keyId)
+	if err != nil {
+		return err
+	}
 
-	return app.AppName, nil
+	if err := s.repo.UpdateRateLimit(ctx, keyId, limit); err != nil {
+		return err
+	}
+
+	s.cache.Invalidate(keyId)
+	s.audit.Record(ctx, AuditKeyRateLimit, "api_key", keyId,
+		map[string]any{"rate_limit": app.RateLimit}, map[string]any{"rate_limit": limit})
+	return nil
+}
+
+// UpdateAuthMode switches how a key authenticates. Switching to HMAC stops
+// the key from being accepted as a bearer token immediately, and returns the
+// signing key the client must now sign requests with.
+func (s *authService) UpdateAuthMode(ctx context.Context, keyId string, mode auth.AuthMode) (string, error) {
+	if keyId == "" {
+		return "", errors.NewValidationError("key_id", "is required")
+	}
+
+	if err := s.validateAuthMode(mode); err != nil {
+		return "", err
+	}
+
+	app, err := s.repo.FindKeyById(ctx, keyId)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.repo.UpdateAuthMode(ctx, keyId, mode); err != nil {
+		return "", err
+	}
+
+	s.cache.Invalidate(keyId)
+	s.audit.Record(ctx, AuditKeyAuthMode, "api_key", keyId,
+		map[string]any{"auth_mode": app.AuthMode}, map[string]any{"auth_mode": mode})
+
+	if mode != auth.AuthModeHMAC {
+		return "", nil
+	}
+	return s.signingKey(keyId, app.HashedKey), nil
+}
+
+// UpdateAllowedCIDRs replaces the address ranges a key may be used from. An
+// empty list lifts the restriction.
+func (s *authService) UpdateAllowedCIDRs(ctx context.Context, keyId string, cidrs []string) error {
+	if keyId == "" {
+		return errors.NewValidationError("key_id", "is required")
+	}
+
+	allowedCIDRs, err := s.parseCIDRs(cidrs)
+	if err != nil {
+		return err
+	}
+
+	app, err := s.repo.FindKeyById(ctx, keyId)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateAllowedCIDRs(ctx, keyId, allowedCIDRs); err != nil {
+		return err
+	}
+
+	s.cache.Invalidate(keyId)
+	s.audit.Record(ctx, AuditKeyAllowedCIDRs, "api_key", keyId,
+		map[string]any{"allowed_cidrs": app.AllowedCIDRs}, map[string]any{"allowed_cidrs": allowedCIDRs})
+	return nil
+}
+
+// checkUsable rejects keys that are revoked, expired or authenticating with
+// the wrong mode
+func (s *authService) checkUsable(app *auth.Application, mode auth.AuthMode, now time.Time) error {
+	if app.RevokedAt != nil {
+		return fmt.Errorf("api key revoked")
+	}
+	if app.ExpiresAt != nil && !now.Before(*app.ExpiresAt) {
+		return fmt.Errorf("api key expired")
+	}
+
+	appMode := app.AuthMode
+	if appMode == "" {
+		appMode = auth.AuthModeBearer
+	}
+	if appMode != mode {
+		if appMode == auth.AuthModeHMAC {
+			return fmt.Errorf("api key requires signed requests")
+		}
+		return fmt.Errorf("api key does not accept signed requests")
+	}
+	return nil
+}
+
+func (s *authService) checkLockout(subject string, now time.Time) error {
+	if subject == "" {
+		return nil
+	}
+	if retryAfter := s.lockout.Check(subject, now); retryAfter > 0 {
+		return errors.NewLockedOutError(retryAfter)
+	}
+	return nil
+}
+
+func (s *authService) recordFailure(ctx context.Context, now time.Time, subjects ...string) {
+	for _, subject := range subjects {
+		if subject == "" {
+			continue
+		}
+		if duration := s.lockout.Fail(subject, now); duration > 0 {
+			logLockout(subject, duration)
+
+			entityType, entityID, _ := strings.Cut(subject, ":")
+			s.audit.Record(ctx, AuditAuthLockout, entityType, entityID, nil, map[string]any{
+				"locked_until": now.Add(duration),
+			})
+		}
+	}
 }
 
 func (s *authService) genereateCredentials() (string, string, error) {
@@ -110,17 +556,82 @@ func (s *authService) hashSecret(secret string) []byte {
 
 func (s *authService) validateAppName(appName string) error {
 	if appName == "" {
-		return fmt.Errorf("app_name is required")
+		return errors.NewValidationError("app_name", "is required")
 	}
 
 	if len(appName) > MaxAppNameLength {
-		return fmt.Errorf("app_name must be %d characters or less", MaxAppNameLength)
+		return errors.NewValidationError("app_name", fmt.Sprintf("must be %d characters or less", MaxAppNameLength))
 	}
 
 	if !validAppNameRegex.MatchString(appName) {
-		return fmt.Errorf("app_name can only contain letters, numbers, hyphens, and underscores")
+		return errors.NewValidationError("app_name", "can only contain letters, numbers, hyphens, and underscores")
+	}
+
+	return nil
+}
+
+func (s *authService) validateScopes(scopes []auth.Scope) error {
+	if len(scopes) == 0 {
+		return errors.NewValidationError("scopes", "at least one scope is required")
+	}
+
+	for _, scope := range scopes {
+		if !scope.IsValid() {
+			return errors.NewValidationError("scopes", fmt.Sprintf("unknown scope %q", scope))
+		}
+	}
+
+	return nil
+}
+
+func (s *authService) validateRateLimit(limit *auth.RateLimit) error {
+	if limit == nil {
+		return nil
+	}
+
+	if limit.PerMinute < 1 {
+		return errors.NewValidationError("rate_limit.per_minute", "must be at least 1")
 	}
 
+	if limit.Burst < 1 {
+		return errors.NewValidationError("rate_limit.burst", "must be at least 1")
+	}
+
+	return nil
+}
+
+func (s *authService) validateAuthMode(mode auth.AuthMode) error {
+	if !mode.IsValid() {
+		return errors.NewValidationError("auth_mode", fmt.Sprintf("must be %q or %q", auth.AuthModeBearer, auth.AuthModeHMAC))
+	}
+	if mode == auth.AuthModeHMAC && len(s.config.SigningPepper) == 0 {
+		return errors.NewValidationError("auth_mode", "hmac is not enabled on this server")
+	}
+	return nil
+}
+
+func (s *authService) parseCIDRs(values []string) ([]netip.Prefix, error) {
+	var cidrs []netip.Prefix
+	for _, value := range values {
+		cidr, err := auth.ParseCIDR(strings.TrimSpace(value))
+		if err != nil {
+			return nil, errors.NewValidationError("allowed_cidrs", fmt.Sprintf("%q is not a CIDR range or IP address", value))
+		}
+		cidrs = append(cidrs, cidr)
+	}
+	return cidrs, nil
+}
+
+func (s *authService) validateSignedRequest(req *auth.SignedRequest) error {
+	if req.KeyId == "" {
+		return fmt.Errorf("signed request is missing a key id")
+	}
+	if len(req.Nonce) < MinNonceLength || len(req.Nonce) > MaxNonceLength {
+		return fmt.Errorf("signed request nonce must be %d to %d characters", MinNonceLength, MaxNonceLength)
+	}
+	if req.Signature == "" {
+		return fmt.Errorf("signed request is missing a signature")
+	}
 	return nil
 }
 
@@ -146,3 +657,36 @@ func (s *authService) verifySecret(secret string, hashedSecret []byte) bool {
 
 	return subtle.ConstantTimeCompare(hashedSecret, actualHash) == 1
 }
+
+func (s *authService) verifyPreviousSecret(secret string, app *auth.Application, now time.Time) bool {
+	if !s.previousKeyValid(app, now) {
+		return false
+	}
+	return s.verifySecret(secret, app.PreviousHashedKey)
+}
+
+// previousKeyValid reports whether the secret replaced by the last rotation
+// is still accepted
+func (s *authService) previousKeyValid(app *auth.Application, now time.Time) bool {
+	if app.PreviousHashedKey == nil || app.PreviousKeyExpiresAt == nil {
+		return false
+	}
+	return now.Before(*app.PreviousKeyExpiresAt)
+}
+
+// verifySignature checks req against the signing key of a stored secret hash
+func (s *authService) verifySignature(req *auth.SignedRequest, hashedSecret []byte) bool {
+	signingKey := s.signingKey(req.KeyId, hashedSecret)
+	return hmac.Equal([]byte(req.Signature), []byte(req.Sign([]byte(signingKey))))
+}
+
+// signingKey derives the key an HMAC mode client signs with from the stored
+// hash of its secret. The hash alone isn't enough to sign requests, only the
+// server holding SigningPepper can derive the signing key from it.
+func (s *authService) signingKey(keyId string, hashedSecret []byte) string {
+	mac := hmac.New(sha256.New, s.config.SigningPepper)
+	mac.Write([]byte(keyId))
+	mac.Write([]byte{0})
+	mac.Write(hashedSecret)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
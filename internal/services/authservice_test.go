@@ -3,15 +3,23 @@ package services
 import (
 	"context"
 	"fmt"
+	"net/netip"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/ubcesports/echo-base/internal/errors"
 	"github.com/ubcesports/echo-base/internal/interfaces/auth"
 )
 
 type mockAuthRepository struct {
 	applications map[string]*auth.Application
+	findCalls    int
+	lastUsed     map[string]time.Time
+	lastUsedErr  error
+	// afterFind runs once a key has been loaded, before it is returned
+	afterFind func()
 }
 
 func (m *mockAuthRepository) Store(ctx context.Context, app *auth.Application) error {
@@ -19,13 +27,77 @@ func (m *mockAuthRepository) Store(ctx context.Context, app *auth.Application) e
 	return nil
 }
 func (m *mockAuthRepository) FindKeyById(ctx context.Context, keyId string) (*auth.Application, error) {
+	m.findCalls++
 	app, exists := m.applications[keyId]
 	if !exists {
-		return nil, fmt.Errorf("error")
+		return nil, errors.NewNotFoundError("api key", keyId)
 	}
-	return app, nil
+	loaded := *app
+	if m.afterFind != nil {
+		m.afterFind()
+	}
+	return &loaded, nil
+}
+func (m *mockAuthRepository) UpdateLastUsed(ctx context.Context, lastUsed map[string]time.Time) error {
+	if m.lastUsedErr != nil {
+		return m.lastUsedErr
+	}
+	if m.lastUsed == nil {
+		m.lastUsed = make(map[string]time.Time)
+	}
+	for keyId, usedAt := range lastUsed {
+		m.lastUsed[keyId] = usedAt
+	}
+	return nil
+}
+func (m *mockAuthRepository) List(ctx context.Context) ([]auth.Application, error) {
+	var apps []auth.Application
+	for _, app := range m.applications {
+		apps = append(apps, *app)
+	}
+	return apps, nil
+}
+func (m *mockAuthRepository) Revoke(ctx context.Context, keyId string) error {
+	app, exists := m.applications[keyId]
+	if !exists {
+		return fmt.Errorf("error")
+	}
+	now := time.Now()
+	app.RevokedAt = &now
+	return nil
+}
+func (m *mockAuthRepository) UpdateRateLimit(ctx context.Context, keyId string, limit *auth.RateLimit) error {
+	app, exists := m.applications[keyId]
+	if !exists {
+		return fmt.Errorf("error")
+	}
+	app.RateLimit = limit
+	return nil
+}
+func (m *mockAuthRepository) UpdateAuthMode(ctx context.Context, keyId string, mode auth.AuthMode) error {
+	app, exists := m.applications[keyId]
+	if !exists {
+		return fmt.Errorf("error")
+	}
+	app.AuthMode = mode
+	return nil
+}
+func (m *mockAuthRepository) UpdateAllowedCIDRs(ctx context.Context, keyId string, cidrs []netip.Prefix) error {
+	app, exists := m.applications[keyId]
+	if !exists {
+		return fmt.Errorf("error")
+	}
+	app.AllowedCIDRs = cidrs
+	return nil
 }
-func (m *mockAuthRepository) UpdateLastUsed(ctx context.Context, keyId string) error {
+func (m *mockAuthRepository) Rotate(ctx context.Context, keyId string, hashedKey []byte, previousValidUntil time.Time) error {
+	app, exists := m.applications[keyId]
+	if !exists {
+		return fmt.Errorf("error")
+	}
+	app.PreviousHashedKey = app.HashedKey
+	app.PreviousKeyExpiresAt = &previousValidUntil
+	app.HashedKey = hashedKey
 	return nil
 }
 
@@ -33,19 +105,41 @@ func TestGenerateAPIKey(t *testing.T) {
 	mockRepo := &mockAuthRepository{
 		applications: make(map[string]*auth.Application),
 	}
-	authService := NewAuthService(mockRepo)
+	authService := NewAuthService(mockRepo, &mockAuditRecorder{})
+
+	scopes := []auth.Scope{auth.ScopeProfilesRead}
+	opts := auth.KeyOptions{Scopes: scopes}
 
 	// Valid Key
-	apiKey, err := authService.GenerateAPIKey(context.Background(), "test-app")
+	apiKey, err := authService.GenerateAPIKey(context.Background(), "test-app", opts)
 	assert.NoError(t, err)
 	assert.Equal(t, "test-app", apiKey.AppName)
+	assert.Equal(t, scopes, apiKey.Scopes)
+	assert.Equal(t, scopes, mockRepo.applications[apiKey.KeyId].Scopes)
 
 	// Invalid key, no app name
-	apiKey, err = authService.GenerateAPIKey(context.Background(), "")
+	apiKey, err = authService.GenerateAPIKey(context.Background(), "", opts)
 	assert.Error(t, err)
 
 	// Invalid key, too long
-	apiKey, err = authService.GenerateAPIKey(context.Background(), strings.Repeat("a", 101))
+	apiKey, err = authService.GenerateAPIKey(context.Background(), strings.Repeat("a", 101), opts)
+	assert.Error(t, err)
+
+	// Invalid key, no scopes
+	apiKey, err = authService.GenerateAPIKey(context.Background(), "test-app", auth.KeyOptions{})
+	assert.Error(t, err)
+
+	// Invalid key, unknown scope
+	apiKey, err = authService.GenerateAPIKey(context.Background(), "test-app", auth.KeyOptions{Scopes: []auth.Scope{"profiles:delete"}})
+	assert.Error(t, err)
+
+	// Invalid key, bad rate limit
+	apiKey, err = authService.GenerateAPIKey(context.Background(), "test-app", auth.KeyOptions{Scopes: scopes, RateLimit: &auth.RateLimit{PerMinute: 0, Burst: 5}})
+	assert.Error(t, err)
+
+	// Invalid key, already expired
+	past := time.Now().Add(-time.Hour)
+	apiKey, err = authService.GenerateAPIKey(context.Background(), "test-app", auth.KeyOptions{Scopes: scopes, ExpiresAt: &past})
 	assert.Error(t, err)
 }
 
@@ -54,24 +148,402 @@ func TestValidateAPIKey(t *testing.T) {
 		applications: make(map[string]*auth.Application),
 	}
 
-	authService := NewAuthService(mockRepo)
+	authService := NewAuthService(mockRepo, &mockAuditRecorder{})
 	rawSecret := "supersecret"
 	hashedSecret := authService.hashSecret(rawSecret)
 	mockApp := auth.Application{
 		KeyId:     "testid",
 		HashedKey: hashedSecret,
 		AppName:   "test-app",
+		Scopes:    []auth.Scope{auth.ScopeActivityWrite},
 	}
 	mockRepo.Store(context.Background(), &mockApp)
 
 	// Valid API key
 	apiKey := fmt.Sprintf("api_%s.%s", mockApp.KeyId, rawSecret)
-	gotAppName, err := authService.ValidateAPIKey(context.Background(), apiKey)
+	gotApp, err := authService.ValidateAPIKey(context.Background(), apiKey, "10.0.0.1")
 	assert.NoError(t, err)
-	assert.Equal(t, mockApp.AppName, gotAppName)
+	assert.Equal(t, mockApp.AppName, gotApp.AppName)
+	assert.Equal(t, mockApp.Scopes, gotApp.Scopes)
 
 	// Invalid secret
 	badApiKey := fmt.Sprintf("api_%s.%s", mockApp.KeyId, "wrongsecret")
-	_, err = authService.ValidateAPIKey(context.Background(), badApiKey)
+	_, err = authService.ValidateAPIKey(context.Background(), badApiKey, "10.0.0.1")
+	assert.Error(t, err)
+}
+
+func TestValidateAPIKeyLifecycle(t *testing.T) {
+	mockRepo := &mockAuthRepository{
+		applications: make(map[string]*auth.Application),
+	}
+	authService := NewAuthService(mockRepo, &mockAuditRecorder{})
+	ctx := context.Background()
+
+	// Expired key
+	past := time.Now().Add(-time.Minute)
+	mockRepo.Store(ctx, &auth.Application{
+		KeyId:     "expired",
+		AppName:   "test-app",
+		HashedKey: authService.hashSecret("secret"),
+		ExpiresAt: &past,
+	})
+	_, err := authService.ValidateAPIKey(ctx, "api_expired.secret", "10.0.0.1")
+	assert.Error(t, err)
+
+	// Revoked key
+	apiKey, err := authService.GenerateAPIKey(ctx, "test-app", auth.KeyOptions{Scopes: []auth.Scope{auth.ScopeAdmin}})
+	assert.NoError(t, err)
+	_, err = authService.ValidateAPIKey(ctx, apiKey.APIKey, "10.0.0.1")
+	assert.NoError(t, err)
+
+	assert.NoError(t, authService.RevokeAPIKey(ctx, apiKey.KeyId))
+	_, err = authService.ValidateAPIKey(ctx, apiKey.APIKey, "10.0.0.1")
+	assert.Error(t, err)
+}
+
+func TestRotateAPIKey(t *testing.T) {
+	mockRepo := &mockAuthRepository{
+		applications: make(map[string]*auth.Application),
+	}
+	authService := NewAuthService(mockRepo, &mockAuditRecorder{})
+	ctx := context.Background()
+
+	original, err := authService.GenerateAPIKey(ctx, "kiosk", auth.KeyOptions{Scopes: []auth.Scope{auth.ScopeActivityWrite}})
+	assert.NoError(t, err)
+
+	// Both secrets validate during the overlap window
+	rotated, err := authService.RotateAPIKey(ctx, original.KeyId, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, original.KeyId, rotated.KeyId)
+	assert.NotEqual(t, original.APIKey, rotated.APIKey)
+
+	_, err = authService.ValidateAPIKey(ctx, original.APIKey, "10.0.0.1")
+	assert.NoError(t, err)
+	_, err = authService.ValidateAPIKey(ctx, rotated.APIKey, "10.0.0.1")
+	assert.NoError(t, err)
+
+	// Only the newest secret validates once the overlap is over
+	_, err = authService.RotateAPIKey(ctx, original.KeyId, 0)
+	assert.NoError(t, err)
+	_, err = authService.ValidateAPIKey(ctx, rotated.APIKey, "10.0.0.1")
+	assert.Error(t, err)
+
+	// Negative overlap
+	_, err = authService.RotateAPIKey(ctx, original.KeyId, -time.Hour)
+	assert.Error(t, err)
+}
+
+func TestValidateAPIKeyUsesCache(t *testing.T) {
+	mockRepo := &mockAuthRepository{
+		applications: make(map[string]*auth.Application),
+	}
+	authService := NewAuthService(mockRepo, &mockAuditRecorder{})
+	ctx := context.Background()
+
+	apiKey, err := authService.GenerateAPIKey(ctx, "kiosk", auth.KeyOptions{Scopes: []auth.Scope{auth.ScopeActivityRead}})
+	assert.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err = authService.ValidateAPIKey(ctx, apiKey.APIKey, "10.0.0.1")
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 1, mockRepo.findCalls)
+
+	// A wrong secret for a cached key id still goes to the database and fails
+	_, err = authService.ValidateAPIKey(ctx, fmt.Sprintf("api_%s.%s", apiKey.KeyId, "wrongsecret"), "10.0.0.1")
+	assert.Error(t, err)
+	assert.Equal(t, 2, mockRepo.findCalls)
+
+	// Revoking invalidates the cached entry immediately
+	assert.NoError(t, authService.RevokeAPIKey(ctx, apiKey.KeyId))
+	_, err = authService.ValidateAPIKey(ctx, apiKey.APIKey, "10.0.0.1")
+	assert.Error(t, err)
+}
+
+func TestValidateAPIKeyRevokedWhileLoading(t *testing.T) {
+	mockRepo := &mockAuthRepository{
+		applications: make(map[string]*auth.Application),
+	}
+	authService := NewAuthService(mockRepo, &mockAuditRecorder{})
+	ctx := context.Background()
+
+	apiKey, err := authService.GenerateAPIKey(ctx, "kiosk", auth.KeyOptions{Scopes: []auth.Scope{auth.ScopeActivityRead}})
+	assert.NoError(t, err)
+
+	// The key is revoked after it was loaded but before it is cached
+	mockRepo.afterFind = func() {
+		mockRepo.afterFind = nil
+		assert.NoError(t, authService.RevokeAPIKey(ctx, apiKey.KeyId))
+	}
+	_, err = authService.ValidateAPIKey(ctx, apiKey.APIKey, "10.0.0.1")
+	assert.NoError(t, err)
+
+	_, err = authService.ValidateAPIKey(ctx, apiKey.APIKey, "10.0.0.1")
+	assert.Error(t, err)
+	assert.Equal(t, 2, mockRepo.findCalls)
+}
+
+func TestValidateAPIKeyBatchesLastUsed(t *testing.T) {
+	mockRepo := &mockAuthRepository{
+		applications: make(map[string]*auth.Application),
+	}
+	authService := NewAuthService(mockRepo, &mockAuditRecorder{})
+	ctx := context.Background()
+
+	apiKey, err := authService.GenerateAPIKey(ctx, "kiosk", auth.KeyOptions{Scopes: []auth.Scope{auth.ScopeActivityRead}})
+	assert.NoError(t, err)
+
+	_, err = authService.ValidateAPIKey(ctx, apiKey.APIKey, "10.0.0.1")
+	assert.NoError(t, err)
+	assert.Empty(t, mockRepo.lastUsed)
+
+	// Failed writes are retried on the next flush
+	mockRepo.lastUsedErr = fmt.Errorf("database unavailable")
+	assert.Error(t, authService.FlushLastUsed(ctx))
+	assert.Empty(t, mockRepo.lastUsed)
+
+	mockRepo.lastUsedErr = nil
+	assert.NoError(t, authService.FlushLastUsed(ctx))
+	assert.Contains(t, mockRepo.lastUsed, apiKey.KeyId)
+}
+
+func TestUpdateRateLimit(t *testing.T) {
+	mockRepo := &mockAuthRepository{
+		applications: make(map[string]*auth.Application),
+	}
+	authService := NewAuthService(mockRepo, &mockAuditRecorder{})
+	ctx := context.Background()
+
+	apiKey, err := authService.GenerateAPIKey(ctx, "frontend", auth.KeyOptions{Scopes: []auth.Scope{auth.ScopeProfilesRead}})
+	assert.NoError(t, err)
+
+	// Cache the key so we can check the update invalidates it
+	_, err = authService.ValidateAPIKey(ctx, apiKey.APIKey, "10.0.0.1")
+	assert.NoError(t, err)
+
+	limit := &auth.RateLimit{PerMinute: 30, Burst: 5}
+	assert.NoError(t, authService.UpdateRateLimit(ctx, apiKey.KeyId, limit))
+
+	app, err := authService.ValidateAPIKey(ctx, apiKey.APIKey, "10.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, limit, app.RateLimit)
+
+	assert.Error(t, authService.UpdateRateLimit(ctx, apiKey.KeyId, &auth.RateLimit{PerMinute: 30, Burst: 0}))
+}
+
+func TestValidateAPIKeyLockout(t *testing.T) {
+	mockRepo := &mockAuthRepository{
+		applications: make(map[string]*auth.Application),
+	}
+	authService := NewAuthService(mockRepo, &mockAuditRecorder{})
+	ctx := context.Background()
+
+	apiKey, err := authService.GenerateAPIKey(ctx, "frontend", auth.KeyOptions{Scopes: []auth.Scope{auth.ScopeProfilesRead}})
+	assert.NoError(t, err)
+
+	// Malformed keys are rejected without a database lookup
+	_, err = authService.ValidateAPIKey(ctx, "not-an-api-key", "10.0.0.9")
+	assert.Error(t, err)
+	assert.Equal(t, 0, mockRepo.findCalls)
+
+	// Guessing secrets for a known key id locks out the IP
+	wrongKey := fmt.Sprintf("api_%s.%s", apiKey.KeyId, "wrongsecret")
+	for i := 0; i < DefaultAuthServiceConfig().MaxFailedAttempts; i++ {
+		_, err = authService.ValidateAPIKey(ctx, wrongKey, "10.0.0.2")
+		assert.Error(t, err)
+	}
+
+	var lockedOut *errors.LockedOutError
+	_, err = authService.ValidateAPIKey(ctx, apiKey.APIKey, "10.0.0.2")
+	assert.ErrorAs(t, err, &lockedOut)
+	assert.Equal(t, time.Minute, lockedOut.RetryAfter.Round(time.Second))
+
+	// but not the key itself, so the real client can't be locked out by
+	// someone who has seen its key id
+	_, err = authService.ValidateAPIKey(ctx, apiKey.APIKey, "10.0.0.3")
+	assert.NoError(t, err)
+
+	// Other keys from other IPs are unaffected
+	other, err := authService.GenerateAPIKey(ctx, "kiosk", auth.KeyOptions{Scopes: []auth.Scope{auth.ScopeProfilesRead}})
+	assert.NoError(t, err)
+	_, err = authService.ValidateAPIKey(ctx, other.APIKey, "10.0.0.3")
+	assert.NoError(t, err)
+}
+
+// newHMACAuthService has a signing pepper so keys can use HMAC mode
+func newHMACAuthService(repo auth.AuthRepository) *authService {
+	config := DefaultAuthServiceConfig()
+	config.SigningPepper = []byte("test-pepper")
+	return NewAuthServiceWithConfig(repo, &mockAuditRecorder{}, config)
+}
+
+func signRequest(apiKey *auth.APIKey, method, path string, body []byte, signedAt time.Time, nonce string) *auth.SignedRequest {
+	return signRequestWith([]byte(apiKey.SigningKey), apiKey.KeyId, method, path, body, signedAt, nonce)
+}
+
+func signRequestWith(signingKey []byte, keyId, method, path string, body []byte, signedAt time.Time, nonce string) *auth.SignedRequest {
+	req := &auth.SignedRequest{
+		KeyId:     keyId,
+		Timestamp: signedAt.Unix(),
+		Nonce:     nonce,
+		Method:    method,
+		Path:      path,
+		BodyHash:  auth.HashBody(body),
+	}
+	req.Signature = req.Sign(signingKey)
+	return req
+}
+
+func TestValidateSignedRequest(t *testing.T) {
+	mockRepo := &mockAuthRepository{
+		applications: make(map[string]*auth.Application),
+	}
+	authService := newHMACAuthService(mockRepo)
+	ctx := context.Background()
+
+	apiKey, err := authService.GenerateAPIKey(ctx, "kiosk", auth.KeyOptions{
+		Scopes:   []auth.Scope{auth.ScopeActivityWrite},
+		AuthMode: auth.AuthModeHMAC,
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, apiKey.SigningKey)
+
+	body := []byte(`{"pc_number":1}`)
+	now := time.Now()
+
+	req := signRequest(apiKey, "POST", "/v1/api/activity", body, now, "nonce-0000000001")
+	app, err := authService.ValidateSignedRequest(ctx, req, "10.0.0.1", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "kiosk", app.AppName)
+
+	// Replaying the exact request is rejected
+	_, err = authService.ValidateSignedRequest(ctx, req, "10.0.0.1", nil)
+	assert.ErrorContains(t, err, "nonce")
+
+	// Signed requests are the only way in for HMAC keys
+	_, err = authService.ValidateAPIKey(ctx, apiKey.APIKey, "10.0.0.1")
+	assert.ErrorContains(t, err, "requires signed requests")
+
+	tampered := signRequest(apiKey, "POST", "/v1/api/activity", body, now, "nonce-0000000002")
+	tampered.BodyHash = auth.HashBody([]byte(`{"pc_number":2}`))
+	_, err = authService.ValidateSignedRequest(ctx, tampered, "10.0.0.1", nil)
+	assert.ErrorContains(t, err, "invalid request signature")
+
+	stale := signRequest(apiKey, "POST", "/v1/api/activity", body, now.Add(-6*time.Minute), "nonce-0000000003")
+	_, err = authService.ValidateSignedRequest(ctx, stale, "10.0.0.1", nil)
+	assert.ErrorContains(t, err, "server clock")
+
+	// The stored hash of the secret can't be used to sign requests
+	stored := mockRepo.applications[apiKey.KeyId].HashedKey
+	forged := signRequestWith(stored, apiKey.KeyId, "POST", "/v1/api/activity", body, now, "nonce-0000000006")
+	_, err = authService.ValidateSignedRequest(ctx, forged, "10.0.0.2", nil)
+	assert.ErrorContains(t, err, "invalid request signature")
+
+	// The body is only hashed once the key has been found
+	unknown := signRequestWith([]byte("guess"), "nokey1", "POST", "/v1/api/activity", body, now, "nonce-0000000007")
+	_, err = authService.ValidateSignedRequest(ctx, unknown, "10.0.0.2", func() (string, error) {
+		t.Error("body hashed for an unknown key")
+		return "", nil
+	})
+	assert.Error(t, err)
+
+	shortNonce := signRequest(apiKey, "POST", "/v1/api/activity", body, now, "abc")
+	_, err = authService.ValidateSignedRequest(ctx, shortNonce, "10.0.0.1", nil)
+	assert.Error(t, err)
+
+	// Signatures made with the previous secret keep working during the overlap
+	rotated, err := authService.RotateAPIKey(ctx, apiKey.KeyId, time.Hour)
+	assert.NoError(t, err)
+	assert.NotEqual(t, apiKey.SigningKey, rotated.SigningKey)
+	_, err = authService.ValidateSignedRequest(ctx, signRequest(apiKey, "GET", "/v1/api/gamer/1", nil, now, "nonce-0000000004"), "10.0.0.1", nil)
+	assert.NoError(t, err)
+	_, err = authService.ValidateSignedRequest(ctx, signRequest(rotated, "GET", "/v1/api/gamer/1", nil, now, "nonce-0000000005"), "10.0.0.1", nil)
+	assert.NoError(t, err)
+}
+
+func TestUpdateAuthMode(t *testing.T) {
+	mockRepo := &mockAuthRepository{
+		applications: make(map[string]*auth.Application),
+	}
+	authService := newHMACAuthService(mockRepo)
+	ctx := context.Background()
+
+	apiKey, err := authService.GenerateAPIKey(ctx, "kiosk", auth.KeyOptions{Scopes: []auth.Scope{auth.ScopeActivityWrite}})
+	assert.NoError(t, err)
+
+	// Cache the bearer key so we can check the switch invalidates it
+	_, err = authService.ValidateAPIKey(ctx, apiKey.APIKey, "10.0.0.1")
+	assert.NoError(t, err)
+
+	assert.Empty(t, apiKey.SigningKey)
+	signed := signRequestWith([]byte("guess"), apiKey.KeyId, "GET", "/health", nil, time.Now(), "nonce-0000000001")
+	_, err = authService.ValidateSignedRequest(ctx, signed, "10.0.0.1", nil)
+	assert.ErrorContains(t, err, "does not accept signed requests")
+
+	signingKey, err := authService.UpdateAuthMode(ctx, apiKey.KeyId, auth.AuthModeHMAC)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, signingKey)
+
+	_, err = authService.ValidateAPIKey(ctx, apiKey.APIKey, "10.0.0.1")
 	assert.Error(t, err)
+
+	signed = signRequestWith([]byte(signingKey), apiKey.KeyId, "GET", "/health", nil, time.Now(), "nonce-0000000002")
+	_, err = authService.ValidateSignedRequest(ctx, signed, "10.0.0.1", nil)
+	assert.NoError(t, err)
+
+	var validationErr *errors.ValidationError
+	_, err = authService.UpdateAuthMode(ctx, apiKey.KeyId, "basic")
+	assert.ErrorAs(t, err, &validationErr)
+
+	// HMAC mode needs a signing pepper
+	withoutPepper := NewAuthService(mockRepo, &mockAuditRecorder{})
+	_, err = withoutPepper.UpdateAuthMode(ctx, apiKey.KeyId, auth.AuthModeHMAC)
+	assert.ErrorAs(t, err, &validationErr)
+	_, err = withoutPepper.GenerateAPIKey(ctx, "kiosk2", auth.KeyOptions{
+		Scopes:   []auth.Scope{auth.ScopeActivityWrite},
+		AuthMode: auth.AuthModeHMAC,
+	})
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestUpdateAllowedCIDRs(t *testing.T) {
+	mockRepo := &mockAuthRepository{
+		applications: make(map[string]*auth.Application),
+	}
+	authService := NewAuthService(mockRepo, &mockAuditRecorder{})
+	ctx := context.Background()
+
+	apiKey, err := authService.GenerateAPIKey(ctx, "kiosk", auth.KeyOptions{
+		Scopes:       []auth.Scope{auth.ScopeActivityWrite},
+		AllowedCIDRs: []string{"10.20.0.0/16"},
+	})
+	assert.NoError(t, err)
+
+	app, err := authService.ValidateAPIKey(ctx, apiKey.APIKey, "10.20.1.1")
+	assert.NoError(t, err)
+	assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("10.20.0.0/16")}, app.AllowedCIDRs)
+
+	// Host bits are cleared and single addresses become one address ranges
+	assert.NoError(t, authService.UpdateAllowedCIDRs(ctx, apiKey.KeyId, []string{"192.168.1.7/24", "2001:db8::1"}))
+
+	app, err = authService.ValidateAPIKey(ctx, apiKey.APIKey, "10.20.1.1")
+	assert.NoError(t, err)
+	assert.Equal(t, []netip.Prefix{
+		netip.MustParsePrefix("192.168.1.0/24"),
+		netip.MustParsePrefix("2001:db8::1/128"),
+	}, app.AllowedCIDRs)
+
+	var validationErr *errors.ValidationError
+	assert.ErrorAs(t, authService.UpdateAllowedCIDRs(ctx, apiKey.KeyId, []string{"lounge"}), &validationErr)
+
+	_, err = authService.GenerateAPIKey(ctx, "kiosk", auth.KeyOptions{
+		Scopes:       []auth.Scope{auth.ScopeActivityWrite},
+		AllowedCIDRs: []string{"10.0.0.0/33"},
+	})
+	assert.ErrorAs(t, err, &validationErr)
+
+	assert.NoError(t, authService.UpdateAllowedCIDRs(ctx, apiKey.KeyId, nil))
+	app, err = authService.ValidateAPIKey(ctx, apiKey.APIKey, "10.20.1.1")
+	assert.NoError(t, err)
+	assert.Empty(t, app.AllowedCIDRs)
 }
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	goerrors "errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
+	"github.com/ubcesports/echo-base/internal/interfaces/exec"
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+const (
+	AuditExecCreate = "exec.create"
+	AuditExecUpdate = "exec.update"
+)
+
+type ExecService interface {
+	CreateExec(ctx context.Context, req *models.CreateExecRequest) (*models.Exec, error)
+	ListExecs(ctx context.Context) ([]models.Exec, error)
+	UpdateExec(ctx context.Context, id string, req *models.UpdateExecRequest) (*models.Exec, error)
+	Login(ctx context.Context, req *models.ExecLoginRequest, clientIP string) (*models.ExecToken, error)
+	VerifyToken(ctx context.Context, token string) (*auth.ExecIdentity, error)
+}
+
+type ExecServiceConfig struct {
+	// TokenSecret signs exec tokens. If empty a random secret is generated,
+	// so tokens stop working when the server restarts.
+	TokenSecret []byte
+	// TokenTTL is how long an exec stays signed in
+	TokenTTL time.Duration
+	// MaxFailedLogins is how many failed logins within FailureWindow lock out
+	// a client IP, like failed API key attempts do. Zero disables lockouts.
+	MaxFailedLogins int
+	FailureWindow   time.Duration
+	// LockoutDuration is the first lockout, each further lockout doubles it up
+	// to MaxLockoutDuration
+	LockoutDuration    time.Duration
+	MaxLockoutDuration time.Duration
+}
+
+func DefaultExecServiceConfig() ExecServiceConfig {
+	return ExecServiceConfig{
+		TokenTTL: 2 * time.Hour,
+
+		MaxFailedLogins:    5,
+		FailureWindow:      15 * time.Minute,
+		LockoutDuration:    time.Minute,
+		MaxLockoutDuration: time.Hour,
+	}
+}
+
+type execService struct {
+	repo    exec.ExecRepository
+	audit   AuditRecorder
+	tokens  *execTokenSigner
+	lockout *authLockout
+
+	// dummyHash is checked when no exec matches a login so unknown emails
+	// take as long to reject as wrong passwords
+	dummyHashOnce sync.Once
+	dummyHash     string
+}
+
+func NewExecService(repo exec.ExecRepository, audit AuditRecorder, config ExecServiceConfig) ExecService {
+	secret := config.TokenSecret
+	if len(secret) == 0 {
+		log.Printf("no exec token secret configured, using a random one; exec tokens will not survive a restart")
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			panic(fmt.Sprintf("failed to generate exec token secret: %v", err))
+		}
+	}
+
+	return &execService{
+		repo:   repo,
+		audit:  audit,
+		tokens: newExecTokenSigner(secret, config.TokenTTL),
+		lockout: newAuthLockout(AuthServiceConfig{
+			MaxFailedAttempts:  config.MaxFailedLogins,
+			FailureWindow:      config.FailureWindow,
+			LockoutDuration:    config.LockoutDuration,
+			MaxLockoutDuration: config.MaxLockoutDuration,
+		}),
+	}
+}
+
+func (s *execService) CreateExec(ctx context.Context, req *models.CreateExecRequest) (*models.Exec, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.NewValidationError("name", "is required")
+	}
+
+	email, err := normalizeEmail(req.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	role := req.Role
+	if role == "" {
+		role = models.ExecRoleExec
+	}
+	if !role.IsValid() {
+		return nil, errors.NewValidationError("role", fmt.Sprintf("must be %q or %q", models.ExecRoleExec, models.ExecRoleDirector))
+	}
+
+	if len(req.Password) < MinPasswordLength {
+		return nil, errors.NewValidationError("password", fmt.Sprintf("must be at least %d characters", MinPasswordLength))
+	}
+
+	passwordHash, err := hashPassword(req.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	created, err := s.repo.Create(ctx, &models.Exec{
+		Name:         name,
+		Email:        email,
+		Role:         role,
+		PasswordHash: passwordHash,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditExecCreate, "exec", created.ID, nil, created)
+	return created, nil
+}
+
+func (s *execService) ListExecs(ctx context.Context) ([]models.Exec, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *execService) UpdateExec(ctx context.Context, id string, req *models.UpdateExecRequest) (*models.Exec, error) {
+	if req.Active == nil {
+		return nil, errors.NewValidationError("active", "is required")
+	}
+
+	before, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.SetActive(ctx, id, *req.Active)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditExecUpdate, "exec", id, before, updated)
+	return updated, nil
+}
+
+// Login checks an exec's credentials and issues a signed exec token. Unknown
+// emails, wrong passwords and deactivated execs are indistinguishable.
+// Repeated failures from clientIP lock it out so a kiosk key can't be used to
+// guess passwords.
+func (s *execService) Login(ctx context.Context, req *models.ExecLoginRequest, clientIP string) (*models.ExecToken, error) {
+	now := time.Now()
+	subject := "exec_login:" + clientIP
+	if retryAfter := s.lockout.Check(subject, now); retryAfter > 0 {
+		return nil, errors.NewLockedOutError(retryAfter)
+	}
+
+	e, err := s.checkCredentials(ctx, req)
+	var unauthorizedErr *errors.UnauthorizedError
+	if goerrors.As(err, &unauthorizedErr) {
+		s.recordFailure(ctx, subject, now)
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.lockout.Succeed(subject)
+
+	token, expiresAt, err := s.tokens.Sign(&auth.ExecIdentity{ID: e.ID, Name: e.Name, Role: string(e.Role)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign exec token: %w", err)
+	}
+
+	return &models.ExecToken{Token: token, ExpiresAt: expiresAt, Exec: *e}, nil
+}
+
+func (s *execService) checkCredentials(ctx context.Context, req *models.ExecLoginRequest) (*models.Exec, error) {
+	invalid := errors.NewUnauthorizedError("invalid email or password")
+
+	email, err := normalizeEmail(req.Email)
+	if err != nil {
+		return nil, invalid
+	}
+
+	e, err := s.repo.GetByEmail(ctx, email)
+	var notFoundErr *errors.NotFoundError
+	if goerrors.As(err, &notFoundErr) {
+		verifyPassword(req.Password, s.getDummyHash())
+		return nil, invalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !verifyPassword(req.Password, e.PasswordHash) || !e.Active {
+		return nil, invalid
+	}
+	return e, nil
+}
+
+func (s *execService) recordFailure(ctx context.Context, subject string, now time.Time) {
+	if duration := s.lockout.Fail(subject, now); duration > 0 {
+		logLockout(subject, duration)
+
+		entityType, entityID, _ := strings.Cut(subject, ":")
+		s.audit.Record(ctx, AuditAuthLockout, entityType, entityID, nil, map[string]any{
+			"locked_until": now.Add(duration),
+		})
+	}
+}
+
+// VerifyToken checks a token's signature and expiry, and that the exec it was
+// issued to is still active so deactivating an exec signs them out at once
+func (s *execService) VerifyToken(ctx context.Context, token string) (*auth.ExecIdentity, error) {
+	identity, err := s.tokens.Verify(token)
+	if err != nil {
+		return nil, errors.NewUnauthorizedError(err.Error())
+	}
+
+	e, err := s.repo.GetByID(ctx, identity.ID)
+	var notFoundErr *errors.NotFoundError
+	if goerrors.As(err, &notFoundErr) {
+		return nil, errors.NewUnauthorizedError("exec no longer exists")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !e.Active {
+		return nil, errors.NewUnauthorizedError("exec has been deactivated")
+	}
+
+	return identity, nil
+}
+
+func (s *execService) getDummyHash() string {
+	s.dummyHashOnce.Do(func() {
+		s.dummyHash, _ = hashPassword("not a real password")
+	})
+	return s.dummyHash
+}
+
+func normalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return "", errors.NewValidationError("email", "is required")
+	}
+
+	if _, err := mail.ParseAddress(email); err != nil || strings.ContainsAny(email, "<> ") {
+		return "", errors.NewValidationError("email", "is not a valid email address")
+	}
+
+	return email, nil
+}
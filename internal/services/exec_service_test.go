@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+func init() {
+	// Full strength hashing makes every login test take hundreds of milliseconds
+	passwordIterations = 1000
+}
+
+type mockExecRepository struct {
+	execs []*models.Exec
+}
+
+func (m *mockExecRepository) Create(ctx context.Context, exec *models.Exec) (*models.Exec, error) {
+	for _, e := range m.execs {
+		if e.Email == exec.Email {
+			return nil, errors.NewValidationError("email", "is already in use")
+		}
+	}
+	created := *exec
+	created.ID = fmt.Sprintf("exec-%d", len(m.execs)+1)
+	created.Active = true
+	m.execs = append(m.execs, &created)
+	return &created, nil
+}
+
+func (m *mockExecRepository) GetByID(ctx context.Context, id string) (*models.Exec, error) {
+	for _, e := range m.execs {
+		if e.ID == id {
+			found := *e
+			return &found, nil
+		}
+	}
+	return nil, errors.NewNotFoundError("exec", id)
+}
+
+func (m *mockExecRepository) GetByEmail(ctx context.Context, email string) (*models.Exec, error) {
+	for _, e := range m.execs {
+		if e.Email == email {
+			found := *e
+			return &found, nil
+		}
+	}
+	return nil, errors.NewNotFoundError("exec", email)
+}
+
+func (m *mockExecRepository) List(ctx context.Context) ([]models.Exec, error) {
+	execs := make([]models.Exec, 0, len(m.execs))
+	for _, e := range m.execs {
+		execs = append(execs, *e)
+	}
+	return execs, nil
+}
+
+func (m *mockExecRepository) SetActive(ctx context.Context, id string, active bool) (*models.Exec, error) {
+	for _, e := range m.execs {
+		if e.ID == id {
+			e.Active = active
+			updated := *e
+			return &updated, nil
+		}
+	}
+	return nil, errors.NewNotFoundError("exec", id)
+}
+
+func newTestExecService() (ExecService, *mockAuditRecorder) {
+	audit := &mockAuditRecorder{}
+	config := DefaultExecServiceConfig()
+	config.TokenSecret = []byte("test-secret")
+	return NewExecService(&mockExecRepository{}, audit, config), audit
+}
+
+func TestCreateExec(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.CreateExecRequest
+		wantErr bool
+	}{
+		{
+			name: "valid exec",
+			req:  models.CreateExecRequest{Name: "Alex", Email: "alex@example.com", Password: "long enough password"},
+		},
+		{
+			name:    "missing name",
+			req:     models.CreateExecRequest{Name: " ", Email: "alex@example.com", Password: "long enough password"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid email",
+			req:     models.CreateExecRequest{Name: "Alex", Email: "alex", Password: "long enough password"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid role",
+			req:     models.CreateExecRequest{Name: "Alex", Email: "alex@example.com", Role: "president", Password: "long enough password"},
+			wantErr: true,
+		},
+		{
+			name:    "short password",
+			req:     models.CreateExecRequest{Name: "Alex", Email: "alex@example.com", Password: "short"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, audit := newTestExecService()
+
+			exec, err := service.CreateExec(context.Background(), &tt.req)
+			if tt.wantErr {
+				var validationErr *errors.ValidationError
+				assert.ErrorAs(t, err, &validationErr)
+				assert.Empty(t, audit.entries)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, models.ExecRoleExec, exec.Role)
+			assert.NotContains(t, exec.PasswordHash, tt.req.Password)
+			assert.Len(t, audit.entries, 1)
+			assert.NotContains(t, string(audit.entries[0].After), "password")
+		})
+	}
+}
+
+func TestExecLogin(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestExecService()
+
+	created, err := service.CreateExec(ctx, &models.CreateExecRequest{
+		Name:     "Alex",
+		Email:    "Alex@Example.com",
+		Role:     models.ExecRoleDirector,
+		Password: "correct horse battery",
+	})
+	assert.NoError(t, err)
+
+	token, err := service.Login(ctx, &models.ExecLoginRequest{Email: " alex@example.com", Password: "correct horse battery"}, "10.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, created.ID, token.Exec.ID)
+
+	identity, err := service.VerifyToken(ctx, token.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, created.ID, identity.ID)
+	assert.Equal(t, "Alex", identity.Name)
+	assert.Equal(t, "director", identity.Role)
+
+	var unauthorizedErr *errors.UnauthorizedError
+
+	_, err = service.Login(ctx, &models.ExecLoginRequest{Email: "alex@example.com", Password: "wrong password"}, "10.0.0.1")
+	assert.ErrorAs(t, err, &unauthorizedErr)
+
+	_, err = service.Login(ctx, &models.ExecLoginRequest{Email: "nobody@example.com", Password: "correct horse battery"}, "10.0.0.1")
+	assert.ErrorAs(t, err, &unauthorizedErr)
+
+	_, err = service.UpdateExec(ctx, created.ID, &models.UpdateExecRequest{Active: new(bool)})
+	assert.NoError(t, err)
+
+	_, err = service.Login(ctx, &models.ExecLoginRequest{Email: "alex@example.com", Password: "correct horse battery"}, "10.0.0.1")
+	assert.ErrorAs(t, err, &unauthorizedErr)
+
+	// Tokens issued before the exec was deactivated stop working at once
+	_, err = service.VerifyToken(ctx, token.Token)
+	assert.ErrorAs(t, err, &unauthorizedErr)
+}
+
+func TestExecLoginLockout(t *testing.T) {
+	ctx := context.Background()
+	service, audit := newTestExecService()
+
+	_, err := service.CreateExec(ctx, &models.CreateExecRequest{
+		Name:     "Alex",
+		Email:    "alex@example.com",
+		Password: "correct horse battery",
+	})
+	assert.NoError(t, err)
+
+	wrong := &models.ExecLoginRequest{Email: "alex@example.com", Password: "wrong password"}
+	for i := 0; i < DefaultExecServiceConfig().MaxFailedLogins; i++ {
+		_, err = service.Login(ctx, wrong, "10.0.0.2")
+		var unauthorizedErr *errors.UnauthorizedError
+		assert.ErrorAs(t, err, &unauthorizedErr)
+	}
+
+	// The right password is refused while the IP is locked out
+	right := &models.ExecLoginRequest{Email: "alex@example.com", Password: "correct horse battery"}
+	var lockedOutErr *errors.LockedOutError
+	_, err = service.Login(ctx, right, "10.0.0.2")
+	assert.ErrorAs(t, err, &lockedOutErr)
+	assert.Equal(t, AuditAuthLockout, audit.entries[len(audit.entries)-1].Action)
+
+	_, err = service.Login(ctx, right, "10.0.0.3")
+	assert.NoError(t, err)
+}
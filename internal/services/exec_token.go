@@ -0,0 +1,89 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
+)
+
+// execTokenHeader is the fixed JWT header. Tokens carrying any other header
+// are rejected, which rules out "alg": "none" style downgrades.
+var execTokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+type execTokenClaims struct {
+	Subject   string `json:"sub"`
+	Name      string `json:"name"`
+	Role      string `json:"role"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// execTokenSigner issues and verifies HS256 JWTs identifying an exec
+type execTokenSigner struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+func newExecTokenSigner(secret []byte, ttl time.Duration) *execTokenSigner {
+	return &execTokenSigner{secret: secret, ttl: ttl, now: time.Now}
+}
+
+func (s *execTokenSigner) Sign(exec *auth.ExecIdentity) (string, time.Time, error) {
+	now := s.now()
+	expiresAt := now.Add(s.ttl).Truncate(time.Second)
+
+	claims, err := json.Marshal(execTokenClaims{
+		Subject:   exec.ID,
+		Name:      exec.Name,
+		Role:      exec.Role,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	signingInput := execTokenHeader + "." + base64.RawURLEncoding.EncodeToString(claims)
+	return signingInput + "." + s.signature(signingInput), expiresAt, nil
+}
+
+func (s *execTokenSigner) Verify(token string) (*auth.ExecIdentity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != execTokenHeader {
+		return nil, fmt.Errorf("malformed exec token")
+	}
+
+	signingInput := parts[0] + "." + parts[1]
+	if !hmac.Equal([]byte(parts[2]), []byte(s.signature(signingInput))) {
+		return nil, fmt.Errorf("invalid exec token signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed exec token: %w", err)
+	}
+
+	var claims execTokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("malformed exec token: %w", err)
+	}
+
+	if !s.now().Before(time.Unix(claims.ExpiresAt, 0)) {
+		return nil, fmt.Errorf("exec token expired")
+	}
+
+	return &auth.ExecIdentity{ID: claims.Subject, Name: claims.Name, Role: claims.Role}, nil
+}
+
+func (s *execTokenSigner) signature(signingInput string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(signingInput))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
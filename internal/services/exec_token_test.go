@@ -0,0 +1,74 @@
+package services
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
+)
+
+func TestExecTokenRoundTrip(t *testing.T) {
+	signer := newExecTokenSigner([]byte("secret"), time.Hour)
+	exec := &auth.ExecIdentity{ID: "a1b2", Name: "Alex", Role: "director"}
+
+	token, expiresAt, err := signer.Sign(exec)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Second)
+
+	verified, err := signer.Verify(token)
+	assert.NoError(t, err)
+	assert.Equal(t, exec, verified)
+}
+
+func TestExecTokenRejected(t *testing.T) {
+	signer := newExecTokenSigner([]byte("secret"), time.Hour)
+	token, _, err := signer.Sign(&auth.ExecIdentity{ID: "a1b2", Name: "Alex", Role: "exec"})
+	assert.NoError(t, err)
+	parts := strings.Split(token, ".")
+
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"a1b2","name":"Alex","role":"director","exp":9999999999}`))
+	noneHeader := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+
+	tests := map[string]string{
+		"empty":             "",
+		"wrong secret":      mustSign(t, newExecTokenSigner([]byte("other"), time.Hour)),
+		"tampered claims":   parts[0] + "." + forged + "." + parts[2],
+		"unsigned":          noneHeader + "." + parts[1] + ".",
+		"missing signature": parts[0] + "." + parts[1],
+		"garbage":           "not.a.token",
+	}
+
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := signer.Verify(token)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestExecTokenExpiry(t *testing.T) {
+	now := time.Now()
+	signer := newExecTokenSigner([]byte("secret"), time.Hour)
+	signer.now = func() time.Time { return now }
+
+	token := mustSign(t, signer)
+
+	now = now.Add(59 * time.Minute)
+	_, err := signer.Verify(token)
+	assert.NoError(t, err)
+
+	now = now.Add(time.Minute)
+	_, err = signer.Verify(token)
+	assert.Error(t, err)
+}
+
+func mustSign(t *testing.T, signer *execTokenSigner) string {
+	token, _, err := signer.Sign(&auth.ExecIdentity{ID: "a1b2", Name: "Alex", Role: "exec"})
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return token
+}
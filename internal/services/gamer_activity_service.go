@@ -2,23 +2,58 @@ package services
 
 import (
 	"context"
+	goerrors "errors"
 	"fmt"
 	"time"
 
 	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
 	"github.com/ubcesports/echo-base/internal/interfaces/gamer"
+	"github.com/ubcesports/echo-base/internal/interfaces/reservation"
+	"github.com/ubcesports/echo-base/internal/interfaces/station"
 	"github.com/ubcesports/echo-base/internal/models"
+	"github.com/ubcesports/echo-base/internal/utils"
 )
 
+type GamerActivityServiceConfig struct {
+	// OvertimeGrace is how long a session may run past its tier's allowance
+	// before the overtime reaper acts on it
+	OvertimeGrace time.Duration
+	// ReapInterval is how often active sessions are checked for overtime
+	ReapInterval time.Duration
+	// AutoEndOverdue makes the reaper end overdue sessions instead of only
+	// flagging them
+	AutoEndOverdue bool
+}
+
+func DefaultGamerActivityServiceConfig() GamerActivityServiceConfig {
+	return GamerActivityServiceConfig{
+		OvertimeGrace: 10 * time.Minute,
+		ReapInterval:  time.Minute,
+	}
+}
+
 type gamerActivityService struct {
 	activityRepo gamer.GamerActivityRepository
 	profileRepo  gamer.GamerProfileRepository
+	stations     station.StationRepository
+	reservations reservation.ReservationRepository
+	audit        AuditRecorder
+	config       GamerActivityServiceConfig
+}
+
+func NewGamerActivityService(activityRepo gamer.GamerActivityRepository, profileRepo gamer.GamerProfileRepository, stations station.StationRepository, reservations reservation.ReservationRepository, audit AuditRecorder) GamerActivityService {
+	return NewGamerActivityServiceWithConfig(activityRepo, profileRepo, stations, reservations, audit, DefaultGamerActivityServiceConfig())
 }
 
-func NewGamerActivityService(activityRepo gamer.GamerActivityRepository, profileRepo gamer.GamerProfileRepository) GamerActivityService {
+func NewGamerActivityServiceWithConfig(activityRepo gamer.GamerActivityRepository, profileRepo gamer.GamerProfileRepository, stations station.StationRepository, reservations reservation.ReservationRepository, audit AuditRecorder, config GamerActivityServiceConfig) *gamerActivityService {
 	return &gamerActivityService{
 		activityRepo: activityRepo,
 		profileRepo:  profileRepo,
+		stations:     stations,
+		reservations: reservations,
+		audit:        audit,
+		config:       config,
 	}
 }
 
@@ -64,11 +99,23 @@ func (s *gamerActivityService) StartActivity(ctx context.Context, req *models.Cr
 		return nil, errors.NewValidationError("game", "is required")
 	}
 
+	if err := checkStationAvailable(ctx, s.stations, "pc_number", req.PCNumber); err != nil {
+		return nil, err
+	}
+
 	tierNum, expiryDate, err := s.profileRepo.CheckMembershipValidity(ctx, req.StudentNumber)
 	if err != nil {
 		return nil, errors.NewNotFoundError("student", req.StudentNumber)
 	}
 
+	ban, err := s.profileRepo.GetActiveBan(ctx, req.StudentNumber)
+	if err != nil {
+		return nil, err
+	}
+	if ban != nil {
+		return nil, errors.NewForbiddenError(banMessage(ban))
+	}
+
 	tier, err := models.NewMembershipTier(tierNum)
 	if err != nil {
 		return nil, fmt.Errorf("invalid membership tier: %w", err)
@@ -84,17 +131,126 @@ func (s *gamerActivityService) StartActivity(ctx context.Context, req *models.Cr
 		if expiryDate != nil {
 			expiryDateStr = expiryDate.Format("2006-01-02")
 		}
-		return nil, errors.NewForbiddenError(fmt.Sprintf("%s membership expired on %s. Please ask the user to purchase a new membership. If the member has already purchased a new membership for this year please verify via Showpass then create a new profile for them.", tier.GetName(), expiryDateStr))
+		return nil, errors.NewForbiddenError(fmt.Sprintf("%s membership expired on %s. Please ask the user to purchase a new membership. If the member has already purchased a new membership for this year please verify via Showpass then renew their membership.", tier.GetName(), expiryDateStr))
 	}
 
+	now := time.Now()
 	activity := &models.GamerActivity{
 		StudentNumber: req.StudentNumber,
 		PCNumber:      req.PCNumber,
 		Game:          req.Game,
-		StartedAt:     time.Now(),
+		StartedAt:     now,
+	}
+	if ms := tier.GetSessionDurationMs(); ms > 0 {
+		expiresAt := now.Add(time.Duration(ms) * time.Millisecond)
+		activity.ExpiresAt = &expiresAt
+	}
+
+	// Sessions are cut short to what is left of the daily allowance
+	if tier.HasDailyLimit() {
+		quota, err := s.playQuota(ctx, req.StudentNumber, tierNum, tier, now)
+		if err != nil {
+			return nil, err
+		}
+		if *quota.RemainingMs <= 0 {
+			return nil, errors.NewForbiddenError(fmt.Sprintf("%s daily play time of %s is used up. It resets at %s.", tier.GetName(), time.Duration(*quota.LimitMs)*time.Millisecond, quota.ResetsAt.In(utils.LoungeLocation).Format(time.RFC3339)))
+		}
+		capped := now.Add(time.Duration(*quota.RemainingMs) * time.Millisecond)
+		if activity.ExpiresAt == nil || capped.Before(*activity.ExpiresAt) {
+			activity.ExpiresAt = &capped
+		}
+	}
+
+	held, err := s.applyReservations(ctx, activity, now)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.activityRepo.Create(ctx, activity)
+	if err != nil {
+		return nil, err
+	}
+	created.MembershipTier = tierNum
+	applySessionLimit(created, now)
+
+	s.audit.Record(ctx, AuditSessionStart, "session", created.ID, nil, created)
+
+	// Starting on a reserved station counts as showing up for it
+	for i := range held {
+		if held[i].Status != models.ReservationStatusBooked {
+			continue
+		}
+		checkedIn, err := s.reservations.CheckIn(ctx, held[i].ID, now)
+		if err != nil {
+			return nil, err
+		}
+		if checkedIn {
+			before := held[i]
+			held[i].Status = models.ReservationStatusCheckedIn
+			held[i].CheckedInAt = &now
+			s.audit.Record(ctx, AuditReservationCheckIn, "reservation", held[i].ID, &before, &held[i])
+		}
+	}
+	return created, nil
+}
+
+// checkStationAvailable makes sure sessions and reservations are only made
+// on stations that exist and aren't under maintenance or retired
+func checkStationAvailable(ctx context.Context, stations station.StationRepository, field string, number int) error {
+	st, err := stations.Get(ctx, number)
+	var notFound *errors.NotFoundError
+	if goerrors.As(err, &notFound) {
+		return errors.NewValidationError(field, fmt.Sprintf("there is no station %d", number))
+	}
+	if err != nil {
+		return err
+	}
+
+	if st.Status != models.StationStatusAvailable {
+		msg := fmt.Sprintf("station %d is %s", number, st.Status)
+		if st.StatusReason != nil {
+			msg += ": " + *st.StatusReason
+		}
+		return errors.NewConflictError(msg)
+	}
+	return nil
+}
+
+// applyReservations keeps a session off other members' reservations. A
+// station reserved for someone else right now can't be used, and a session
+// runs out when the next reservation for someone else begins. It returns the
+// member's own reservations on the station that have begun, which the session
+// checks in. Their later reservations are left booked.
+func (s *gamerActivityService) applyReservations(ctx context.Context, activity *models.GamerActivity, now time.Time) ([]models.Reservation, error) {
+	// Sessions without a limit still make way for reservations later that day
+	windowEnd := now.Add(24 * time.Hour)
+	if activity.ExpiresAt != nil {
+		windowEnd = *activity.ExpiresAt
+	}
+
+	reservations, err := s.reservations.GetOverlapping(ctx, []int{activity.PCNumber}, now, windowEnd)
+	if err != nil {
+		return nil, err
 	}
 
-	return s.activityRepo.Create(ctx, activity)
+	var held []models.Reservation
+	for i := range reservations {
+		res := &reservations[i]
+		if res.IsHeldFor(activity.StudentNumber) {
+			if res.Covers(now) {
+				held = append(held, *res)
+			}
+			continue
+		}
+		if !res.StartsAt.After(now) {
+			return nil, errors.NewConflictError(reservationMessage(activity.PCNumber, res))
+		}
+		if activity.ExpiresAt == nil || res.StartsAt.Before(*activity.ExpiresAt) {
+			startsAt := res.StartsAt
+			activity.ExpiresAt = &startsAt
+		}
+	}
+	return held, nil
 }
 
 func (s *gamerActivityService) EndActivity(ctx context.Context, studentNumber string, req *models.UpdateActivityRequest) (*models.GamerActivity, error) {
@@ -102,15 +258,99 @@ func (s *gamerActivityService) EndActivity(ctx context.Context, studentNumber st
 		return nil, err
 	}
 
-	if req.ExecName == "" {
-		return nil, errors.NewValidationError("exec_name", "is required")
+	exec := auth.ExecFromContext(ctx)
+	if exec == nil {
+		return nil, errors.NewUnauthorizedError("an exec must be signed in to end a session")
+	}
+
+	ended, err := s.activityRepo.UpdateEndTime(ctx, studentNumber, req.PCNumber, time.Now(), exec.ID, exec.Name)
+	if err != nil {
+		return nil, err
 	}
 
-	return s.activityRepo.UpdateEndTime(ctx, studentNumber, req.PCNumber, time.Now(), req.ExecName)
+	before := *ended
+	before.EndedAt = nil
+	before.ExecName = nil
+	before.ExecID = nil
+	s.audit.Record(ctx, AuditSessionEnd, "session", ended.ID, &before, ended)
+	return ended, nil
 }
 
 func (s *gamerActivityService) GetActiveSessions(ctx context.Context) ([]models.GamerActivity, error) {
-	return s.activityRepo.GetActiveSessions(ctx)
+	sessions, err := s.activityRepo.GetActiveSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range sessions {
+		applySessionLimit(&sessions[i], now)
+	}
+	return sessions, nil
+}
+
+// GetQuota reports how much of a member's daily play time is used
+func (s *gamerActivityService) GetQuota(ctx context.Context, studentNumber string) (*models.PlayQuota, error) {
+	if err := validateStudentNumber(studentNumber); err != nil {
+		return nil, err
+	}
+
+	tierNum, _, err := s.profileRepo.CheckMembershipValidity(ctx, studentNumber)
+	if err != nil {
+		return nil, errors.NewNotFoundError("student", studentNumber)
+	}
+
+	tier, err := models.NewMembershipTier(tierNum)
+	if err != nil {
+		return nil, fmt.Errorf("invalid membership tier: %w", err)
+	}
+
+	return s.playQuota(ctx, studentNumber, tierNum, tier, time.Now())
+}
+
+// playQuota adds up the time a member has played on the lounge's current
+// day. The daily allowance of a tier with a daily limit is one session.
+func (s *gamerActivityService) playQuota(ctx context.Context, studentNumber string, tierNum int, tier models.MembershipTier, now time.Time) (*models.PlayQuota, error) {
+	dayStart, dayEnd := utils.LoungeDay(now)
+	sessions, err := s.activityRepo.GetBetween(ctx, studentNumber, dayStart, dayEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	quota := &models.PlayQuota{
+		StudentNumber:  studentNumber,
+		MembershipTier: tierNum,
+		DailyLimit:     tier.HasDailyLimit(),
+		UsedMs:         playedBetween(sessions, dayStart, now).Milliseconds(),
+		ResetsAt:       dayEnd,
+	}
+	if quota.DailyLimit {
+		limit := tier.GetSessionDurationMs()
+		remaining := max(limit-quota.UsedMs, 0)
+		quota.LimitMs = &limit
+		quota.RemainingMs = &remaining
+	}
+	return quota, nil
+}
+
+// playedBetween adds up the time sessions ran between from and to. Sessions
+// still running count up to to.
+func playedBetween(sessions []models.GamerActivity, from, to time.Time) time.Duration {
+	var played time.Duration
+	for _, session := range sessions {
+		start := session.StartedAt
+		if start.Before(from) {
+			start = from
+		}
+		end := to
+		if session.EndedAt != nil && session.EndedAt.Before(to) {
+			end = *session.EndedAt
+		}
+		if end.After(start) {
+			played += end.Sub(start)
+		}
+	}
+	return played
 }
 
 func getMembershipYearWindow(now time.Time) (time.Time, time.Time) {
@@ -123,3 +363,11 @@ func getMembershipYearWindow(now time.Time) (time.Time, time.Time) {
 
 	return currentMayFirst, currentMayFirst.AddDate(1, 0, 0)
 }
+
+// banMessage explains an active ban to the exec trying to sign the member in
+func banMessage(ban *models.Ban) string {
+	if ban.EndsAt == nil {
+		return fmt.Sprintf("%s is banned: %s", ban.StudentNumber, ban.Reason)
+	}
+	return fmt.Sprintf("%s is suspended until %s: %s", ban.StudentNumber, ban.EndsAt.Format(time.RFC3339), ban.Reason)
+}
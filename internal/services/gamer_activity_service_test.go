@@ -2,11 +2,16 @@ package services
 
 import (
 	"context"
+	goerrors "errors"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
 	"github.com/ubcesports/echo-base/internal/models"
+	"github.com/ubcesports/echo-base/internal/utils"
 )
 
 type mockGamerActivityRepository struct {
@@ -36,6 +41,16 @@ func (m *mockGamerActivityRepository) GetTodayActivitiesByStudent(ctx context.Co
 	return result, nil
 }
 
+func (m *mockGamerActivityRepository) GetBetween(ctx context.Context, studentNumber string, windowStart, windowEnd time.Time) ([]models.GamerActivity, error) {
+	var result []models.GamerActivity
+	for _, a := range m.activities {
+		if a.StudentNumber == studentNumber && a.StartedAt.Before(windowEnd) && (a.EndedAt == nil || a.EndedAt.After(windowStart)) {
+			result = append(result, a)
+		}
+	}
+	return result, nil
+}
+
 func (m *mockGamerActivityRepository) GetRecentActivities(ctx context.Context, page, limit int, search string) ([]models.GamerActivity, error) {
 	return m.activities, nil
 }
@@ -47,14 +62,20 @@ func (m *mockGamerActivityRepository) GetExecLeaderboard(ctx context.Context, wi
 }
 
 func (m *mockGamerActivityRepository) Create(ctx context.Context, activity *models.GamerActivity) (*models.GamerActivity, error) {
+	for _, a := range m.activities {
+		if a.EndedAt == nil && (a.PCNumber == activity.PCNumber || a.StudentNumber == activity.StudentNumber) {
+			return nil, errors.NewConflictError(fmt.Sprintf("PC %d is in use by %s", a.PCNumber, a.StudentNumber))
+		}
+	}
 	m.activities = append(m.activities, *activity)
 	return activity, nil
 }
 
-func (m *mockGamerActivityRepository) UpdateEndTime(ctx context.Context, studentNumber string, pcNumber int, endedAt time.Time, execName string) (*models.GamerActivity, error) {
+func (m *mockGamerActivityRepository) UpdateEndTime(ctx context.Context, studentNumber string, pcNumber int, endedAt time.Time, execID, execName string) (*models.GamerActivity, error) {
 	for i, a := range m.activities {
 		if a.StudentNumber == studentNumber && a.PCNumber == pcNumber && a.EndedAt == nil {
 			m.activities[i].EndedAt = &endedAt
+			m.activities[i].ExecID = &execID
 			m.activities[i].ExecName = &execName
 			return &m.activities[i], nil
 		}
@@ -72,6 +93,27 @@ func (m *mockGamerActivityRepository) GetActiveSessions(ctx context.Context) ([]
 	return result, nil
 }
 
+func (m *mockGamerActivityRepository) FlagOverdue(ctx context.Context, id string, flaggedAt time.Time) (bool, error) {
+	for i, a := range m.activities {
+		if a.ID == id && a.EndedAt == nil && a.OverdueFlaggedAt == nil {
+			m.activities[i].OverdueFlaggedAt = &flaggedAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockGamerActivityRepository) EndOverdue(ctx context.Context, id string, endedAt time.Time) (*models.GamerActivity, error) {
+	for i, a := range m.activities {
+		if a.ID == id && a.EndedAt == nil {
+			m.activities[i].EndedAt = &endedAt
+			m.activities[i].EndedBySystem = true
+			return &m.activities[i], nil
+		}
+	}
+	return nil, nil
+}
+
 func TestStartActivity(t *testing.T) {
 	now := time.Now()
 	yesterday := now.AddDate(0, 0, -1)
@@ -83,6 +125,7 @@ func TestStartActivity(t *testing.T) {
 		tierNum       int
 		expiryDate    *time.Time
 		profileExists bool
+		ban           *models.Ban
 		wantErr       bool
 		errContains   string
 	}{
@@ -111,6 +154,60 @@ func TestStartActivity(t *testing.T) {
 			wantErr:       true,
 			errContains:   "expired",
 		},
+		{
+			name: "active ban",
+			req: &models.CreateActivityRequest{
+				StudentNumber: "12345678",
+				PCNumber:      1,
+				Game:          "League of Legends",
+			},
+			tierNum:       1,
+			expiryDate:    &tomorrow,
+			profileExists: true,
+			ban:           &models.Ban{StudentNumber: "12345678", Reason: "Unplugged a PC", StartsAt: yesterday},
+			wantErr:       true,
+			errContains:   "banned: Unplugged a PC",
+		},
+		{
+			name: "active suspension",
+			req: &models.CreateActivityRequest{
+				StudentNumber: "12345678",
+				PCNumber:      1,
+				Game:          "League of Legends",
+			},
+			tierNum:       1,
+			expiryDate:    &tomorrow,
+			profileExists: true,
+			ban:           &models.Ban{StudentNumber: "12345678", Reason: "Shouting", StartsAt: yesterday, EndsAt: &tomorrow},
+			wantErr:       true,
+			errContains:   "suspended until",
+		},
+		{
+			name: "ended suspension",
+			req: &models.CreateActivityRequest{
+				StudentNumber: "12345678",
+				PCNumber:      1,
+				Game:          "League of Legends",
+			},
+			tierNum:       1,
+			expiryDate:    &tomorrow,
+			profileExists: true,
+			ban:           &models.Ban{StudentNumber: "12345678", Reason: "Shouting", StartsAt: yesterday.AddDate(0, 0, -1), EndsAt: &yesterday},
+			wantErr:       false,
+		},
+		{
+			name: "lifted ban",
+			req: &models.CreateActivityRequest{
+				StudentNumber: "12345678",
+				PCNumber:      1,
+				Game:          "League of Legends",
+			},
+			tierNum:       1,
+			expiryDate:    &tomorrow,
+			profileExists: true,
+			ban:           &models.Ban{StudentNumber: "12345678", Reason: "Shouting", StartsAt: yesterday, LiftedAt: &now},
+			wantErr:       false,
+		},
 		{
 			name: "student not found",
 			req: &models.CreateActivityRequest{
@@ -164,8 +261,11 @@ func TestStartActivity(t *testing.T) {
 					MembershipExpiryDate: tt.expiryDate,
 				}
 			}
+			if tt.ban != nil {
+				mockProfileRepo.bans = []models.Ban{*tt.ban}
+			}
 
-			service := NewGamerActivityService(mockActivityRepo, mockProfileRepo)
+			service := NewGamerActivityService(mockActivityRepo, mockProfileRepo, newMockStationRepository(), newMockReservationRepository(), &mockAuditRecorder{})
 
 			activity, err := service.StartActivity(context.Background(), tt.req)
 
@@ -206,7 +306,7 @@ func TestGetRecentActivities(t *testing.T) {
 			mockProfileRepo := &mockGamerProfileRepository{
 				profiles: make(map[string]*models.GamerProfile),
 			}
-			service := NewGamerActivityService(mockActivityRepo, mockProfileRepo)
+			service := NewGamerActivityService(mockActivityRepo, mockProfileRepo, newMockStationRepository(), newMockReservationRepository(), &mockAuditRecorder{})
 
 			_, err := service.GetRecentActivities(context.Background(), tt.page, tt.limit, "")
 			if (err != nil) != tt.wantErr {
@@ -217,10 +317,13 @@ func TestGetRecentActivities(t *testing.T) {
 }
 
 func TestEndActivity(t *testing.T) {
+	admin := &auth.ExecIdentity{ID: "8f14e45f-ceea-467f-a0e6-2a1b5f1d3c11", Name: "Admin"}
+
 	tests := []struct {
 		name          string
 		studentNumber string
 		req           *models.UpdateActivityRequest
+		exec          *auth.ExecIdentity
 		wantErr       bool
 	}{
 		{
@@ -228,8 +331,8 @@ func TestEndActivity(t *testing.T) {
 			studentNumber: "12345678",
 			req: &models.UpdateActivityRequest{
 				PCNumber: 1,
-				ExecName: "Admin",
 			},
+			exec:    admin,
 			wantErr: false,
 		},
 		{
@@ -237,17 +340,17 @@ func TestEndActivity(t *testing.T) {
 			studentNumber: "123",
 			req: &models.UpdateActivityRequest{
 				PCNumber: 1,
-				ExecName: "Admin",
 			},
+			exec:    admin,
 			wantErr: true,
 		},
 		{
-			name:          "missing exec name",
+			name:          "no exec signed in",
 			studentNumber: "12345678",
 			req: &models.UpdateActivityRequest{
 				PCNumber: 1,
-				ExecName: "",
 			},
+			exec:    nil,
 			wantErr: true,
 		},
 	}
@@ -268,12 +371,20 @@ func TestEndActivity(t *testing.T) {
 			mockProfileRepo := &mockGamerProfileRepository{
 				profiles: make(map[string]*models.GamerProfile),
 			}
-			service := NewGamerActivityService(mockActivityRepo, mockProfileRepo)
+			service := NewGamerActivityService(mockActivityRepo, mockProfileRepo, newMockStationRepository(), newMockReservationRepository(), &mockAuditRecorder{})
 
-			_, err := service.EndActivity(context.Background(), tt.studentNumber, tt.req)
+			ctx := context.Background()
+			if tt.exec != nil {
+				ctx = auth.WithExec(ctx, tt.exec)
+			}
+
+			activity, err := service.EndActivity(ctx, tt.studentNumber, tt.req)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("EndActivity() error = %v, wantErr %v", err, tt.wantErr)
 			}
+			if err == nil && (activity.ExecID == nil || *activity.ExecID != tt.exec.ID) {
+				t.Errorf("expected session to be signed out by exec %s, got %v", tt.exec.ID, activity.ExecID)
+			}
 		})
 	}
 }
@@ -317,3 +428,132 @@ func TestGetMembershipYearWindow(t *testing.T) {
 		})
 	}
 }
+
+func TestPlayQuota(t *testing.T) {
+	// Noon in the lounge
+	now := time.Date(2026, time.October, 18, 19, 0, 0, 0, time.UTC)
+	dayStart, dayEnd := utils.LoungeDay(now)
+	tenPastMidnight := dayStart.Add(10 * time.Minute)
+	twentyPastTen := now.Add(-100 * time.Minute)
+
+	mockActivityRepo := &mockGamerActivityRepository{
+		activities: []models.GamerActivity{
+			// Started before midnight, only the time after it counts
+			{StudentNumber: "12345678", StartedAt: dayStart.Add(-30 * time.Minute), EndedAt: &tenPastMidnight},
+			{StudentNumber: "12345678", StartedAt: now.Add(-2 * time.Hour), EndedAt: &twentyPastTen},
+			// Still running
+			{StudentNumber: "12345678", StartedAt: now.Add(-15 * time.Minute)},
+			{StudentNumber: "87654321", StartedAt: now.Add(-15 * time.Minute)},
+		},
+	}
+	service := NewGamerActivityServiceWithConfig(mockActivityRepo, &mockGamerProfileRepository{}, newMockStationRepository(), newMockReservationRepository(), &mockAuditRecorder{}, DefaultGamerActivityServiceConfig())
+
+	tier1, _ := models.NewMembershipTier(1)
+	quota, err := service.playQuota(context.Background(), "12345678", 1, tier1, now)
+	if err != nil {
+		t.Fatalf("playQuota() error = %v", err)
+	}
+	if quota.UsedMs != (45 * time.Minute).Milliseconds() {
+		t.Errorf("used = %s, want 45m", time.Duration(quota.UsedMs)*time.Millisecond)
+	}
+	if quota.RemainingMs == nil || *quota.RemainingMs != (15*time.Minute).Milliseconds() {
+		t.Errorf("remaining = %v, want 15m", quota.RemainingMs)
+	}
+	if !quota.ResetsAt.Equal(dayEnd) || quota.ResetsAt.In(utils.LoungeLocation).Hour() != 0 {
+		t.Errorf("expected quota to reset at the lounge's midnight, got %v", quota.ResetsAt)
+	}
+
+	tier2, _ := models.NewMembershipTier(2)
+	quota, err = service.playQuota(context.Background(), "12345678", 2, tier2, now)
+	if err != nil {
+		t.Fatalf("playQuota() error = %v", err)
+	}
+	if quota.DailyLimit || quota.LimitMs != nil || quota.RemainingMs != nil {
+		t.Errorf("expected Tier 2 to have no daily limit, got %+v", quota)
+	}
+	if quota.UsedMs != (45 * time.Minute).Milliseconds() {
+		t.Errorf("expected used time for any tier, got %d", quota.UsedMs)
+	}
+}
+
+func TestStartActivityDailyLimit(t *testing.T) {
+	now := time.Now()
+	dayStart, _ := utils.LoungeDay(now)
+	tomorrow := now.AddDate(0, 0, 1)
+	newService := func(activities []models.GamerActivity) GamerActivityService {
+		mockProfileRepo := &mockGamerProfileRepository{
+			profiles: map[string]*models.GamerProfile{
+				"12345678": {StudentNumber: "12345678", MembershipTier: 1, MembershipExpiryDate: &tomorrow},
+			},
+		}
+		return NewGamerActivityService(&mockGamerActivityRepository{activities: activities}, mockProfileRepo, newMockStationRepository(), newMockReservationRepository(), &mockAuditRecorder{})
+	}
+	req := &models.CreateActivityRequest{StudentNumber: "12345678", PCNumber: 1, Game: "Valorant"}
+
+	t.Run("capped to what is left", func(t *testing.T) {
+		startedAt := now.Add(-21 * time.Minute)
+		endedAt := now.Add(-time.Minute)
+		service := newService([]models.GamerActivity{{StudentNumber: "12345678", StartedAt: startedAt, EndedAt: &endedAt}})
+
+		activity, err := service.StartActivity(context.Background(), req)
+		if err != nil {
+			t.Fatalf("StartActivity() error = %v", err)
+		}
+		used := playedBetween([]models.GamerActivity{{StartedAt: startedAt, EndedAt: &endedAt}}, dayStart, now)
+		want := activity.StartedAt.Add(time.Hour - used)
+		if activity.ExpiresAt == nil || activity.ExpiresAt.Sub(want).Abs() > time.Second {
+			t.Errorf("expires_at = %v, want %v", activity.ExpiresAt, want)
+		}
+	})
+
+	t.Run("used up", func(t *testing.T) {
+		if now.Sub(dayStart) < time.Hour {
+			t.Skip("the lounge's day is less than an hour old")
+		}
+		service := newService([]models.GamerActivity{{StudentNumber: "12345678", PCNumber: 2, StartedAt: dayStart.Add(-time.Hour)}})
+
+		_, err := service.StartActivity(context.Background(), req)
+		var forbiddenErr *errors.ForbiddenError
+		if !goerrors.As(err, &forbiddenErr) || !strings.Contains(err.Error(), "used up") {
+			t.Errorf("expected a ForbiddenError, got %v", err)
+		}
+	})
+}
+
+func TestStartActivityConflict(t *testing.T) {
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	mockProfileRepo := &mockGamerProfileRepository{
+		profiles: map[string]*models.GamerProfile{
+			"12345678": {StudentNumber: "12345678", MembershipTier: 2, MembershipExpiryDate: &tomorrow},
+			"87654321": {StudentNumber: "87654321", MembershipTier: 2, MembershipExpiryDate: &tomorrow},
+		},
+	}
+	mockActivityRepo := &mockGamerActivityRepository{}
+	recorder := &mockAuditRecorder{}
+	service := NewGamerActivityService(mockActivityRepo, mockProfileRepo, newMockStationRepository(), newMockReservationRepository(), recorder)
+	ctx := context.Background()
+
+	if _, err := service.StartActivity(ctx, &models.CreateActivityRequest{StudentNumber: "12345678", PCNumber: 1, Game: "Valorant"}); err != nil {
+		t.Fatalf("StartActivity() error = %v", err)
+	}
+
+	clashes := []struct {
+		name string
+		req  models.CreateActivityRequest
+	}{
+		{"PC in use", models.CreateActivityRequest{StudentNumber: "87654321", PCNumber: 1, Game: "Dota"}},
+		{"student already signed in", models.CreateActivityRequest{StudentNumber: "12345678", PCNumber: 2, Game: "Dota"}},
+	}
+	for _, tt := range clashes {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.StartActivity(ctx, &tt.req)
+			var conflictErr *errors.ConflictError
+			if !goerrors.As(err, &conflictErr) {
+				t.Errorf("expected ConflictError, got %v", err)
+			}
+		})
+	}
+	if len(mockActivityRepo.activities) != 1 || len(recorder.entries) != 1 {
+		t.Errorf("expected clashing sessions not to be started, got %d sessions", len(mockActivityRepo.activities))
+	}
+}
@@ -2,23 +2,32 @@ package services
 
 import (
 	"context"
+	goerrors "errors"
 	"fmt"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
 	"github.com/ubcesports/echo-base/internal/interfaces/gamer"
 	"github.com/ubcesports/echo-base/internal/models"
 )
 
 var studentNumberRegex = regexp.MustCompile(`^\d{8}$`)
 
+// discordHandleRegex matches Discord usernames, which are lowercase
+var discordHandleRegex = regexp.MustCompile(`^[a-z0-9_.]{2,32}$`)
+
+const MaxProfilesPerPage = 100
+
 type gamerProfileService struct {
-	repo gamer.GamerProfileRepository
+	repo  gamer.GamerProfileRepository
+	audit AuditRecorder
 }
 
-func NewGamerProfileService(repo gamer.GamerProfileRepository) GamerProfileService {
-	return &gamerProfileService{repo: repo}
+func NewGamerProfileService(repo gamer.GamerProfileRepository, audit AuditRecorder) GamerProfileService {
+	return &gamerProfileService{repo: repo, audit: audit}
 }
 
 func (s *gamerProfileService) GetProfile(ctx context.Context, studentNumber string) (*models.GamerProfile, error) {
@@ -29,22 +38,58 @@ func (s *gamerProfileService) GetProfile(ctx context.Context, studentNumber stri
 	return s.repo.GetByStudentNumber(ctx, studentNumber)
 }
 
-func (s *gamerProfileService) CreateOrUpdateProfile(ctx context.Context, req *models.CreateGamerProfileRequest) (*models.GamerProfile, error) {
-	if err := validateStudentNumber(req.StudentNumber); err != nil {
-		return nil, err
+// ListProfiles returns a page of profiles matching filter, sorted by name
+func (s *gamerProfileService) ListProfiles(ctx context.Context, filter models.ProfileFilter) (*models.GamerProfilePage, error) {
+	if filter.Page < 1 {
+		return nil, errors.NewValidationError("page", "must be >= 1")
 	}
 
-	if req.FirstName == "" {
-		return nil, errors.NewValidationError("first_name", "is required")
+	if filter.Limit < 1 || filter.Limit > MaxProfilesPerPage {
+		return nil, errors.NewValidationError("limit", fmt.Sprintf("must be between 1 and %d", MaxProfilesPerPage))
 	}
 
-	if req.LastName == "" {
-		return nil, errors.NewValidationError("last_name", "is required")
+	if filter.MembershipTier != nil {
+		if _, err := models.NewMembershipTier(*filter.MembershipTier); err != nil {
+			return nil, errors.NewValidationError("membership_tier", err.Error())
+		}
 	}
 
-	tier, err := models.NewMembershipTier(req.MembershipTier)
+	if filter.ExpiresAfter != nil && filter.ExpiresBefore != nil && !filter.ExpiresAfter.Before(*filter.ExpiresBefore) {
+		return nil, errors.NewValidationError("expires_after", "must be before expires_before")
+	}
+
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return nil, errors.NewValidationError("created_from", "must be before created_to")
+	}
+
+	filter.Search = strings.TrimSpace(filter.Search)
+
+	profiles, total, err := s.repo.List(ctx, filter)
 	if err != nil {
-		return nil, errors.NewValidationError("membership_tier", err.Error())
+		return nil, err
+	}
+
+	return &models.GamerProfilePage{
+		Profiles: profiles,
+		Total:    total,
+		Page:     filter.Page,
+		Limit:    filter.Limit,
+	}, nil
+}
+
+// CreateOrUpdateProfile registers a member and records a membership for a
+// paid tier. Registering an existing member again replaces their details and
+// renews them, but keeps the original join date. Use UpdateProfile to edit a
+// member without renewing.
+func (s *gamerProfileService) CreateOrUpdateProfile(ctx context.Context, req *models.CreateGamerProfileRequest) (*models.GamerProfile, error) {
+	tier, source, err := validateProfileRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	email, discordHandle, err := normalizeContact(req.Email, req.DiscordHandle)
+	if err != nil {
+		return nil, err
 	}
 
 	expiryDate, err := tier.GetExpiryDate()
@@ -58,20 +103,676 @@ func (s *gamerProfileService) CreateOrUpdateProfile(ctx context.Context, req *mo
 		LastName:             req.LastName,
 		MembershipTier:       req.MembershipTier,
 		Banned:               req.Banned,
-		Notes:                req.Notes,
 		CreatedAt:            time.Now(),
 		MembershipExpiryDate: expiryDate,
+		Email:                email,
+		DiscordHandle:        discordHandle,
+		NotificationConsent:  true,
+	}
+
+	// The profile, its notes and its membership are written together so a
+	// failure part way doesn't leave a member registered without the
+	// membership they paid for
+	var saved *models.GamerProfile
+	audit := &deferredAudit{}
+	err = s.repo.WithTx(ctx, func(repo gamer.GamerProfileRepository) error {
+		service := &gamerProfileService{repo: repo, audit: audit}
+		var err error
+		saved, err = service.saveProfile(ctx, req, profile, tier, source)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	audit.flush(ctx, s.audit)
+	return saved, nil
+}
+
+func (s *gamerProfileService) saveProfile(ctx context.Context, req *models.CreateGamerProfileRequest, profile *models.GamerProfile, tier models.MembershipTier, source models.MembershipSource) (*models.GamerProfile, error) {
+	before, err := s.findProfile(ctx, req.StudentNumber)
+	if err != nil {
+		return nil, err
+	}
+	if before == nil {
+		if err := s.checkNotDeleted(ctx, req.StudentNumber); err != nil {
+			return nil, err
+		}
+	}
+	if before != nil {
+		profile.CreatedAt = before.CreatedAt
+		if profile.Email == nil {
+			profile.Email = before.Email
+		}
+		if profile.DiscordHandle == nil {
+			profile.DiscordHandle = before.DiscordHandle
+		}
+		profile.MarketingConsent = before.MarketingConsent
+		profile.NotificationConsent = before.NotificationConsent
+	}
+	if req.MarketingConsent != nil {
+		profile.MarketingConsent = *req.MarketingConsent
+	}
+	if req.NotificationConsent != nil {
+		profile.NotificationConsent = *req.NotificationConsent
+	}
+
+	saved, err := s.repo.Upsert(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+	saved.Banned = currentBanned(before)
+
+	if req.Notes != nil && strings.TrimSpace(*req.Notes) != "" {
+		saved.Notes, err = s.setProfileNotes(ctx, saved.StudentNumber, saved.Notes, req.Notes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Registering again on the same tier isn't a renewal, so it only records
+	// a membership when the tier changes or the last one has run out
+	if req.MembershipTier > 0 && !hasCurrentMembership(before, req.MembershipTier) {
+		membership, err := s.recordMembership(ctx, saved.StudentNumber, tier, req.MembershipTier, source, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		saved.MembershipTier = membership.MembershipTier
+		saved.MembershipExpiryDate = membership.ExpiresAt
+	}
+
+	s.audit.Record(ctx, AuditProfileUpsert, "profile", saved.StudentNumber, before, saved)
+	return saved, nil
+}
+
+// checkNotDeleted refuses to register a student number whose member was
+// deleted. They are brought back with RestoreProfile instead, keeping their
+// details.
+func (s *gamerProfileService) checkNotDeleted(ctx context.Context, studentNumber string) error {
+	profile, err := s.repo.GetByStudentNumberIncludingDeleted(ctx, studentNumber)
+	var notFoundErr *errors.NotFoundError
+	if err != nil && !goerrors.As(err, &notFoundErr) {
+		return err
+	}
+	if profile != nil && profile.DeletedAt != nil {
+		return errors.NewConflictError(fmt.Sprintf("student %s was deleted; restore them with POST /v1/api/gamer/%s/restore", studentNumber, studentNumber))
+	}
+	return nil
+}
+
+// validateProfileRequest checks a registration and returns its tier and
+// membership source
+func validateProfileRequest(req *models.CreateGamerProfileRequest) (models.MembershipTier, models.MembershipSource, error) {
+	if err := validateStudentNumber(req.StudentNumber); err != nil {
+		return nil, "", err
+	}
+
+	if req.FirstName == "" {
+		return nil, "", errors.NewValidationError("first_name", "is required")
+	}
+
+	if req.LastName == "" {
+		return nil, "", errors.NewValidationError("last_name", "is required")
+	}
+
+	tier, err := models.NewMembershipTier(req.MembershipTier)
+	if err != nil {
+		return nil, "", errors.NewValidationError("membership_tier", err.Error())
+	}
+
+	if req.Banned != nil && *req.Banned {
+		return nil, "", errors.NewValidationError("banned", "can't be set on a profile, issue a ban instead")
+	}
+
+	source := req.MembershipSource
+	if source == "" {
+		source = models.MembershipSourceUnknown
+	}
+	if !source.IsValid() {
+		return nil, "", errors.NewValidationError("membership_source", "must be showpass, cash, comp or unknown")
+	}
+
+	if req.Notes != nil && strings.TrimSpace(*req.Notes) != "" {
+		if _, err := validateNoteBody("notes", *req.Notes); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if _, _, err := normalizeContact(req.Email, req.DiscordHandle); err != nil {
+		return nil, "", err
+	}
+
+	return tier, source, nil
+}
+
+// normalizeContact checks and normalizes a member's optional email and
+// Discord handle. Blank values are left out.
+func normalizeContact(email, discordHandle *string) (*string, *string, error) {
+	var normalizedEmail, normalizedHandle *string
+
+	if email != nil && strings.TrimSpace(*email) != "" {
+		e, err := normalizeEmail(*email)
+		if err != nil {
+			return nil, nil, err
+		}
+		normalizedEmail = &e
+	}
+
+	if discordHandle != nil && strings.TrimSpace(*discordHandle) != "" {
+		h := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(*discordHandle), "@"))
+		if !discordHandleRegex.MatchString(h) || strings.Contains(h, "..") {
+			return nil, nil, errors.NewValidationError("discord_handle", "must be 2 to 32 letters, numbers, underscores or periods")
+		}
+		normalizedHandle = &h
+	}
+
+	return normalizedEmail, normalizedHandle, nil
+}
+
+// UpdateProfile applies a merge patch to an existing profile. Only the
+// fields in the patch change. The membership and ban status can't be
+// patched; they come from RenewMembership, IssueBan and LiftBan.
+func (s *gamerProfileService) UpdateProfile(ctx context.Context, studentNumber string, req *models.UpdateGamerProfileRequest) (*models.GamerProfile, error) {
+	if err := validateStudentNumber(studentNumber); err != nil {
+		return nil, err
+	}
+
+	// The profile stays locked from the read to the write so a concurrent
+	// patch of other fields can't be overwritten with stale values
+	var saved *models.GamerProfile
+	audit := &deferredAudit{}
+	err := s.repo.WithTx(ctx, func(repo gamer.GamerProfileRepository) error {
+		service := &gamerProfileService{repo: repo, audit: audit}
+		var err error
+		saved, err = service.updateProfile(ctx, studentNumber, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	audit.flush(ctx, s.audit)
+	return saved, nil
+}
+
+func (s *gamerProfileService) updateProfile(ctx context.Context, studentNumber string, req *models.UpdateGamerProfileRequest) (*models.GamerProfile, error) {
+	before, err := s.repo.GetByStudentNumberForUpdate(ctx, studentNumber)
+	if err != nil {
+		return nil, err
+	}
+	if before == nil {
+		return nil, errors.NewNotFoundError("student", studentNumber)
+	}
+
+	profile := *before
+	if req.FirstName.Set {
+		if req.FirstName.Value == nil || *req.FirstName.Value == "" {
+			return nil, errors.NewValidationError("first_name", "is required")
+		}
+		profile.FirstName = *req.FirstName.Value
+	}
+
+	if req.LastName.Set {
+		if req.LastName.Value == nil || *req.LastName.Value == "" {
+			return nil, errors.NewValidationError("last_name", "is required")
+		}
+		profile.LastName = *req.LastName.Value
+	}
+
+	if req.MembershipTier.Set {
+		return nil, errors.NewValidationError("membership_tier", "can't be patched, renew the membership instead")
+	}
+
+	if req.Banned.Set {
+		return nil, errors.NewValidationError("banned", "can't be patched, issue or lift a ban instead")
+	}
+
+	if req.Notes.Set && req.Notes.Value != nil && strings.TrimSpace(*req.Notes.Value) != "" {
+		if _, err := validateNoteBody("notes", *req.Notes.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Email.Set {
+		profile.Email, _, err = normalizeContact(req.Email.Value, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if req.DiscordHandle.Set {
+		_, profile.DiscordHandle, err = normalizeContact(nil, req.DiscordHandle.Value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if req.MarketingConsent.Set {
+		if req.MarketingConsent.Value == nil {
+			return nil, errors.NewValidationError("marketing_consent", "must be true or false")
+		}
+		profile.MarketingConsent = *req.MarketingConsent.Value
+	}
+
+	if req.NotificationConsent.Set {
+		if req.NotificationConsent.Value == nil {
+			return nil, errors.NewValidationError("notification_consent", "must be true or false")
+		}
+		profile.NotificationConsent = *req.NotificationConsent.Value
+	}
+
+	saved, err := s.repo.Update(ctx, &profile)
+	if err != nil {
+		return nil, err
+	}
+	saved.Banned = currentBanned(before)
+
+	if req.Notes.Set {
+		saved.Notes, err = s.setProfileNotes(ctx, saved.StudentNumber, before.Notes, req.Notes.Value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.audit.Record(ctx, AuditProfileUpdate, "profile", saved.StudentNumber, before, saved)
+	return saved, nil
+}
+
+// RenewMembership records a new paid membership for an existing member,
+// starting now
+func (s *gamerProfileService) RenewMembership(ctx context.Context, studentNumber string, req *models.RenewMembershipRequest) (*models.Membership, error) {
+	if err := validateStudentNumber(studentNumber); err != nil {
+		return nil, err
+	}
+
+	if req.MembershipTier == 0 {
+		return nil, errors.NewValidationError("membership_tier", "must be a paid tier")
+	}
+	tier, err := models.NewMembershipTier(req.MembershipTier)
+	if err != nil {
+		return nil, errors.NewValidationError("membership_tier", err.Error())
+	}
+
+	if req.Source == models.MembershipSourceUnknown || !req.Source.IsValid() {
+		return nil, errors.NewValidationError("source", "must be showpass, cash or comp")
+	}
+
+	purchasedAt := time.Now()
+	if req.PurchasedAt != nil {
+		if req.PurchasedAt.After(purchasedAt) {
+			return nil, errors.NewValidationError("purchased_at", "can't be in the future")
+		}
+		purchasedAt = *req.PurchasedAt
+	}
+
+	profile, err := s.findProfile(ctx, studentNumber)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, errors.NewNotFoundError("student", studentNumber)
+	}
+
+	membership, err := s.recordMembership(ctx, studentNumber, tier, req.MembershipTier, req.Source, purchasedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditMembershipRenew, "membership", membership.ID, nil, membership)
+	return membership, nil
+}
+
+// ListMemberships returns a member's membership history, newest first
+func (s *gamerProfileService) ListMemberships(ctx context.Context, studentNumber string) ([]models.Membership, error) {
+	if err := validateStudentNumber(studentNumber); err != nil {
+		return nil, err
+	}
+
+	profile, err := s.findProfile(ctx, studentNumber)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, errors.NewNotFoundError("student", studentNumber)
+	}
+
+	return s.repo.ListMemberships(ctx, studentNumber)
+}
+
+// recordMembership adds a membership of tier starting now, recorded by the
+// app and exec making the request
+func (s *gamerProfileService) recordMembership(ctx context.Context, studentNumber string, tier models.MembershipTier, tierNumber int, source models.MembershipSource, purchasedAt time.Time) (*models.Membership, error) {
+	membership, err := newMembership(ctx, studentNumber, tier, tierNumber, source, purchasedAt)
+	if err != nil {
+		return nil, err
 	}
 
-	return s.repo.Upsert(ctx, profile)
+	return s.repo.AddMembership(ctx, membership)
 }
 
+// hasCurrentMembership reports whether profile holds an unexpired membership
+// of the given tier
+func hasCurrentMembership(profile *models.GamerProfile, tier int) bool {
+	if profile == nil || profile.MembershipTier != tier || profile.MembershipExpiryDate == nil {
+		return false
+	}
+	return profile.MembershipExpiryDate.After(time.Now())
+}
+
+// currentMembership returns the latest membership to have started, or nil if
+// there is none or it has run out
+func (s *gamerProfileService) currentMembership(ctx context.Context, studentNumber string) (*models.Membership, error) {
+	memberships, err := s.repo.ListMemberships(ctx, studentNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range memberships {
+		if memberships[i].StartsAt.After(now) {
+			continue
+		}
+		if memberships[i].ExpiresAt != nil && !memberships[i].ExpiresAt.After(now) {
+			return nil, nil
+		}
+		return &memberships[i], nil
+	}
+	return nil, nil
+}
+
+func newMembership(ctx context.Context, studentNumber string, tier models.MembershipTier, tierNumber int, source models.MembershipSource, purchasedAt time.Time) (*models.Membership, error) {
+	expiryDate, err := tier.GetExpiryDate()
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate expiry date: %w", err)
+	}
+
+	membership := &models.Membership{
+		StudentNumber:  studentNumber,
+		MembershipTier: tierNumber,
+		Source:         source,
+		PurchasedAt:    purchasedAt,
+		StartsAt:       time.Now(),
+		ExpiresAt:      expiryDate,
+	}
+	if appName := auth.AppNameFromContext(ctx); appName != "" {
+		membership.RecordedByApp = &appName
+	}
+	if exec := auth.ExecFromContext(ctx); exec != nil {
+		membership.RecordedByExecID = &exec.ID
+	}
+	return membership, nil
+}
+
+// ProvisionMembership records a purchase made outside the lounge, such as
+// through Showpass, registering the member if they're new. Existing members,
+// deleted or not, keep the details execs gave them, and a purchaser whose name
+// doesn't match theirs is a conflict. A purchase that was already recorded is
+// returned as it is, with created false.
+func (s *gamerProfileService) ProvisionMembership(ctx context.Context, req *models.ProvisionMembershipRequest) (*models.Membership, bool, error) {
+	if req.ExternalRef == "" {
+		return nil, false, errors.NewValidationError("external_ref", "is required")
+	}
+
+	registration := &models.CreateGamerProfileRequest{
+		StudentNumber:    req.StudentNumber,
+		FirstName:        req.FirstName,
+		LastName:         req.LastName,
+		MembershipTier:   req.MembershipTier,
+		MembershipSource: req.Source,
+	}
+	tier, _, err := validateProfileRequest(registration)
+	if err != nil {
+		return nil, false, err
+	}
+	if req.MembershipTier == 0 {
+		return nil, false, errors.NewValidationError("membership_tier", "must be a paid tier")
+	}
+	if req.Source == models.MembershipSourceUnknown {
+		return nil, false, errors.NewValidationError("source", "must be showpass, cash or comp")
+	}
+
+	var membership *models.Membership
+	created := false
+	audit := &deferredAudit{}
+	err = s.repo.WithTx(ctx, func(repo gamer.GamerProfileRepository) error {
+		existing, err := repo.GetMembershipByExternalRef(ctx, req.ExternalRef)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			membership = existing
+			return nil
+		}
+
+		// Deleted members are matched too, so a purchase can't bring their
+		// profile back or rename it
+		profile, err := repo.GetByStudentNumberIncludingDeleted(ctx, req.StudentNumber)
+		var notFoundErr *errors.NotFoundError
+		if err != nil && !goerrors.As(err, &notFoundErr) {
+			return err
+		}
+		if profile != nil && (!strings.EqualFold(profile.FirstName, req.FirstName) || !strings.EqualFold(profile.LastName, req.LastName)) {
+			return errors.NewConflictError(fmt.Sprintf("student number %s belongs to a member with another name", req.StudentNumber))
+		}
+		if profile == nil {
+			saved, err := repo.Upsert(ctx, &models.GamerProfile{
+				StudentNumber:       req.StudentNumber,
+				FirstName:           req.FirstName,
+				LastName:            req.LastName,
+				CreatedAt:           time.Now(),
+				NotificationConsent: true,
+			})
+			if err != nil {
+				return err
+			}
+			audit.Record(ctx, AuditProfileUpsert, "profile", saved.StudentNumber, nil, saved)
+		}
+
+		pending, err := newMembership(ctx, req.StudentNumber, tier, req.MembershipTier, req.Source, req.PurchasedAt)
+		if err != nil {
+			return err
+		}
+		pending.ExternalRef = &req.ExternalRef
+
+		membership, err = repo.AddMembership(ctx, pending)
+		if err != nil {
+			return err
+		}
+		audit.Record(ctx, AuditMembershipProvision, "membership", membership.ID, nil, membership)
+		created = true
+		return nil
+	})
+	var conflictErr *errors.ConflictError
+	if goerrors.As(err, &conflictErr) {
+		// A concurrent delivery of the same purchase may have recorded it
+		// between the lookup and the insert
+		if existing, getErr := s.repo.GetMembershipByExternalRef(ctx, req.ExternalRef); getErr == nil && existing != nil {
+			return existing, false, nil
+		}
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	audit.flush(ctx, s.audit)
+	return membership, created, nil
+}
+
+// DeleteProfile soft deletes a profile. It can be brought back with
+// RestoreProfile, or registered again.
 func (s *gamerProfileService) DeleteProfile(ctx context.Context, studentNumber string) error {
 	if err := validateStudentNumber(studentNumber); err != nil {
 		return err
 	}
 
-	return s.repo.Delete(ctx, studentNumber)
+	before, err := s.findProfile(ctx, studentNumber)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, studentNumber); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, AuditProfileDelete, "profile", studentNumber, before, nil)
+	return nil
+}
+
+func (s *gamerProfileService) RestoreProfile(ctx context.Context, studentNumber string) (*models.GamerProfile, error) {
+	if err := validateStudentNumber(studentNumber); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.Restore(ctx, studentNumber); err != nil {
+		return nil, err
+	}
+
+	restored, err := s.repo.GetByStudentNumber(ctx, studentNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditProfileRestore, "profile", studentNumber, nil, restored)
+	return restored, nil
+}
+
+// EraseProfile anonymizes a profile for good. Its sessions are kept under a
+// pseudonym so they still count towards statistics.
+func (s *gamerProfileService) EraseProfile(ctx context.Context, studentNumber string) error {
+	if err := validateStudentNumber(studentNumber); err != nil {
+		return err
+	}
+
+	pseudonym, err := s.repo.Erase(ctx, studentNumber)
+	if err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, AuditProfileErase, "profile", pseudonym, nil, nil)
+	return nil
+}
+
+// IssueBan bans a member, permanently or until req.EndsAt
+func (s *gamerProfileService) IssueBan(ctx context.Context, studentNumber string, req *models.IssueBanRequest) (*models.Ban, error) {
+	if err := validateStudentNumber(studentNumber); err != nil {
+		return nil, err
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, errors.NewValidationError("reason", "is required")
+	}
+
+	startsAt := time.Now()
+	if req.StartsAt != nil {
+		startsAt = *req.StartsAt
+	}
+	if req.EndsAt != nil && !req.EndsAt.After(startsAt) {
+		return nil, errors.NewValidationError("ends_at", "must be after starts_at")
+	}
+
+	profile, err := s.findProfile(ctx, studentNumber)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, errors.NewNotFoundError("student", studentNumber)
+	}
+
+	ban := &models.Ban{
+		StudentNumber: studentNumber,
+		Reason:        reason,
+		StartsAt:      startsAt,
+		EndsAt:        req.EndsAt,
+	}
+	if appName := auth.AppNameFromContext(ctx); appName != "" {
+		ban.IssuedByApp = &appName
+	}
+	if exec := auth.ExecFromContext(ctx); exec != nil {
+		ban.IssuedByExecID = &exec.ID
+	}
+
+	created, err := s.repo.CreateBan(ctx, ban)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditBanIssue, "ban", created.ID, nil, created)
+	return created, nil
+}
+
+// LiftBan ends a member's ban early. The ban stays in their history.
+func (s *gamerProfileService) LiftBan(ctx context.Context, studentNumber, banID string, req *models.LiftBanRequest) (*models.Ban, error) {
+	if err := validateStudentNumber(studentNumber); err != nil {
+		return nil, err
+	}
+
+	before, err := s.repo.GetBan(ctx, banID)
+	if err != nil {
+		return nil, err
+	}
+	if before.StudentNumber != studentNumber {
+		return nil, errors.NewNotFoundError("ban", banID)
+	}
+	if before.LiftedAt != nil {
+		return nil, errors.NewValidationError("ban", "is already lifted")
+	}
+
+	ban := *before
+	if reason := strings.TrimSpace(req.Reason); reason != "" {
+		ban.LiftReason = &reason
+	}
+	if appName := auth.AppNameFromContext(ctx); appName != "" {
+		ban.LiftedByApp = &appName
+	}
+	if exec := auth.ExecFromContext(ctx); exec != nil {
+		ban.LiftedByExecID = &exec.ID
+	}
+
+	lifted, err := s.repo.LiftBan(ctx, &ban)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditBanLift, "ban", lifted.ID, before, lifted)
+	return lifted, nil
+}
+
+// ListBans returns a member's ban history, newest first
+func (s *gamerProfileService) ListBans(ctx context.Context, studentNumber string) ([]models.Ban, error) {
+	if err := validateStudentNumber(studentNumber); err != nil {
+		return nil, err
+	}
+
+	profile, err := s.findProfile(ctx, studentNumber)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, errors.NewNotFoundError("student", studentNumber)
+	}
+
+	return s.repo.ListBans(ctx, studentNumber)
+}
+
+// currentBanned is the ban status to report after saving a profile, since
+// it comes from the ban records rather than the saved row
+func currentBanned(before *models.GamerProfile) *bool {
+	if before != nil && before.Banned != nil {
+		return before.Banned
+	}
+	banned := false
+	return &banned
+}
+
+// findProfile returns the stored profile, or nil if there isn't one
+func (s *gamerProfileService) findProfile(ctx context.Context, studentNumber string) (*models.GamerProfile, error) {
+	profile, err := s.repo.GetByStudentNumber(ctx, studentNumber)
+	var notFoundErr *errors.NotFoundError
+	if goerrors.As(err, &notFoundErr) {
+		return nil, nil
+	}
+	return profile, err
 }
 
 func validateStudentNumber(studentNumber string) error {
@@ -2,18 +2,30 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	goerrors "errors"
 	"fmt"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
+	"github.com/ubcesports/echo-base/internal/interfaces/gamer"
 	"github.com/ubcesports/echo-base/internal/models"
 )
 
 type mockGamerProfileRepository struct {
 	profiles map[string]*models.GamerProfile
+	memberships []models.Membership
+	bans []models.Ban
+	notes []models.MemberNote
+	deleted map[string]*models.GamerProfile
 	getErr   error
 	upsertErr error
 	deleteErr error
+	addMembershipErr error
 }
 
 func (m *mockGamerProfileRepository) GetByStudentNumber(ctx context.Context, studentNumber string) (*models.GamerProfile, error) {
@@ -27,10 +39,82 @@ func (m *mockGamerProfileRepository) GetByStudentNumber(ctx context.Context, stu
 	return profile, nil
 }
 
+func (m *mockGamerProfileRepository) GetByStudentNumberForUpdate(ctx context.Context, studentNumber string) (*models.GamerProfile, error) {
+	return m.GetByStudentNumber(ctx, studentNumber)
+}
+
+func (m *mockGamerProfileRepository) GetByStudentNumberIncludingDeleted(ctx context.Context, studentNumber string) (*models.GamerProfile, error) {
+	if profile, exists := m.deleted[studentNumber]; exists {
+		return profile, nil
+	}
+	return m.GetByStudentNumber(ctx, studentNumber)
+}
+
+func (m *mockGamerProfileRepository) GetByEmail(ctx context.Context, email string) (*models.GamerProfile, error) {
+	for _, profiles := range []map[string]*models.GamerProfile{m.profiles, m.deleted} {
+		for _, p := range profiles {
+			if p.Email != nil && strings.EqualFold(*p.Email, email) {
+				return p, nil
+			}
+		}
+	}
+	return nil, errors.NewNotFoundError("profile", email)
+}
+
+func (m *mockGamerProfileRepository) List(ctx context.Context, filter models.ProfileFilter) ([]models.GamerProfile, int, error) {
+	matches := m.filter(filter)
+	start := min((filter.Page-1)*filter.Limit, len(matches))
+	end := min(start+filter.Limit, len(matches))
+	return matches[start:end], len(matches), nil
+}
+
+// filter applies the tier, banned and search filters, sorted like the query
+func (m *mockGamerProfileRepository) filter(filter models.ProfileFilter) []models.GamerProfile {
+	var matches []models.GamerProfile
+	for _, p := range m.profiles {
+		if filter.MembershipTier != nil && p.MembershipTier != *filter.MembershipTier {
+			continue
+		}
+		if filter.Banned != nil && (p.Banned != nil && *p.Banned) != *filter.Banned {
+			continue
+		}
+		name := strings.ToLower(p.FirstName + " " + p.LastName)
+		if p.Email != nil {
+			name += " " + *p.Email
+		}
+		if p.DiscordHandle != nil {
+			name += " " + *p.DiscordHandle
+		}
+		if filter.Search != "" && !strings.Contains(name, strings.ToLower(filter.Search)) && !strings.Contains(p.StudentNumber, filter.Search) {
+			continue
+		}
+		matches = append(matches, *p)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].LastName+matches[i].FirstName < matches[j].LastName+matches[j].FirstName
+	})
+	return matches
+}
+
 func (m *mockGamerProfileRepository) Upsert(ctx context.Context, profile *models.GamerProfile) (*models.GamerProfile, error) {
 	if m.upsertErr != nil {
 		return nil, m.upsertErr
 	}
+	// Notes come from the notes timeline rather than the profile
+	profile.Notes = nil
+	if existing, exists := m.profiles[profile.StudentNumber]; exists {
+		profile.Notes = existing.Notes
+	}
+	m.profiles[profile.StudentNumber] = profile
+	return profile, nil
+}
+
+func (m *mockGamerProfileRepository) Update(ctx context.Context, profile *models.GamerProfile) (*models.GamerProfile, error) {
+	existing, exists := m.profiles[profile.StudentNumber]
+	if !exists {
+		return nil, fmt.Errorf("student %s not found", profile.StudentNumber)
+	}
+	profile.Notes = existing.Notes
 	m.profiles[profile.StudentNumber] = profile
 	return profile, nil
 }
@@ -39,10 +123,40 @@ func (m *mockGamerProfileRepository) Delete(ctx context.Context, studentNumber s
 	if m.deleteErr != nil {
 		return m.deleteErr
 	}
+	if profile, exists := m.profiles[studentNumber]; exists {
+		if m.deleted == nil {
+			m.deleted = make(map[string]*models.GamerProfile)
+		}
+		deletedAt := time.Now()
+		profile.DeletedAt = &deletedAt
+		m.deleted[studentNumber] = profile
+	}
 	delete(m.profiles, studentNumber)
 	return nil
 }
 
+func (m *mockGamerProfileRepository) Restore(ctx context.Context, studentNumber string) (*models.GamerProfile, error) {
+	profile, exists := m.deleted[studentNumber]
+	if !exists {
+		return nil, errors.NewNotFoundError("deleted student", studentNumber)
+	}
+	delete(m.deleted, studentNumber)
+	profile.DeletedAt = nil
+	m.profiles[studentNumber] = profile
+	return profile, nil
+}
+
+func (m *mockGamerProfileRepository) Erase(ctx context.Context, studentNumber string) (string, error) {
+	_, active := m.profiles[studentNumber]
+	_, deleted := m.deleted[studentNumber]
+	if !active && !deleted {
+		return "", errors.NewNotFoundError("student", studentNumber)
+	}
+	delete(m.profiles, studentNumber)
+	delete(m.deleted, studentNumber)
+	return "E0000001", nil
+}
+
 func (m *mockGamerProfileRepository) CheckMembershipValidity(ctx context.Context, studentNumber string) (tier int, expiryDate *time.Time, err error) {
 	profile, exists := m.profiles[studentNumber]
 	if !exists {
@@ -51,6 +165,92 @@ func (m *mockGamerProfileRepository) CheckMembershipValidity(ctx context.Context
 	return profile.MembershipTier, profile.MembershipExpiryDate, nil
 }
 
+func (m *mockGamerProfileRepository) AddMembership(ctx context.Context, membership *models.Membership) (*models.Membership, error) {
+	if m.addMembershipErr != nil {
+		return nil, m.addMembershipErr
+	}
+	profile, exists := m.profiles[membership.StudentNumber]
+	if !exists {
+		profile, exists = m.deleted[membership.StudentNumber]
+	}
+	if !exists {
+		return nil, fmt.Errorf("student %s not found", membership.StudentNumber)
+	}
+	membership.ID = fmt.Sprintf("membership-%d", len(m.memberships)+1)
+	m.memberships = append(m.memberships, *membership)
+	profile.MembershipTier = membership.MembershipTier
+	profile.MembershipExpiryDate = membership.ExpiresAt
+	return membership, nil
+}
+
+func (m *mockGamerProfileRepository) GetMembershipByExternalRef(ctx context.Context, externalRef string) (*models.Membership, error) {
+	for i := range m.memberships {
+		if ref := m.memberships[i].ExternalRef; ref != nil && *ref == externalRef {
+			return &m.memberships[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockGamerProfileRepository) ListMemberships(ctx context.Context, studentNumber string) ([]models.Membership, error) {
+	var memberships []models.Membership
+	for i := len(m.memberships) - 1; i >= 0; i-- {
+		if m.memberships[i].StudentNumber == studentNumber {
+			memberships = append(memberships, m.memberships[i])
+		}
+	}
+	return memberships, nil
+}
+
+func (m *mockGamerProfileRepository) CreateBan(ctx context.Context, ban *models.Ban) (*models.Ban, error) {
+	ban.ID = fmt.Sprintf("ban-%d", len(m.bans)+1)
+	ban.CreatedAt = time.Now()
+	m.bans = append(m.bans, *ban)
+	return ban, nil
+}
+
+func (m *mockGamerProfileRepository) GetBan(ctx context.Context, banID string) (*models.Ban, error) {
+	for i := range m.bans {
+		if m.bans[i].ID == banID {
+			ban := m.bans[i]
+			return &ban, nil
+		}
+	}
+	return nil, errors.NewNotFoundError("ban", banID)
+}
+
+func (m *mockGamerProfileRepository) GetActiveBan(ctx context.Context, studentNumber string) (*models.Ban, error) {
+	for i := range m.bans {
+		if m.bans[i].StudentNumber == studentNumber && m.bans[i].IsActive(time.Now()) {
+			ban := m.bans[i]
+			return &ban, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockGamerProfileRepository) ListBans(ctx context.Context, studentNumber string) ([]models.Ban, error) {
+	var bans []models.Ban
+	for i := len(m.bans) - 1; i >= 0; i-- {
+		if m.bans[i].StudentNumber == studentNumber {
+			bans = append(bans, m.bans[i])
+		}
+	}
+	return bans, nil
+}
+
+func (m *mockGamerProfileRepository) LiftBan(ctx context.Context, ban *models.Ban) (*models.Ban, error) {
+	for i := range m.bans {
+		if m.bans[i].ID == ban.ID && m.bans[i].LiftedAt == nil {
+			now := time.Now()
+			ban.LiftedAt = &now
+			m.bans[i] = *ban
+			return ban, nil
+		}
+	}
+	return nil, errors.NewNotFoundError("ban", ban.ID)
+}
+
 func TestCreateOrUpdateProfile(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -140,7 +340,7 @@ func TestCreateOrUpdateProfile(t *testing.T) {
 			mockRepo := &mockGamerProfileRepository{
 				profiles: make(map[string]*models.GamerProfile),
 			}
-			service := NewGamerProfileService(mockRepo)
+			service := NewGamerProfileService(mockRepo, &mockAuditRecorder{})
 
 			profile, err := service.CreateOrUpdateProfile(context.Background(), tt.req)
 
@@ -182,7 +382,7 @@ func TestGetProfile(t *testing.T) {
 			},
 		},
 	}
-	service := NewGamerProfileService(mockRepo)
+	service := NewGamerProfileService(mockRepo, &mockAuditRecorder{})
 
 	tests := []struct {
 		name          string
@@ -212,7 +412,7 @@ func TestDeleteProfile(t *testing.T) {
 			},
 		},
 	}
-	service := NewGamerProfileService(mockRepo)
+	service := NewGamerProfileService(mockRepo, &mockAuditRecorder{})
 
 	tests := []struct {
 		name          string
@@ -232,3 +432,703 @@ func TestDeleteProfile(t *testing.T) {
 		})
 	}
 }
+
+func TestProfileChangesAreAudited(t *testing.T) {
+	mockRepo := &mockGamerProfileRepository{
+		profiles: make(map[string]*models.GamerProfile),
+	}
+	recorder := &mockAuditRecorder{}
+	service := NewGamerProfileService(mockRepo, recorder)
+	ctx := context.Background()
+
+	req := &models.CreateGamerProfileRequest{
+		StudentNumber:  "12345678",
+		FirstName:      "John",
+		LastName:       "Doe",
+		MembershipTier: 1,
+	}
+	if _, err := service.CreateOrUpdateProfile(ctx, req); err != nil {
+		t.Fatalf("CreateOrUpdateProfile() error = %v", err)
+	}
+
+	req.MembershipTier = 2
+	if _, err := service.CreateOrUpdateProfile(ctx, req); err != nil {
+		t.Fatalf("CreateOrUpdateProfile() error = %v", err)
+	}
+
+	if err := service.DeleteProfile(ctx, "12345678"); err != nil {
+		t.Fatalf("DeleteProfile() error = %v", err)
+	}
+
+	if len(recorder.entries) != 3 {
+		t.Fatalf("expected 3 audit entries, got %d", len(recorder.entries))
+	}
+	if string(recorder.entries[0].Before) != "null" {
+		t.Errorf("create should have no before state, got %s", recorder.entries[0].Before)
+	}
+	if string(recorder.entries[1].Before) == "null" || string(recorder.entries[1].After) == "null" {
+		t.Errorf("update should record before and after state")
+	}
+	if recorder.entries[2].Action != AuditProfileDelete || string(recorder.entries[2].After) != "null" {
+		t.Errorf("unexpected delete entry %+v", recorder.entries[2])
+	}
+}
+
+func TestListProfiles(t *testing.T) {
+	banned := true
+	mockRepo := &mockGamerProfileRepository{
+		profiles: map[string]*models.GamerProfile{
+			"11111111": {StudentNumber: "11111111", FirstName: "Ada", LastName: "Lovelace", MembershipTier: 1},
+			"22222222": {StudentNumber: "22222222", FirstName: "Alan", LastName: "Turing", MembershipTier: 2},
+			"33333333": {StudentNumber: "33333333", FirstName: "Grace", LastName: "Hopper", MembershipTier: 1, Banned: &banned},
+		},
+	}
+	service := NewGamerProfileService(mockRepo, &mockAuditRecorder{})
+	ctx := context.Background()
+
+	page, err := service.ListProfiles(ctx, models.ProfileFilter{Page: 1, Limit: 2})
+	if err != nil {
+		t.Fatalf("ListProfiles() error = %v", err)
+	}
+	if page.Total != 3 || len(page.Profiles) != 2 || page.Profiles[0].LastName != "Hopper" {
+		t.Errorf("unexpected first page %+v", page)
+	}
+
+	page, err = service.ListProfiles(ctx, models.ProfileFilter{Page: 2, Limit: 2})
+	if err != nil {
+		t.Fatalf("ListProfiles() error = %v", err)
+	}
+	if page.Total != 3 || len(page.Profiles) != 1 || page.Profiles[0].LastName != "Turing" {
+		t.Errorf("unexpected second page %+v", page)
+	}
+
+	tier := 1
+	notBanned := false
+	page, err = service.ListProfiles(ctx, models.ProfileFilter{MembershipTier: &tier, Banned: &notBanned, Page: 1, Limit: 20})
+	if err != nil {
+		t.Fatalf("ListProfiles() error = %v", err)
+	}
+	if page.Total != 1 || page.Profiles[0].StudentNumber != "11111111" {
+		t.Errorf("expected only Ada, got %+v", page)
+	}
+
+	page, err = service.ListProfiles(ctx, models.ProfileFilter{Search: "  alan tur ", Page: 1, Limit: 20})
+	if err != nil {
+		t.Fatalf("ListProfiles() error = %v", err)
+	}
+	if page.Total != 1 || page.Profiles[0].StudentNumber != "22222222" {
+		t.Errorf("expected only Alan, got %+v", page)
+	}
+
+	now := time.Now()
+	invalidTier := 7
+	invalid := []models.ProfileFilter{
+		{Page: 0, Limit: 20},
+		{Page: 1, Limit: 0},
+		{Page: 1, Limit: MaxProfilesPerPage + 1},
+		{Page: 1, Limit: 20, MembershipTier: &invalidTier},
+		{Page: 1, Limit: 20, CreatedFrom: &now, CreatedTo: &now},
+	}
+	for _, filter := range invalid {
+		if _, err := service.ListProfiles(ctx, filter); err == nil {
+			t.Errorf("ListProfiles(%+v) expected an error", filter)
+		}
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	joined := time.Date(2024, time.September, 5, 0, 0, 0, 0, time.UTC)
+	expiry := time.Date(2025, time.May, 1, 0, 0, 0, 0, time.UTC)
+	notes := "prefers PC 3"
+	newProfile := func() *mockGamerProfileRepository {
+		return &mockGamerProfileRepository{
+			profiles: map[string]*models.GamerProfile{
+				"12345678": {
+					StudentNumber:        "12345678",
+					FirstName:            "John",
+					LastName:             "Doe",
+					MembershipTier:       1,
+					Notes:                &notes,
+					CreatedAt:            joined,
+					MembershipExpiryDate: &expiry,
+				},
+			},
+		}
+	}
+	patch := func(body string) *models.UpdateGamerProfileRequest {
+		var req models.UpdateGamerProfileRequest
+		if err := json.Unmarshal([]byte(body), &req); err != nil {
+			t.Fatalf("failed to decode patch: %v", err)
+		}
+		return &req
+	}
+
+	t.Run("only touches provided fields", func(t *testing.T) {
+		recorder := &mockAuditRecorder{}
+		service := NewGamerProfileService(newProfile(), recorder)
+
+		profile, err := service.UpdateProfile(context.Background(), "12345678", patch(`{"last_name": "Dough"}`))
+		if err != nil {
+			t.Fatalf("UpdateProfile() error = %v", err)
+		}
+		if profile.LastName != "Dough" || profile.FirstName != "John" {
+			t.Errorf("unexpected name %s %s", profile.FirstName, profile.LastName)
+		}
+		if !profile.CreatedAt.Equal(joined) {
+			t.Errorf("created_at changed to %v", profile.CreatedAt)
+		}
+		if !profile.MembershipExpiryDate.Equal(expiry) {
+			t.Errorf("expiry changed to %v", profile.MembershipExpiryDate)
+		}
+		if profile.Notes == nil || *profile.Notes != notes {
+			t.Errorf("notes changed to %v", profile.Notes)
+		}
+		if len(recorder.entries) != 1 || recorder.entries[0].Action != AuditProfileUpdate {
+			t.Errorf("expected one update audit entry, got %+v", recorder.entries)
+		}
+	})
+
+	t.Run("null clears a field", func(t *testing.T) {
+		service := NewGamerProfileService(newProfile(), &mockAuditRecorder{})
+
+		profile, err := service.UpdateProfile(context.Background(), "12345678", patch(`{"notes": null}`))
+		if err != nil {
+			t.Fatalf("UpdateProfile() error = %v", err)
+		}
+		if profile.Notes != nil {
+			t.Errorf("expected notes to be cleared, got %q", *profile.Notes)
+		}
+	})
+
+	t.Run("membership can't be patched", func(t *testing.T) {
+		service := NewGamerProfileService(newProfile(), &mockAuditRecorder{})
+
+		_, err := service.UpdateProfile(context.Background(), "12345678", patch(`{"membership_tier": 2}`))
+		var validationErr *errors.ValidationError
+		if !goerrors.As(err, &validationErr) || validationErr.Field != "membership_tier" {
+			t.Errorf("expected a membership_tier ValidationError, got %v", err)
+		}
+	})
+
+	invalid := []string{
+		`{"first_name": ""}`,
+		`{"first_name": null}`,
+		`{"last_name": null}`,
+		`{"membership_tier": null}`,
+		`{"membership_tier": 9}`,
+		`{"email": "not an email"}`,
+		`{"discord_handle": "a"}`,
+		`{"marketing_consent": null}`,
+	}
+	for _, body := range invalid {
+		t.Run("rejects "+body, func(t *testing.T) {
+			service := NewGamerProfileService(newProfile(), &mockAuditRecorder{})
+
+			if _, err := service.UpdateProfile(context.Background(), "12345678", patch(body)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+
+	t.Run("missing profile", func(t *testing.T) {
+		service := NewGamerProfileService(newProfile(), &mockAuditRecorder{})
+
+		_, err := service.UpdateProfile(context.Background(), "87654321", patch(`{"last_name": "Dough"}`))
+		var notFoundErr *errors.NotFoundError
+		if !goerrors.As(err, &notFoundErr) {
+			t.Errorf("expected NotFoundError, got %v", err)
+		}
+	})
+}
+
+func TestRenewMembership(t *testing.T) {
+	expired := time.Now().AddDate(0, -1, 0)
+	mockRepo := &mockGamerProfileRepository{
+		profiles: map[string]*models.GamerProfile{
+			"12345678": {StudentNumber: "12345678", FirstName: "John", LastName: "Doe", MembershipTier: 1, MembershipExpiryDate: &expired},
+		},
+	}
+	recorder := &mockAuditRecorder{}
+	service := NewGamerProfileService(mockRepo, recorder)
+	ctx := auth.WithExec(context.Background(), &auth.ExecIdentity{ID: "exec-1", Name: "Exec One"})
+
+	purchased := time.Now().Add(-time.Hour)
+	membership, err := service.RenewMembership(ctx, "12345678", &models.RenewMembershipRequest{
+		MembershipTier: 2,
+		Source:         models.MembershipSourceCash,
+		PurchasedAt:    &purchased,
+	})
+	if err != nil {
+		t.Fatalf("RenewMembership() error = %v", err)
+	}
+	if membership.MembershipTier != 2 || membership.Source != models.MembershipSourceCash || !membership.PurchasedAt.Equal(purchased) {
+		t.Errorf("unexpected membership %+v", membership)
+	}
+	if membership.ExpiresAt == nil || !membership.ExpiresAt.After(time.Now()) {
+		t.Errorf("expected an expiry in the future, got %v", membership.ExpiresAt)
+	}
+	if membership.RecordedByExecID == nil || *membership.RecordedByExecID != "exec-1" {
+		t.Errorf("expected the exec to be recorded, got %v", membership.RecordedByExecID)
+	}
+
+	profile := mockRepo.profiles["12345678"]
+	if profile.MembershipTier != 2 || !profile.MembershipExpiryDate.Equal(*membership.ExpiresAt) {
+		t.Errorf("profile wasn't moved to the new membership: %+v", profile)
+	}
+	if len(recorder.entries) != 1 || recorder.entries[0].Action != AuditMembershipRenew {
+		t.Errorf("expected one renewal audit entry, got %+v", recorder.entries)
+	}
+
+	history, err := service.ListMemberships(ctx, "12345678")
+	if err != nil {
+		t.Fatalf("ListMemberships() error = %v", err)
+	}
+	if len(history) != 1 || history[0].ID != membership.ID {
+		t.Errorf("unexpected history %+v", history)
+	}
+
+	future := time.Now().Add(time.Hour)
+	invalid := []*models.RenewMembershipRequest{
+		{MembershipTier: 0, Source: models.MembershipSourceCash},
+		{MembershipTier: 9, Source: models.MembershipSourceCash},
+		{MembershipTier: 1, Source: ""},
+		{MembershipTier: 1, Source: models.MembershipSourceUnknown},
+		{MembershipTier: 1, Source: models.MembershipSourceCash, PurchasedAt: &future},
+	}
+	for _, req := range invalid {
+		if _, err := service.RenewMembership(ctx, "12345678", req); err == nil {
+			t.Errorf("RenewMembership(%+v) expected an error", req)
+		}
+	}
+
+	_, err = service.RenewMembership(ctx, "87654321", &models.RenewMembershipRequest{MembershipTier: 1, Source: models.MembershipSourceComp})
+	var notFoundErr *errors.NotFoundError
+	if !goerrors.As(err, &notFoundErr) {
+		t.Errorf("expected NotFoundError, got %v", err)
+	}
+}
+
+func TestRegistrationRecordsMembership(t *testing.T) {
+	mockRepo := &mockGamerProfileRepository{
+		profiles: make(map[string]*models.GamerProfile),
+	}
+	service := NewGamerProfileService(mockRepo, &mockAuditRecorder{})
+	ctx := context.Background()
+
+	req := &models.CreateGamerProfileRequest{
+		StudentNumber:    "12345678",
+		FirstName:        "John",
+		LastName:         "Doe",
+		MembershipTier:   1,
+		MembershipSource: models.MembershipSourceShowpass,
+	}
+	if _, err := service.CreateOrUpdateProfile(ctx, req); err != nil {
+		t.Fatalf("CreateOrUpdateProfile() error = %v", err)
+	}
+
+	// Registering again on the current tier isn't a renewal
+	if _, err := service.CreateOrUpdateProfile(ctx, req); err != nil {
+		t.Fatalf("CreateOrUpdateProfile() error = %v", err)
+	}
+
+	req.MembershipTier = 0
+	if _, err := service.CreateOrUpdateProfile(ctx, req); err != nil {
+		t.Fatalf("CreateOrUpdateProfile() error = %v", err)
+	}
+
+	if len(mockRepo.memberships) != 1 {
+		t.Fatalf("expected only the first paid registration to record a membership, got %d", len(mockRepo.memberships))
+	}
+	if m := mockRepo.memberships[0]; m.MembershipTier != 1 || m.Source != models.MembershipSourceShowpass {
+		t.Errorf("unexpected membership %+v", m)
+	}
+
+	req.MembershipTier = 2
+	if _, err := service.CreateOrUpdateProfile(ctx, req); err != nil {
+		t.Fatalf("CreateOrUpdateProfile() error = %v", err)
+	}
+	if len(mockRepo.memberships) != 2 || mockRepo.memberships[1].MembershipTier != 2 {
+		t.Errorf("expected a tier change to record a membership, got %+v", mockRepo.memberships)
+	}
+
+	req.MembershipSource = "venmo"
+	if _, err := service.CreateOrUpdateProfile(ctx, req); err == nil {
+		t.Error("expected an invalid membership_source to be rejected")
+	}
+}
+
+func TestRegistrationRollsBackWithoutMembership(t *testing.T) {
+	mockRepo := &mockGamerProfileRepository{
+		profiles:         make(map[string]*models.GamerProfile),
+		addMembershipErr: fmt.Errorf("database is down"),
+	}
+	recorder := &mockAuditRecorder{}
+	service := NewGamerProfileService(mockRepo, recorder)
+
+	req := &models.CreateGamerProfileRequest{
+		StudentNumber:  "12345678",
+		FirstName:      "John",
+		LastName:       "Doe",
+		MembershipTier: 1,
+	}
+	if _, err := service.CreateOrUpdateProfile(context.Background(), req); err == nil {
+		t.Fatal("expected the failed membership to fail the registration")
+	}
+
+	if _, exists := mockRepo.profiles["12345678"]; exists {
+		t.Error("expected the profile to be rolled back")
+	}
+	if len(recorder.entries) != 0 {
+		t.Errorf("expected nothing to be audited, got %d entries", len(recorder.entries))
+	}
+}
+
+func TestRegistrationRejectsDeletedMember(t *testing.T) {
+	mockRepo := &mockGamerProfileRepository{profiles: make(map[string]*models.GamerProfile)}
+	recorder := &mockAuditRecorder{}
+	service := NewGamerProfileService(mockRepo, recorder)
+	ctx := context.Background()
+
+	email := "john@example.com"
+	req := &models.CreateGamerProfileRequest{
+		StudentNumber:  "12345678",
+		FirstName:      "John",
+		LastName:       "Doe",
+		MembershipTier: 1,
+		Email:          &email,
+	}
+	if _, err := service.CreateOrUpdateProfile(ctx, req); err != nil {
+		t.Fatalf("CreateOrUpdateProfile() error = %v", err)
+	}
+	if err := service.DeleteProfile(ctx, "12345678"); err != nil {
+		t.Fatalf("DeleteProfile() error = %v", err)
+	}
+	entries := len(recorder.entries)
+
+	req.Email = nil
+	req.MembershipTier = 2
+	_, err := service.CreateOrUpdateProfile(ctx, req)
+	var conflictErr *errors.ConflictError
+	if !goerrors.As(err, &conflictErr) || !strings.Contains(err.Error(), "/restore") {
+		t.Fatalf("expected a ConflictError pointing to restore, got %v", err)
+	}
+
+	deleted, exists := mockRepo.deleted["12345678"]
+	if !exists || deleted.DeletedAt == nil {
+		t.Fatal("expected the member to stay deleted")
+	}
+	if deleted.Email == nil || *deleted.Email != email {
+		t.Errorf("expected the stored email to be kept, got %v", deleted.Email)
+	}
+	if len(mockRepo.memberships) != 1 {
+		t.Errorf("expected no new membership, got %d", len(mockRepo.memberships))
+	}
+	if len(recorder.entries) != entries {
+		t.Errorf("expected nothing to be audited, got %d new entries", len(recorder.entries)-entries)
+	}
+}
+
+func TestBans(t *testing.T) {
+	mockRepo := &mockGamerProfileRepository{
+		profiles: map[string]*models.GamerProfile{
+			"12345678": {StudentNumber: "12345678", FirstName: "John", LastName: "Doe"},
+		},
+	}
+	recorder := &mockAuditRecorder{}
+	service := NewGamerProfileService(mockRepo, recorder)
+	ctx := auth.WithExec(context.Background(), &auth.ExecIdentity{ID: "exec-1", Name: "Exec One"})
+
+	ends := time.Now().Add(7 * 24 * time.Hour)
+	ban, err := service.IssueBan(ctx, "12345678", &models.IssueBanRequest{Reason: "  Unplugged a PC  ", EndsAt: &ends})
+	if err != nil {
+		t.Fatalf("IssueBan() error = %v", err)
+	}
+	if ban.Reason != "Unplugged a PC" || !ban.IsActive(time.Now()) {
+		t.Errorf("unexpected ban %+v", ban)
+	}
+	if ban.IssuedByExecID == nil || *ban.IssuedByExecID != "exec-1" {
+		t.Errorf("expected the issuing exec to be recorded, got %v", ban.IssuedByExecID)
+	}
+
+	if _, err := service.LiftBan(ctx, "87654321", ban.ID, &models.LiftBanRequest{}); err == nil {
+		t.Error("expected lifting another student's ban to fail")
+	}
+
+	lifted, err := service.LiftBan(ctx, "12345678", ban.ID, &models.LiftBanRequest{Reason: "Apologised"})
+	if err != nil {
+		t.Fatalf("LiftBan() error = %v", err)
+	}
+	if lifted.LiftedAt == nil || lifted.LiftReason == nil || *lifted.LiftReason != "Apologised" || lifted.IsActive(time.Now()) {
+		t.Errorf("unexpected lifted ban %+v", lifted)
+	}
+
+	var validationErr *errors.ValidationError
+	if _, err := service.LiftBan(ctx, "12345678", ban.ID, &models.LiftBanRequest{}); !goerrors.As(err, &validationErr) {
+		t.Errorf("expected lifting twice to be a ValidationError, got %v", err)
+	}
+
+	history, err := service.ListBans(ctx, "12345678")
+	if err != nil {
+		t.Fatalf("ListBans() error = %v", err)
+	}
+	if len(history) != 1 || history[0].LiftedAt == nil {
+		t.Errorf("expected the lifted ban in the history, got %+v", history)
+	}
+
+	if len(recorder.entries) != 2 || recorder.entries[0].Action != AuditBanIssue || recorder.entries[1].Action != AuditBanLift {
+		t.Errorf("unexpected audit entries %+v", recorder.entries)
+	}
+
+	past := time.Now().Add(-time.Hour)
+	invalid := []*models.IssueBanRequest{
+		{Reason: " "},
+		{Reason: "Spam", EndsAt: &past},
+	}
+	for _, req := range invalid {
+		if _, err := service.IssueBan(ctx, "12345678", req); err == nil {
+			t.Errorf("IssueBan(%+v) expected an error", req)
+		}
+	}
+
+	if _, err := service.IssueBan(ctx, "87654321", &models.IssueBanRequest{Reason: "Spam"}); err == nil {
+		t.Error("expected banning an unknown student to fail")
+	}
+}
+
+func TestBannedCantBeSetOnProfile(t *testing.T) {
+	mockRepo := &mockGamerProfileRepository{
+		profiles: map[string]*models.GamerProfile{
+			"12345678": {StudentNumber: "12345678", FirstName: "John", LastName: "Doe"},
+		},
+	}
+	service := NewGamerProfileService(mockRepo, &mockAuditRecorder{})
+	ctx := context.Background()
+
+	banned := true
+	_, err := service.CreateOrUpdateProfile(ctx, &models.CreateGamerProfileRequest{
+		StudentNumber: "12345678",
+		FirstName:     "John",
+		LastName:      "Doe",
+		Banned:        &banned,
+	})
+	if err == nil {
+		t.Error("expected registering with banned set to fail")
+	}
+
+	var req models.UpdateGamerProfileRequest
+	if err := json.Unmarshal([]byte(`{"banned": true}`), &req); err != nil {
+		t.Fatalf("failed to decode patch: %v", err)
+	}
+	if _, err := service.UpdateProfile(ctx, "12345678", &req); err == nil {
+		t.Error("expected patching banned to fail")
+	}
+}
+
+func TestProfileContact(t *testing.T) {
+	mockRepo := &mockGamerProfileRepository{profiles: make(map[string]*models.GamerProfile)}
+	service := NewGamerProfileService(mockRepo, &mockAuditRecorder{})
+	ctx := context.Background()
+
+	email := "  Alan.Turing@Example.com "
+	handle := "@Alan_T"
+	profile, err := service.CreateOrUpdateProfile(ctx, &models.CreateGamerProfileRequest{
+		StudentNumber:  "12345678",
+		FirstName:      "Alan",
+		LastName:       "Turing",
+		MembershipTier: 1,
+		Email:          &email,
+		DiscordHandle:  &handle,
+	})
+	if err != nil {
+		t.Fatalf("CreateOrUpdateProfile() error = %v", err)
+	}
+	if profile.Email == nil || *profile.Email != "alan.turing@example.com" {
+		t.Errorf("expected normalized email, got %v", profile.Email)
+	}
+	if profile.DiscordHandle == nil || *profile.DiscordHandle != "alan_t" {
+		t.Errorf("expected normalized discord handle, got %v", profile.DiscordHandle)
+	}
+	if profile.MarketingConsent || !profile.NotificationConsent {
+		t.Errorf("expected no marketing and notifications by default, got %t and %t", profile.MarketingConsent, profile.NotificationConsent)
+	}
+
+	var req models.UpdateGamerProfileRequest
+	if err := json.Unmarshal([]byte(`{"marketing_consent": true, "notification_consent": false, "discord_handle": null}`), &req); err != nil {
+		t.Fatalf("failed to decode patch: %v", err)
+	}
+	profile, err = service.UpdateProfile(ctx, "12345678", &req)
+	if err != nil {
+		t.Fatalf("UpdateProfile() error = %v", err)
+	}
+	if !profile.MarketingConsent || profile.NotificationConsent || profile.DiscordHandle != nil {
+		t.Errorf("patch not applied: %+v", profile)
+	}
+
+	// Renewing without consents keeps the ones the member chose
+	profile, err = service.CreateOrUpdateProfile(ctx, &models.CreateGamerProfileRequest{
+		StudentNumber:  "12345678",
+		FirstName:      "Alan",
+		LastName:       "Turing",
+		MembershipTier: 2,
+	})
+	if err != nil {
+		t.Fatalf("CreateOrUpdateProfile() error = %v", err)
+	}
+	if !profile.MarketingConsent || profile.NotificationConsent {
+		t.Errorf("expected consents to be kept, got %t and %t", profile.MarketingConsent, profile.NotificationConsent)
+	}
+
+	page, err := service.ListProfiles(ctx, models.ProfileFilter{Search: "turing@example", Page: 1, Limit: 20})
+	if err != nil {
+		t.Fatalf("ListProfiles() error = %v", err)
+	}
+	if page.Total != 1 {
+		t.Errorf("expected search by email to find the member, got %d", page.Total)
+	}
+
+	str := func(s string) *string { return &s }
+	invalid := []struct {
+		field string
+		req   models.CreateGamerProfileRequest
+	}{
+		{"email", models.CreateGamerProfileRequest{Email: str("alan@")}},
+		{"email", models.CreateGamerProfileRequest{Email: str("Alan <alan@example.com>")}},
+		{"discord_handle", models.CreateGamerProfileRequest{DiscordHandle: str("x")}},
+		{"discord_handle", models.CreateGamerProfileRequest{DiscordHandle: str("alan..turing")}},
+		{"discord_handle", models.CreateGamerProfileRequest{DiscordHandle: str("alan turing")}},
+	}
+	for _, tt := range invalid {
+		req := tt.req
+		req.StudentNumber = "87654321"
+		req.FirstName = "Grace"
+		req.LastName = "Hopper"
+		req.MembershipTier = 1
+
+		_, err := service.CreateOrUpdateProfile(ctx, &req)
+		var validationErr *errors.ValidationError
+		if !goerrors.As(err, &validationErr) || validationErr.Field != tt.field {
+			t.Errorf("expected a %s ValidationError, got %v", tt.field, err)
+		}
+	}
+}
+
+func TestRestoreAndEraseProfile(t *testing.T) {
+	mockRepo := &mockGamerProfileRepository{
+		profiles: map[string]*models.GamerProfile{
+			"12345678": {StudentNumber: "12345678", FirstName: "John", LastName: "Doe"},
+			"87654321": {StudentNumber: "87654321", FirstName: "Jane", LastName: "Roe"},
+		},
+	}
+	recorder := &mockAuditRecorder{}
+	service := NewGamerProfileService(mockRepo, recorder)
+	ctx := context.Background()
+
+	if err := service.DeleteProfile(ctx, "12345678"); err != nil {
+		t.Fatalf("DeleteProfile() error = %v", err)
+	}
+
+	restored, err := service.RestoreProfile(ctx, "12345678")
+	if err != nil {
+		t.Fatalf("RestoreProfile() error = %v", err)
+	}
+	if restored.FirstName != "John" {
+		t.Errorf("unexpected restored profile %+v", restored)
+	}
+
+	var notFoundErr *errors.NotFoundError
+	if _, err := service.RestoreProfile(ctx, "12345678"); !goerrors.As(err, &notFoundErr) {
+		t.Errorf("expected restoring an active profile to be NotFound, got %v", err)
+	}
+
+	if err := service.EraseProfile(ctx, "87654321"); err != nil {
+		t.Fatalf("EraseProfile() error = %v", err)
+	}
+	if _, exists := mockRepo.profiles["87654321"]; exists {
+		t.Error("expected the erased profile to be gone")
+	}
+	if err := service.EraseProfile(ctx, "87654321"); !goerrors.As(err, &notFoundErr) {
+		t.Errorf("expected erasing twice to be NotFound, got %v", err)
+	}
+
+	erase := recorder.entries[len(recorder.entries)-1]
+	if erase.Action != AuditProfileErase || erase.EntityID != "E0000001" {
+		t.Errorf("expected the erase to be audited under the pseudonym, got %+v", erase)
+	}
+	if string(erase.Before) != "null" || string(erase.After) != "null" {
+		t.Errorf("erase audit entry shouldn't hold personal fields, got %s %s", erase.Before, erase.After)
+	}
+}
+
+// WithTx undoes changes to profiles and memberships if fn fails
+func (m *mockGamerProfileRepository) CreateNote(ctx context.Context, note *models.MemberNote) (*models.MemberNote, error) {
+	note.ID = fmt.Sprintf("note-%d", len(m.notes)+1)
+	note.CreatedAt = time.Now()
+	m.notes = append(m.notes, *note)
+	m.refreshNotes(note.StudentNumber)
+	return note, nil
+}
+
+func (m *mockGamerProfileRepository) GetNote(ctx context.Context, noteID string) (*models.MemberNote, error) {
+	for i := range m.notes {
+		if m.notes[i].ID == noteID {
+			note := m.notes[i]
+			return &note, nil
+		}
+	}
+	return nil, errors.NewNotFoundError("note", noteID)
+}
+
+func (m *mockGamerProfileRepository) ListNotes(ctx context.Context, studentNumber string, includeArchived bool) ([]models.MemberNote, error) {
+	var notes []models.MemberNote
+	for i := len(m.notes) - 1; i >= 0; i-- {
+		if m.notes[i].StudentNumber == studentNumber && (includeArchived || m.notes[i].ArchivedAt == nil) {
+			notes = append(notes, m.notes[i])
+		}
+	}
+	return notes, nil
+}
+
+func (m *mockGamerProfileRepository) ArchiveNote(ctx context.Context, note *models.MemberNote) (*models.MemberNote, error) {
+	for i := range m.notes {
+		if m.notes[i].ID == note.ID && m.notes[i].ArchivedAt == nil {
+			now := time.Now()
+			note.ArchivedAt = &now
+			m.notes[i] = *note
+			m.refreshNotes(note.StudentNumber)
+			return note, nil
+		}
+	}
+	return nil, errors.NewNotFoundError("note", note.ID)
+}
+
+// refreshNotes sets the notes of a profile to its latest pinned note
+func (m *mockGamerProfileRepository) refreshNotes(studentNumber string) {
+	profile, exists := m.profiles[studentNumber]
+	if !exists {
+		return
+	}
+	profile.Notes = nil
+	for i := range m.notes {
+		if m.notes[i].StudentNumber == studentNumber && m.notes[i].Pinned && m.notes[i].ArchivedAt == nil {
+			body := m.notes[i].Body
+			profile.Notes = &body
+		}
+	}
+}
+
+func (m *mockGamerProfileRepository) WithTx(ctx context.Context, fn func(repo gamer.GamerProfileRepository) error) error {
+	profiles := make(map[string]*models.GamerProfile, len(m.profiles))
+	for sn, p := range m.profiles {
+		profiles[sn] = p
+	}
+	memberships := append([]models.Membership(nil), m.memberships...)
+	notes := append([]models.MemberNote(nil), m.notes...)
+
+	if err := fn(m); err != nil {
+		m.profiles = profiles
+		m.memberships = memberships
+		m.notes = notes
+		return err
+	}
+	return nil
+}
@@ -2,14 +2,29 @@ package services
 
 import (
 	"context"
+	"io"
 
 	"github.com/ubcesports/echo-base/internal/models"
 )
 
 type GamerProfileService interface {
 	GetProfile(ctx context.Context, studentNumber string) (*models.GamerProfile, error)
+	ListProfiles(ctx context.Context, filter models.ProfileFilter) (*models.GamerProfilePage, error)
 	CreateOrUpdateProfile(ctx context.Context, req *models.CreateGamerProfileRequest) (*models.GamerProfile, error)
+	ImportProfiles(ctx context.Context, r io.Reader, opts models.ImportOptions) (*models.ImportReport, error)
+	UpdateProfile(ctx context.Context, studentNumber string, req *models.UpdateGamerProfileRequest) (*models.GamerProfile, error)
 	DeleteProfile(ctx context.Context, studentNumber string) error
+	RestoreProfile(ctx context.Context, studentNumber string) (*models.GamerProfile, error)
+	EraseProfile(ctx context.Context, studentNumber string) error
+	RenewMembership(ctx context.Context, studentNumber string, req *models.RenewMembershipRequest) (*models.Membership, error)
+	ListMemberships(ctx context.Context, studentNumber string) ([]models.Membership, error)
+	ProvisionMembership(ctx context.Context, req *models.ProvisionMembershipRequest) (*models.Membership, bool, error)
+	IssueBan(ctx context.Context, studentNumber string, req *models.IssueBanRequest) (*models.Ban, error)
+	LiftBan(ctx context.Context, studentNumber, banID string, req *models.LiftBanRequest) (*models.Ban, error)
+	ListBans(ctx context.Context, studentNumber string) ([]models.Ban, error)
+	AddNote(ctx context.Context, studentNumber string, req *models.CreateMemberNoteRequest) (*models.MemberNote, error)
+	ListNotes(ctx context.Context, studentNumber string, includeArchived bool) ([]models.MemberNote, error)
+	ArchiveNote(ctx context.Context, studentNumber, noteID string) (*models.MemberNote, error)
 }
 
 type GamerActivityService interface {
@@ -20,4 +35,5 @@ type GamerActivityService interface {
 	StartActivity(ctx context.Context, req *models.CreateActivityRequest) (*models.GamerActivity, error)
 	EndActivity(ctx context.Context, studentNumber string, req *models.UpdateActivityRequest) (*models.GamerActivity, error)
 	GetActiveSessions(ctx context.Context) ([]models.GamerActivity, error)
+	GetQuota(ctx context.Context, studentNumber string) (*models.PlayQuota, error)
 }
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
+)
+
+// lastUsedFlusher collects key usage in memory and writes it to the
+// database in batches instead of issuing an UPDATE for every request
+type lastUsedFlusher struct {
+	repo    auth.AuthRepository
+	mu      sync.Mutex
+	pending map[string]time.Time
+}
+
+func newLastUsedFlusher(repo auth.AuthRepository) *lastUsedFlusher {
+	return &lastUsedFlusher{
+		repo:    repo,
+		pending: make(map[string]time.Time),
+	}
+}
+
+func (f *lastUsedFlusher) Record(keyId string, usedAt time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if previous, exists := f.pending[keyId]; !exists || usedAt.After(previous) {
+		f.pending[keyId] = usedAt
+	}
+}
+
+// Flush writes all pending usage. On failure the usage is kept so the next
+// flush can retry it.
+func (f *lastUsedFlusher) Flush(ctx context.Context) error {
+	f.mu.Lock()
+	batch := f.pending
+	f.pending = make(map[string]time.Time)
+	f.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	if err := f.repo.UpdateLastUsed(ctx, batch); err != nil {
+		for keyId, usedAt := range batch {
+			f.Record(keyId, usedAt)
+		}
+		return err
+	}
+	return nil
+}
+
+// Run flushes every interval until ctx is cancelled
+func (f *lastUsedFlusher) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultAuthServiceConfig().FlushInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := f.Flush(ctx); err != nil {
+				log.Printf("failed to flush api key last_used_at: %v", err)
+			}
+		}
+	}
+}
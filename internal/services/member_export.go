@@ -0,0 +1,183 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/interfaces/gamer"
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+type MemberExportService interface {
+	ExportMember(ctx context.Context, studentNumber string) (*models.MemberExport, error)
+}
+
+// memberExportService reads profiles straight from the repository since the
+// profile service hides deleted members, and they can still ask for their data
+type memberExportService struct {
+	profiles   gamer.GamerProfileRepository
+	activities GamerActivityService
+	audit      AuditService
+}
+
+func NewMemberExportService(profiles gamer.GamerProfileRepository, activities GamerActivityService, audit AuditService) MemberExportService {
+	return &memberExportService{profiles: profiles, activities: activities, audit: audit}
+}
+
+// ExportMember gathers everything stored about a member, including one whose
+// profile was deleted. The export is itself audited, since it hands out their
+// personal data.
+func (s *memberExportService) ExportMember(ctx context.Context, studentNumber string) (*models.MemberExport, error) {
+	if err := validateStudentNumber(studentNumber); err != nil {
+		return nil, err
+	}
+
+	profile, err := s.profiles.GetByStudentNumberIncludingDeleted(ctx, studentNumber)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, errors.NewNotFoundError("student", studentNumber)
+	}
+
+	memberships, err := s.profiles.ListMemberships(ctx, studentNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	activity, err := s.activities.GetActivitiesByStudent(ctx, studentNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := s.profiles.ListNotes(ctx, studentNumber, true)
+	if err != nil {
+		return nil, err
+	}
+
+	bans, err := s.profiles.ListBans(ctx, studentNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.audit.ListEntriesForStudent(ctx, studentNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &models.MemberExport{
+		ExportedAt:   time.Now(),
+		Profile:      *profile,
+		Memberships:  nonNil(memberships),
+		Activity:     nonNil(activity),
+		Notes:        nonNil(notes),
+		Bans:         nonNil(bans),
+		AuditEntries: nonNil(entries),
+	}
+
+	s.audit.Record(ctx, AuditProfileExport, "profile", studentNumber, nil, nil)
+	return export, nil
+}
+
+// WriteMemberExportZip writes an export as a zip with a CSV file for each
+// kind of record. Columns are named after the JSON fields.
+func WriteMemberExportZip(w io.Writer, export *models.MemberExport) error {
+	zw := zip.NewWriter(w)
+
+	files := []struct {
+		name string
+		rows any
+	}{
+		{"profile.csv", []models.GamerProfile{export.Profile}},
+		{"memberships.csv", export.Memberships},
+		{"activity.csv", export.Activity},
+		{"notes.csv", export.Notes},
+		{"bans.csv", export.Bans},
+		{"audit_entries.csv", export.AuditEntries},
+	}
+	for _, file := range files {
+		f, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add %s: %w", file.name, err)
+		}
+		if err := writeExportCSV(f, file.rows); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+
+	return zw.Close()
+}
+
+// writeExportCSV writes a slice of structs as CSV with a header row of their
+// JSON field names
+func writeExportCSV(w io.Writer, rows any) error {
+	slice := reflect.ValueOf(rows)
+	elem := slice.Type().Elem()
+
+	var header []string
+	var fields []int
+	for i := range elem.NumField() {
+		name, _, _ := strings.Cut(elem.Field(i).Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		header = append(header, name)
+		fields = append(fields, i)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for i := range slice.Len() {
+		record := make([]string, len(fields))
+		for j, field := range fields {
+			record[j] = exportCSVValue(slice.Index(i).Field(field))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// exportCSVValue formats a field for a CSV cell. Nil pointers are empty.
+func exportCSVValue(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch value := v.Interface().(type) {
+	case time.Time:
+		return value.Format(time.RFC3339)
+	case json.RawMessage:
+		return string(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// nonNil returns an empty slice for nil, so exports list nothing as []
+// rather than null
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
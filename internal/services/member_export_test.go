@@ -0,0 +1,126 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	goerrors "errors"
+	"testing"
+	"time"
+
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+func TestExportMember(t *testing.T) {
+	auditRepo := &mockAuditRepository{}
+	auditService := NewAuditService(auditRepo)
+	profileRepo := &mockGamerProfileRepository{profiles: make(map[string]*models.GamerProfile)}
+	profileService := NewGamerProfileService(profileRepo, auditService)
+	activityRepo := &mockGamerActivityRepository{
+		activities: []models.GamerActivity{
+			{ID: "1", StudentNumber: "12345678", PCNumber: 3, Game: "Valorant", StartedAt: time.Now()},
+			{ID: "2", StudentNumber: "87654321", PCNumber: 4, Game: "Dota", StartedAt: time.Now()},
+		},
+	}
+	activityService := NewGamerActivityService(activityRepo, profileRepo, newMockStationRepository(), newMockReservationRepository(), auditService)
+	service := NewMemberExportService(profileRepo, activityService, auditService)
+	ctx := context.Background()
+
+	notes := "Prefers PC 3"
+	_, err := profileService.CreateOrUpdateProfile(ctx, &models.CreateGamerProfileRequest{
+		StudentNumber:  "12345678",
+		FirstName:      "John",
+		LastName:       "Doe",
+		MembershipTier: 1,
+		Notes:          &notes,
+	})
+	if err != nil {
+		t.Fatalf("CreateOrUpdateProfile() error = %v", err)
+	}
+	if _, err := profileService.IssueBan(ctx, "12345678", &models.IssueBanRequest{Reason: "Unplugged a PC"}); err != nil {
+		t.Fatalf("IssueBan() error = %v", err)
+	}
+
+	export, err := service.ExportMember(ctx, "12345678")
+	if err != nil {
+		t.Fatalf("ExportMember() error = %v", err)
+	}
+	if export.Profile.StudentNumber != "12345678" {
+		t.Errorf("unexpected profile %+v", export.Profile)
+	}
+	if len(export.Memberships) != 1 || len(export.Notes) != 1 || len(export.Bans) != 1 {
+		t.Errorf("expected a membership, note and ban, got %d, %d and %d", len(export.Memberships), len(export.Notes), len(export.Bans))
+	}
+	if len(export.Activity) != 1 || export.Activity[0].ID != "1" {
+		t.Errorf("expected only the member's activity, got %+v", export.Activity)
+	}
+	// The registration, its note and the ban
+	if len(export.AuditEntries) != 3 {
+		t.Errorf("expected 3 audit entries about the member, got %d", len(export.AuditEntries))
+	}
+	if last := auditRepo.entries[len(auditRepo.entries)-1]; last.Action != AuditProfileExport {
+		t.Errorf("expected the export to be audited, got %s", last.Action)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteMemberExportZip(&buf, export); err != nil {
+		t.Fatalf("WriteMemberExportZip() error = %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("failed to read zip: %v", err)
+	}
+	wantRows := map[string]int{
+		"profile.csv":       2,
+		"memberships.csv":   2,
+		"activity.csv":      2,
+		"notes.csv":         2,
+		"bans.csv":          2,
+		"audit_entries.csv": 4,
+	}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", f.Name, err)
+		}
+		records, err := csv.NewReader(r).ReadAll()
+		r.Close()
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", f.Name, err)
+		}
+		if len(records) != wantRows[f.Name] {
+			t.Errorf("expected %d rows in %s, got %d", wantRows[f.Name], f.Name, len(records))
+		}
+		if f.Name == "profile.csv" && (records[0][1] != "student_number" || records[1][1] != "12345678") {
+			t.Errorf("unexpected profile.csv %v", records)
+		}
+		delete(wantRows, f.Name)
+	}
+	if len(wantRows) != 0 {
+		t.Errorf("missing files %v", wantRows)
+	}
+
+	t.Run("deleted member", func(t *testing.T) {
+		if err := profileService.DeleteProfile(ctx, "12345678"); err != nil {
+			t.Fatalf("DeleteProfile() error = %v", err)
+		}
+
+		export, err := service.ExportMember(ctx, "12345678")
+		if err != nil {
+			t.Fatalf("ExportMember() error = %v", err)
+		}
+		if export.Profile.StudentNumber != "12345678" || len(export.Memberships) != 1 || len(export.Bans) != 1 {
+			t.Errorf("expected the deleted member's records, got %+v", export)
+		}
+	})
+
+	t.Run("missing member", func(t *testing.T) {
+		_, err := service.ExportMember(ctx, "11111111")
+		var notFoundErr *errors.NotFoundError
+		if !goerrors.As(err, &notFoundErr) {
+			t.Errorf("expected NotFoundError, got %v", err)
+		}
+	})
+}
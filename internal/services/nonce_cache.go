@@ -0,0 +1,53 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// nonceCache remembers the nonces of signed requests until their timestamps
+// fall outside the accepted clock skew, after which the request would be
+// rejected as stale anyway
+type nonceCache struct {
+	mu        sync.Mutex
+	entries   map[string]time.Time
+	pruneSize int
+}
+
+const minNoncePruneSize = 1024
+
+func newNonceCache() *nonceCache {
+	return &nonceCache{
+		entries:   make(map[string]time.Time),
+		pruneSize: minNoncePruneSize,
+	}
+}
+
+// Use records nonce as seen until expiresAt. It returns false if the nonce
+// was already used and has not expired.
+func (c *nonceCache) Use(nonce string, expiresAt, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if seenUntil, seen := c.entries[nonce]; seen && now.Before(seenUntil) {
+		return false
+	}
+
+	if len(c.entries) >= c.pruneSize {
+		c.prune(now)
+	}
+	c.entries[nonce] = expiresAt
+	return true
+}
+
+// prune drops expired nonces. The next prune happens once the cache has
+// doubled again so the cost stays proportional to inserts. Callers must hold
+// c.mu.
+func (c *nonceCache) prune(now time.Time) {
+	for nonce, expiresAt := range c.entries {
+		if !now.Before(expiresAt) {
+			delete(c.entries, nonce)
+		}
+	}
+	c.pruneSize = max(minNoncePruneSize, 2*len(c.entries))
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNonceCacheRejectsReuse(t *testing.T) {
+	cache := newNonceCache()
+	now := time.Now()
+
+	assert.True(t, cache.Use("key:nonce", now.Add(time.Minute), now))
+	assert.False(t, cache.Use("key:nonce", now.Add(time.Minute), now.Add(30*time.Second)))
+	assert.True(t, cache.Use("other:nonce", now.Add(time.Minute), now))
+
+	// Once expired the request would be stale anyway, so the nonce is forgotten
+	assert.True(t, cache.Use("key:nonce", now.Add(2*time.Minute), now.Add(time.Minute)))
+}
+
+func TestNonceCachePrunesExpired(t *testing.T) {
+	cache := newNonceCache()
+	now := time.Now()
+
+	for i := 0; i < minNoncePruneSize; i++ {
+		cache.Use(fmt.Sprintf("nonce-%d", i), now.Add(time.Minute), now)
+	}
+
+	now = now.Add(2 * time.Minute)
+	cache.Use("fresh", now.Add(time.Minute), now)
+	assert.Len(t, cache.entries, 1)
+}
@@ -0,0 +1,359 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	goerrors "errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/interfaces/gamer"
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+// importRow is a CSV row with the registration read from it. req is nil
+// when the row couldn't be read.
+type importRow struct {
+	result models.ImportRowResult
+	req    *models.CreateGamerProfileRequest
+}
+
+// ImportProfiles registers every member in a CSV file, such as a Showpass
+// export, as if each row had been sent to CreateOrUpdateProfile. Rows are
+// checked before anything is written, so a dry run reports exactly what an
+// import would do. Rows that repeat a student number, or whose student
+// number belongs to someone with another name, are conflicts and are never
+// written. An atomic import writes nothing unless every row can be written.
+func (s *gamerProfileService) ImportProfiles(ctx context.Context, r io.Reader, opts models.ImportOptions) (*models.ImportReport, error) {
+	opts = withImportDefaults(opts)
+
+	rows, err := readImportRows(r, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := &importSeen{studentNumbers: make(map[string]int), emails: make(map[string]int)}
+	for i := range rows {
+		if err := s.planImportRow(ctx, &rows[i], seen); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &models.ImportReport{DryRun: opts.DryRun, Atomic: opts.Atomic}
+	switch {
+	case opts.DryRun:
+	case opts.Atomic:
+		if !hasImportProblems(rows) {
+			report.Committed = s.importAtomically(ctx, rows)
+		}
+	default:
+		for i := range rows {
+			writeImportRow(ctx, s, &rows[i])
+		}
+		report.Committed = true
+	}
+
+	report.Rows = make([]models.ImportRowResult, len(rows))
+	for i, row := range rows {
+		report.Rows[i] = row.result
+		switch row.result.Status {
+		case models.ImportRowCreate:
+			report.Created++
+		case models.ImportRowUpdate:
+			report.Updated++
+		case models.ImportRowSkip:
+			report.Skipped++
+		case models.ImportRowConflict:
+			report.Conflicts++
+		case models.ImportRowInvalid:
+			report.Invalid++
+		case models.ImportRowFailed:
+			report.Failed++
+		}
+	}
+	return report, nil
+}
+
+// importSeen holds the row each student number and email was first found on
+type importSeen struct {
+	studentNumbers map[string]int
+	emails         map[string]int
+}
+
+// planImportRow validates a row and works out whether it creates, updates or
+// skips a profile
+func (s *gamerProfileService) planImportRow(ctx context.Context, row *importRow, seen *importSeen) error {
+	if row.req == nil {
+		return nil
+	}
+
+	_, source, err := validateProfileRequest(row.req)
+	if err != nil {
+		var validationErr *errors.ValidationError
+		if !goerrors.As(err, &validationErr) {
+			return err
+		}
+		row.result.Status = models.ImportRowInvalid
+		row.result.Field = validationErr.Field
+		row.result.Message = validationErr.Message
+		return nil
+	}
+
+	if first, ok := seen.studentNumbers[row.req.StudentNumber]; ok {
+		markConflict(row, "student_number", fmt.Sprintf("is also on row %d", first))
+		return nil
+	}
+	seen.studentNumbers[row.req.StudentNumber] = row.result.Row
+
+	if row.req.Email != nil && strings.TrimSpace(*row.req.Email) != "" {
+		// Emails are unique regardless of case, like the index on the column
+		email := strings.ToLower(strings.TrimSpace(*row.req.Email))
+		if first, ok := seen.emails[email]; ok {
+			markConflict(row, "email", fmt.Sprintf("is also on row %d", first))
+			return nil
+		}
+
+		owner, err := s.repo.GetByEmail(ctx, email)
+		var notFoundErr *errors.NotFoundError
+		if err != nil && !goerrors.As(err, &notFoundErr) {
+			return err
+		}
+		if owner != nil && owner.StudentNumber != row.req.StudentNumber {
+			markConflict(row, "email", "is already used by another member")
+			return nil
+		}
+		seen.emails[email] = row.result.Row
+	}
+
+	existing, err := s.findProfile(ctx, row.req.StudentNumber)
+	if err != nil {
+		return err
+	}
+
+	if existing == nil {
+		if err := s.checkNotDeleted(ctx, row.req.StudentNumber); err != nil {
+			var conflictErr *errors.ConflictError
+			if !goerrors.As(err, &conflictErr) {
+				return err
+			}
+			markConflict(row, "student_number", "belongs to a deleted member, who must be restored first")
+			return nil
+		}
+	}
+
+	switch {
+	case existing == nil:
+		row.result.Status = models.ImportRowCreate
+	case !strings.EqualFold(existing.FirstName, row.req.FirstName) || !strings.EqualFold(existing.LastName, row.req.LastName):
+		markConflict(row, "student_number", fmt.Sprintf("belongs to %s %s", existing.FirstName, existing.LastName))
+	default:
+		current, err := s.currentMembership(ctx, row.req.StudentNumber)
+		if err != nil {
+			return err
+		}
+		if current != nil && current.MembershipTier == row.req.MembershipTier && current.Source == source {
+			row.result.Status = models.ImportRowSkip
+			row.result.Message = fmt.Sprintf("already has a current tier %d membership from %s", current.MembershipTier, current.Source)
+			return nil
+		}
+		row.result.Status = models.ImportRowUpdate
+	}
+	return nil
+}
+
+// markConflict marks row as a conflict over field
+func markConflict(row *importRow, field, message string) {
+	row.result.Status = models.ImportRowConflict
+	row.result.Field = field
+	row.result.Message = message
+}
+
+// writeImportRow writes a planned row through service, marking it failed if it
+// couldn't be saved
+func writeImportRow(ctx context.Context, service *gamerProfileService, row *importRow) error {
+	if row.result.Status != models.ImportRowCreate && row.result.Status != models.ImportRowUpdate {
+		return nil
+	}
+
+	if _, err := service.CreateOrUpdateProfile(ctx, row.req); err != nil {
+		row.result.Status = models.ImportRowFailed
+		row.result.Message = err.Error()
+		return err
+	}
+	return nil
+}
+
+// importAtomically writes every row in one transaction and reports whether
+// it was committed. The audit entries are only recorded once it is.
+func (s *gamerProfileService) importAtomically(ctx context.Context, rows []importRow) bool {
+	audit := &deferredAudit{}
+	err := s.repo.WithTx(ctx, func(repo gamer.GamerProfileRepository) error {
+		service := &gamerProfileService{repo: repo, audit: audit}
+		for i := range rows {
+			if err := writeImportRow(ctx, service, &rows[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false
+	}
+
+	audit.flush(ctx, s.audit)
+	return true
+}
+
+func hasImportProblems(rows []importRow) bool {
+	for _, row := range rows {
+		status := row.result.Status
+		if status != models.ImportRowCreate && status != models.ImportRowUpdate && status != models.ImportRowSkip {
+			return true
+		}
+	}
+	return false
+}
+
+// withImportDefaults fills in the Showpass headers and ticket types for
+// anything left out of opts
+func withImportDefaults(opts models.ImportOptions) models.ImportOptions {
+	defaults := models.DefaultImportColumns()
+	if opts.Columns.StudentNumber == "" {
+		opts.Columns.StudentNumber = defaults.StudentNumber
+	}
+	if opts.Columns.FirstName == "" {
+		opts.Columns.FirstName = defaults.FirstName
+	}
+	if opts.Columns.LastName == "" {
+		opts.Columns.LastName = defaults.LastName
+	}
+	if opts.Columns.MembershipTier == "" {
+		opts.Columns.MembershipTier = defaults.MembershipTier
+	}
+	if opts.Tiers == nil {
+		opts.Tiers = models.ShowpassTicketTiers()
+	}
+	return opts
+}
+
+// readImportRows reads the registrations out of a CSV file using the
+// column mapping in opts. A file that can't be parsed, or is missing a
+// mapped column, is rejected as a whole.
+func readImportRows(r io.Reader, opts models.ImportOptions) ([]importRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.NewValidationError("file", "is empty")
+	}
+	if err != nil {
+		return nil, errors.NewValidationError("file", err.Error())
+	}
+
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			// Spreadsheet programs often start the file with a byte order mark
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		positions[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	column := func(field, name string) (int, error) {
+		if name == "" {
+			return -1, nil
+		}
+		i, ok := positions[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return 0, errors.NewValidationError("columns."+field, fmt.Sprintf("no %q column in the file", name))
+		}
+		return i, nil
+	}
+
+	var cols struct{ studentNumber, firstName, lastName, tier, notes, email, discordHandle int }
+	mapping := []struct {
+		field, name string
+		dest        *int
+	}{
+		{"student_number", opts.Columns.StudentNumber, &cols.studentNumber},
+		{"first_name", opts.Columns.FirstName, &cols.firstName},
+		{"last_name", opts.Columns.LastName, &cols.lastName},
+		{"membership_tier", opts.Columns.MembershipTier, &cols.tier},
+		{"notes", opts.Columns.Notes, &cols.notes},
+		{"email", opts.Columns.Email, &cols.email},
+		{"discord_handle", opts.Columns.DiscordHandle, &cols.discordHandle},
+	}
+	for _, m := range mapping {
+		if *m.dest, err = column(m.field, m.name); err != nil {
+			return nil, err
+		}
+	}
+
+	var rows []importRow
+	for n := 1; ; n++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.NewValidationError("file", err.Error())
+		}
+
+		value := func(i int) string {
+			if i < 0 || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		row := importRow{result: models.ImportRowResult{Row: n, StudentNumber: value(cols.studentNumber)}}
+		tier, err := parseImportTier(value(cols.tier), opts.Tiers)
+		if err != nil {
+			row.result.Status = models.ImportRowInvalid
+			row.result.Field = "membership_tier"
+			row.result.Message = err.Error()
+			rows = append(rows, row)
+			continue
+		}
+
+		row.req = &models.CreateGamerProfileRequest{
+			StudentNumber:    value(cols.studentNumber),
+			FirstName:        value(cols.firstName),
+			LastName:         value(cols.lastName),
+			MembershipTier:   tier,
+			MembershipSource: models.MembershipSourceShowpass,
+		}
+		if notes := value(cols.notes); notes != "" {
+			row.req.Notes = &notes
+		}
+		if email := value(cols.email); email != "" {
+			row.req.Email = &email
+		}
+		if handle := value(cols.discordHandle); handle != "" {
+			row.req.DiscordHandle = &handle
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseImportTier reads a tier number, or a name from tiers ignoring case
+func parseImportTier(value string, tiers map[string]int) (int, error) {
+	if value == "" {
+		return 0, goerrors.New("is required")
+	}
+	if n, err := strconv.Atoi(value); err == nil {
+		return n, nil
+	}
+	for name, tier := range tiers {
+		if strings.EqualFold(name, value) {
+			return tier, nil
+		}
+	}
+	return 0, fmt.Errorf("%q isn't a known membership", value)
+}
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"context"
+	goerrors "errors"
+	"strings"
+	"testing"
+
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+const showpassExport = "\ufeffOrder ID,First Name,Last Name,Student Number,Ticket Type\n" +
+	"1001,Jane,Smith,11111111,Tier 1 Membership\n" +
+	"1002,John,Doe,22222222,premier membership\n" +
+	"1003,Jane,Smith,11111111,Tier 2 Membership\n" +
+	"1004,Someone,Else,33333333,Tier 1 Membership\n" +
+	"1005,No,Number,,Tier 1 Membership\n" +
+	"1006,Gift,Card,44444444,Gift Card\n"
+
+func newImportTestService() (*mockGamerProfileRepository, *mockAuditRecorder, GamerProfileService) {
+	notes := "existing member"
+	repo := &mockGamerProfileRepository{
+		profiles: map[string]*models.GamerProfile{
+			"22222222": {StudentNumber: "22222222", FirstName: "John", LastName: "Doe", Notes: &notes},
+			"33333333": {StudentNumber: "33333333", FirstName: "Alex", LastName: "Chen"},
+		},
+	}
+	audit := &mockAuditRecorder{}
+	return repo, audit, NewGamerProfileService(repo, audit)
+}
+
+func TestImportProfilesDryRun(t *testing.T) {
+	repo, audit, service := newImportTestService()
+
+	report, err := service.ImportProfiles(context.Background(), strings.NewReader(showpassExport), models.ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("ImportProfiles() error = %v", err)
+	}
+
+	want := []models.ImportRowStatus{
+		models.ImportRowCreate,
+		models.ImportRowUpdate,
+		models.ImportRowConflict,
+		models.ImportRowConflict,
+		models.ImportRowInvalid,
+		models.ImportRowInvalid,
+	}
+	if len(report.Rows) != len(want) {
+		t.Fatalf("expected %d rows, got %d", len(want), len(report.Rows))
+	}
+	for i, status := range want {
+		if report.Rows[i].Status != status {
+			t.Errorf("row %d: expected %s, got %s (%s)", i+1, status, report.Rows[i].Status, report.Rows[i].Message)
+		}
+	}
+
+	if report.Created != 1 || report.Updated != 1 || report.Conflicts != 2 || report.Invalid != 2 {
+		t.Errorf("unexpected counts %+v", report)
+	}
+	if report.Rows[4].Field != "student_number" || report.Rows[5].Field != "membership_tier" {
+		t.Errorf("expected invalid rows to name the field, got %q and %q", report.Rows[4].Field, report.Rows[5].Field)
+	}
+	if report.Committed {
+		t.Error("expected a dry run not to be committed")
+	}
+	if len(repo.profiles) != 2 || len(repo.memberships) != 0 || len(audit.entries) != 0 {
+		t.Error("expected a dry run to write nothing")
+	}
+}
+
+func TestImportProfilesWritesValidRows(t *testing.T) {
+	repo, _, service := newImportTestService()
+
+	report, err := service.ImportProfiles(context.Background(), strings.NewReader(showpassExport), models.ImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportProfiles() error = %v", err)
+	}
+
+	if !report.Committed || report.Created != 1 || report.Updated != 1 {
+		t.Errorf("unexpected report %+v", report)
+	}
+	if p := repo.profiles["11111111"]; p == nil || p.MembershipTier != 1 {
+		t.Errorf("expected the first row for 11111111 to be imported as tier 1, got %+v", p)
+	}
+	if p := repo.profiles["22222222"]; p.Notes == nil || *p.Notes != "existing member" {
+		t.Error("expected an update without notes to keep the existing notes")
+	}
+	if p := repo.profiles["33333333"]; p.FirstName != "Alex" {
+		t.Error("expected the conflicting row to be left alone")
+	}
+	for _, m := range repo.memberships {
+		if m.Source != models.MembershipSourceShowpass {
+			t.Errorf("expected imported memberships to come from showpass, got %s", m.Source)
+		}
+	}
+}
+
+func TestImportProfilesAtomic(t *testing.T) {
+	t.Run("abandoned when a row has a problem", func(t *testing.T) {
+		repo, audit, service := newImportTestService()
+
+		report, err := service.ImportProfiles(context.Background(), strings.NewReader(showpassExport), models.ImportOptions{Atomic: true})
+		if err != nil {
+			t.Fatalf("ImportProfiles() error = %v", err)
+		}
+
+		if report.Committed {
+			t.Error("expected the import to be abandoned")
+		}
+		if len(repo.profiles) != 2 || len(repo.memberships) != 0 || len(audit.entries) != 0 {
+			t.Error("expected nothing to be written")
+		}
+	})
+
+	t.Run("rolled back when a row fails", func(t *testing.T) {
+		repo, audit, service := newImportTestService()
+		repo.upsertErr = goerrors.New("connection reset")
+
+		csv := "First Name,Last Name,Student Number,Ticket Type\nJane,Smith,11111111,1\n"
+		report, err := service.ImportProfiles(context.Background(), strings.NewReader(csv), models.ImportOptions{Atomic: true})
+		if err != nil {
+			t.Fatalf("ImportProfiles() error = %v", err)
+		}
+
+		if report.Committed || report.Failed != 1 {
+			t.Errorf("unexpected report %+v", report)
+		}
+		if len(audit.entries) != 0 {
+			t.Error("expected no audit entries for a rolled back import")
+		}
+	})
+
+	t.Run("committed and audited", func(t *testing.T) {
+		repo, audit, service := newImportTestService()
+
+		csv := "First Name,Last Name,Student Number,Ticket Type\nJane,Smith,11111111,1\nJohn,Doe,22222222,2\n"
+		report, err := service.ImportProfiles(context.Background(), strings.NewReader(csv), models.ImportOptions{Atomic: true})
+		if err != nil {
+			t.Fatalf("ImportProfiles() error = %v", err)
+		}
+
+		if !report.Committed || report.Created != 1 || report.Updated != 1 {
+			t.Errorf("unexpected report %+v", report)
+		}
+		if len(repo.memberships) != 2 {
+			t.Errorf("expected 2 memberships, got %d", len(repo.memberships))
+		}
+		if len(audit.entries) != 2 {
+			t.Errorf("expected 2 audit entries, got %d", len(audit.entries))
+		}
+	})
+}
+
+func TestImportProfilesSkipsCurrentMemberships(t *testing.T) {
+	repo, _, service := newImportTestService()
+	ctx := context.Background()
+
+	csv := "First Name,Last Name,Student Number,Ticket Type\nJohn,Doe,22222222,2\n"
+	if _, err := service.ImportProfiles(ctx, strings.NewReader(csv), models.ImportOptions{}); err != nil {
+		t.Fatalf("ImportProfiles() error = %v", err)
+	}
+
+	report, err := service.ImportProfiles(ctx, strings.NewReader(csv), models.ImportOptions{Atomic: true})
+	if err != nil {
+		t.Fatalf("ImportProfiles() error = %v", err)
+	}
+	if !report.Committed || report.Skipped != 1 || report.Rows[0].Status != models.ImportRowSkip {
+		t.Errorf("expected the re-imported row to be skipped, got %+v", report)
+	}
+
+	if _, err := service.RenewMembership(ctx, "22222222", &models.RenewMembershipRequest{MembershipTier: 2, Source: models.MembershipSourceCash}); err != nil {
+		t.Fatalf("RenewMembership() error = %v", err)
+	}
+	report, err = service.ImportProfiles(ctx, strings.NewReader(csv), models.ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("ImportProfiles() error = %v", err)
+	}
+	if report.Rows[0].Status != models.ImportRowUpdate {
+		t.Errorf("expected a membership from another source not to be skipped, got %s", report.Rows[0].Status)
+	}
+	if len(repo.memberships) != 2 {
+		t.Errorf("expected 2 memberships, got %d", len(repo.memberships))
+	}
+}
+
+func TestImportProfilesEmailConflicts(t *testing.T) {
+	repo, _, service := newImportTestService()
+	taken := "alex@example.com"
+	repo.profiles["33333333"].Email = &taken
+
+	csv := "First Name,Last Name,Student Number,Ticket Type,Email\n" +
+		"Jane,Smith,11111111,1,Jane@Example.com\n" +
+		"Sam,Lee,55555555,1,jane@example.COM\n" +
+		"John,Doe,22222222,1,ALEX@example.com\n" +
+		"Alex,Chen,33333333,1,Alex@Example.com\n"
+	opts := models.ImportOptions{DryRun: true, Columns: models.ImportColumns{Email: "Email"}}
+	report, err := service.ImportProfiles(context.Background(), strings.NewReader(csv), opts)
+	if err != nil {
+		t.Fatalf("ImportProfiles() error = %v", err)
+	}
+
+	want := []models.ImportRowStatus{
+		models.ImportRowCreate,
+		models.ImportRowConflict,
+		models.ImportRowConflict,
+		models.ImportRowUpdate,
+	}
+	for i, status := range want {
+		if report.Rows[i].Status != status {
+			t.Errorf("row %d: expected %s, got %s (%s)", i+1, status, report.Rows[i].Status, report.Rows[i].Message)
+		}
+	}
+	if report.Rows[1].Field != "email" || report.Rows[2].Field != "email" {
+		t.Errorf("expected the conflicts to name the email, got %q and %q", report.Rows[1].Field, report.Rows[2].Field)
+	}
+}
+
+func TestImportProfilesColumnMapping(t *testing.T) {
+	repo, _, service := newImportTestService()
+
+	csv := "sid,given,family,tier,comment\n11111111,Jane,Smith,3,paid in person\n"
+	opts := models.ImportOptions{
+		Columns: models.ImportColumns{
+			StudentNumber:  "SID",
+			FirstName:      "given",
+			LastName:       "family",
+			MembershipTier: "tier",
+			Notes:          "comment",
+		},
+	}
+	if _, err := service.ImportProfiles(context.Background(), strings.NewReader(csv), opts); err != nil {
+		t.Fatalf("ImportProfiles() error = %v", err)
+	}
+
+	p := repo.profiles["11111111"]
+	if p == nil || p.FirstName != "Jane" || p.MembershipTier != 3 || p.Notes == nil || *p.Notes != "paid in person" {
+		t.Errorf("unexpected profile %+v", p)
+	}
+
+	opts.Columns.Notes = "remarks"
+	_, err := service.ImportProfiles(context.Background(), strings.NewReader(csv), opts)
+	var validationErr *errors.ValidationError
+	if !goerrors.As(err, &validationErr) || validationErr.Field != "columns.notes" {
+		t.Errorf("expected a missing column to be rejected, got %v", err)
+	}
+}
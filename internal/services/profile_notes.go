@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+// AddNote adds a note to a member's timeline
+func (s *gamerProfileService) AddNote(ctx context.Context, studentNumber string, req *models.CreateMemberNoteRequest) (*models.MemberNote, error) {
+	if err := validateStudentNumber(studentNumber); err != nil {
+		return nil, err
+	}
+
+	body, err := validateNoteBody("body", req.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	category := req.Category
+	if category == "" {
+		category = models.NoteCategoryInfo
+	}
+	if !category.IsValid() {
+		return nil, errors.NewValidationError("category", "must be info, warning or incident")
+	}
+
+	profile, err := s.findProfile(ctx, studentNumber)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, errors.NewNotFoundError("student", studentNumber)
+	}
+
+	return s.addNote(ctx, studentNumber, body, category, req.Pinned)
+}
+
+func (s *gamerProfileService) addNote(ctx context.Context, studentNumber, body string, category models.NoteCategory, pinned bool) (*models.MemberNote, error) {
+	note := &models.MemberNote{
+		StudentNumber: studentNumber,
+		Body:          body,
+		Category:      category,
+		Pinned:        pinned,
+	}
+	if appName := auth.AppNameFromContext(ctx); appName != "" {
+		note.AuthorApp = &appName
+	}
+	if exec := auth.ExecFromContext(ctx); exec != nil {
+		note.AuthorExecID = &exec.ID
+	}
+
+	created, err := s.repo.CreateNote(ctx, note)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditNoteAdd, "note", created.ID, nil, created)
+	return created, nil
+}
+
+// ListNotes returns a member's notes, newest first. Archived notes are left
+// out unless includeArchived is set.
+func (s *gamerProfileService) ListNotes(ctx context.Context, studentNumber string, includeArchived bool) ([]models.MemberNote, error) {
+	if err := validateStudentNumber(studentNumber); err != nil {
+		return nil, err
+	}
+
+	profile, err := s.findProfile(ctx, studentNumber)
+	if err != nil {
+		return nil, err
+	}
+	if profile == nil {
+		return nil, errors.NewNotFoundError("student", studentNumber)
+	}
+
+	return s.repo.ListNotes(ctx, studentNumber, includeArchived)
+}
+
+// ArchiveNote takes a note off a member's timeline. It's kept for the
+// history and can still be listed.
+func (s *gamerProfileService) ArchiveNote(ctx context.Context, studentNumber, noteID string) (*models.MemberNote, error) {
+	if err := validateStudentNumber(studentNumber); err != nil {
+		return nil, err
+	}
+
+	before, err := s.repo.GetNote(ctx, noteID)
+	if err != nil {
+		return nil, err
+	}
+	if before.StudentNumber != studentNumber {
+		return nil, errors.NewNotFoundError("note", noteID)
+	}
+	if before.ArchivedAt != nil {
+		return nil, errors.NewValidationError("note", "is already archived")
+	}
+
+	return s.archiveNote(ctx, before)
+}
+
+func (s *gamerProfileService) archiveNote(ctx context.Context, before *models.MemberNote) (*models.MemberNote, error) {
+	note := *before
+	if appName := auth.AppNameFromContext(ctx); appName != "" {
+		note.ArchivedByApp = &appName
+	}
+	if exec := auth.ExecFromContext(ctx); exec != nil {
+		note.ArchivedByExecID = &exec.ID
+	}
+
+	archived, err := s.repo.ArchiveNote(ctx, &note)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditNoteArchive, "note", archived.ID, before, archived)
+	return archived, nil
+}
+
+// setProfileNotes handles the notes field of a profile, which clients from
+// before the notes timeline write as a single value. New notes are added as
+// a pinned note unless they match the current ones, and clearing the notes
+// archives the member's pinned notes. It returns the profile's notes after
+// the change.
+func (s *gamerProfileService) setProfileNotes(ctx context.Context, studentNumber string, current, notes *string) (*string, error) {
+	body := ""
+	if notes != nil {
+		body = strings.TrimSpace(*notes)
+	}
+
+	if body == "" {
+		pinned, err := s.repo.ListNotes(ctx, studentNumber, false)
+		if err != nil {
+			return nil, err
+		}
+		for i := range pinned {
+			if !pinned[i].Pinned {
+				continue
+			}
+			if _, err := s.archiveNote(ctx, &pinned[i]); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}
+
+	if current != nil && *current == body {
+		return current, nil
+	}
+
+	note, err := s.addNote(ctx, studentNumber, body, models.NoteCategoryInfo, true)
+	if err != nil {
+		return nil, err
+	}
+	return &note.Body, nil
+}
+
+// validateNoteBody trims a note and checks its length
+func validateNoteBody(field, body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", errors.NewValidationError(field, "is required")
+	}
+	if utf8.RuneCountInString(body) > models.MaxNoteLength {
+		return "", errors.NewValidationError(field, fmt.Sprintf("must be at most %d characters", models.MaxNoteLength))
+	}
+	return body, nil
+}
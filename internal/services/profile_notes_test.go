@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	goerrors "errors"
+	"strings"
+	"testing"
+
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+func newNotesTestRepo() *mockGamerProfileRepository {
+	return &mockGamerProfileRepository{
+		profiles: map[string]*models.GamerProfile{
+			"12345678": {StudentNumber: "12345678", FirstName: "John", LastName: "Doe"},
+			"87654321": {StudentNumber: "87654321", FirstName: "Jane", LastName: "Roe"},
+		},
+	}
+}
+
+func TestAddNote(t *testing.T) {
+	recorder := &mockAuditRecorder{}
+	service := NewGamerProfileService(newNotesTestRepo(), recorder)
+	ctx := auth.WithExec(context.Background(), &auth.ExecIdentity{ID: "exec-1", Name: "Exec One"})
+
+	note, err := service.AddNote(ctx, "12345678", &models.CreateMemberNoteRequest{Body: "  Left PC 4 logged in  "})
+	if err != nil {
+		t.Fatalf("AddNote() error = %v", err)
+	}
+	if note.Body != "Left PC 4 logged in" || note.Category != models.NoteCategoryInfo || note.Pinned {
+		t.Errorf("unexpected note %+v", note)
+	}
+	if note.AuthorExecID == nil || *note.AuthorExecID != "exec-1" {
+		t.Errorf("expected note to be attributed to exec-1, got %v", note.AuthorExecID)
+	}
+	if len(recorder.entries) != 1 || recorder.entries[0].Action != AuditNoteAdd {
+		t.Errorf("expected one note audit entry, got %+v", recorder.entries)
+	}
+
+	invalid := []struct {
+		name  string
+		sn    string
+		req   models.CreateMemberNoteRequest
+		field string
+	}{
+		{"empty body", "12345678", models.CreateMemberNoteRequest{Body: "   "}, "body"},
+		{"long body", "12345678", models.CreateMemberNoteRequest{Body: strings.Repeat("a", models.MaxNoteLength+1)}, "body"},
+		{"unknown category", "12345678", models.CreateMemberNoteRequest{Body: "Hi", Category: "gossip"}, "category"},
+		{"bad student number", "1234", models.CreateMemberNoteRequest{Body: "Hi"}, "student_number"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.AddNote(ctx, tt.sn, &tt.req)
+			var validationErr *errors.ValidationError
+			if !goerrors.As(err, &validationErr) || validationErr.Field != tt.field {
+				t.Errorf("expected a %s ValidationError, got %v", tt.field, err)
+			}
+		})
+	}
+
+	t.Run("missing profile", func(t *testing.T) {
+		_, err := service.AddNote(ctx, "11111111", &models.CreateMemberNoteRequest{Body: "Hi"})
+		var notFoundErr *errors.NotFoundError
+		if !goerrors.As(err, &notFoundErr) {
+			t.Errorf("expected NotFoundError, got %v", err)
+		}
+	})
+}
+
+func TestPinnedNoteIsProfileNotes(t *testing.T) {
+	mockRepo := newNotesTestRepo()
+	service := NewGamerProfileService(mockRepo, &mockAuditRecorder{})
+	ctx := context.Background()
+
+	first, err := service.AddNote(ctx, "12345678", &models.CreateMemberNoteRequest{Body: "Prefers PC 3", Pinned: true})
+	if err != nil {
+		t.Fatalf("AddNote() error = %v", err)
+	}
+	second, err := service.AddNote(ctx, "12345678", &models.CreateMemberNoteRequest{Body: "Rage quit twice", Category: models.NoteCategoryWarning, Pinned: true})
+	if err != nil {
+		t.Fatalf("AddNote() error = %v", err)
+	}
+	if _, err := service.AddNote(ctx, "12345678", &models.CreateMemberNoteRequest{Body: "Asked about tournaments"}); err != nil {
+		t.Fatalf("AddNote() error = %v", err)
+	}
+
+	profileNotes := func() *string {
+		profile, err := service.GetProfile(ctx, "12345678")
+		if err != nil {
+			t.Fatalf("GetProfile() error = %v", err)
+		}
+		return profile.Notes
+	}
+	if notes := profileNotes(); notes == nil || *notes != "Rage quit twice" {
+		t.Errorf("expected the latest pinned note, got %v", notes)
+	}
+
+	archived, err := service.ArchiveNote(ctx, "12345678", second.ID)
+	if err != nil {
+		t.Fatalf("ArchiveNote() error = %v", err)
+	}
+	if archived.ArchivedAt == nil {
+		t.Error("expected note to be archived")
+	}
+	if notes := profileNotes(); notes == nil || *notes != first.Body {
+		t.Errorf("expected the earlier pinned note, got %v", notes)
+	}
+
+	notes, err := service.ListNotes(ctx, "12345678", false)
+	if err != nil {
+		t.Fatalf("ListNotes() error = %v", err)
+	}
+	if len(notes) != 2 {
+		t.Errorf("expected 2 notes without archived ones, got %d", len(notes))
+	}
+	notes, err = service.ListNotes(ctx, "12345678", true)
+	if err != nil {
+		t.Fatalf("ListNotes() error = %v", err)
+	}
+	if len(notes) != 3 {
+		t.Errorf("expected 3 notes with archived ones, got %d", len(notes))
+	}
+
+	_, err = service.ArchiveNote(ctx, "12345678", second.ID)
+	var validationErr *errors.ValidationError
+	if !goerrors.As(err, &validationErr) {
+		t.Errorf("expected archiving twice to be a ValidationError, got %v", err)
+	}
+
+	_, err = service.ArchiveNote(ctx, "87654321", first.ID)
+	var notFoundErr *errors.NotFoundError
+	if !goerrors.As(err, &notFoundErr) {
+		t.Errorf("expected another member's note to be not found, got %v", err)
+	}
+}
+
+func TestProfileNotesField(t *testing.T) {
+	mockRepo := newNotesTestRepo()
+	service := NewGamerProfileService(mockRepo, &mockAuditRecorder{})
+	ctx := context.Background()
+
+	notes := "Prefers PC 3"
+	register := &models.CreateGamerProfileRequest{
+		StudentNumber:  "12345678",
+		FirstName:      "John",
+		LastName:       "Doe",
+		MembershipTier: 1,
+		Notes:          &notes,
+	}
+	for range 2 {
+		profile, err := service.CreateOrUpdateProfile(ctx, register)
+		if err != nil {
+			t.Fatalf("CreateOrUpdateProfile() error = %v", err)
+		}
+		if profile.Notes == nil || *profile.Notes != notes {
+			t.Errorf("expected notes %q, got %v", notes, profile.Notes)
+		}
+	}
+	if len(mockRepo.notes) != 1 || !mockRepo.notes[0].Pinned {
+		t.Fatalf("expected registering twice with the same notes to add one pinned note, got %+v", mockRepo.notes)
+	}
+
+	var req models.UpdateGamerProfileRequest
+	if err := json.Unmarshal([]byte(`{"notes": null}`), &req); err != nil {
+		t.Fatalf("failed to decode patch: %v", err)
+	}
+	profile, err := service.UpdateProfile(ctx, "12345678", &req)
+	if err != nil {
+		t.Fatalf("UpdateProfile() error = %v", err)
+	}
+	if profile.Notes != nil {
+		t.Errorf("expected notes to be cleared, got %q", *profile.Notes)
+	}
+	if mockRepo.notes[0].ArchivedAt == nil {
+		t.Error("expected clearing the notes to archive the pinned note")
+	}
+}
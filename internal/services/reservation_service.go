@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/interfaces/gamer"
+	"github.com/ubcesports/echo-base/internal/interfaces/reservation"
+	"github.com/ubcesports/echo-base/internal/interfaces/station"
+	"github.com/ubcesports/echo-base/internal/models"
+	"github.com/ubcesports/echo-base/internal/utils"
+)
+
+const (
+	AuditReservationCreate  = "reservation.create"
+	AuditReservationCancel  = "reservation.cancel"
+	AuditReservationCheckIn = "reservation.check_in"
+	AuditReservationNoShow  = "reservation.no_show"
+)
+
+const MaxReservationsPerPage = 100
+
+type ReservationServiceConfig struct {
+	// NoShowGrace is how long after a reservation starts its stations are
+	// held for before they are released to walk-ins
+	NoShowGrace time.Duration
+	// ReleaseInterval is how often reservations are checked for no-shows
+	ReleaseInterval time.Duration
+	// MaxDuration is the longest a single reservation may run
+	MaxDuration time.Duration
+}
+
+func DefaultReservationServiceConfig() ReservationServiceConfig {
+	return ReservationServiceConfig{
+		NoShowGrace:     15 * time.Minute,
+		ReleaseInterval: time.Minute,
+		MaxDuration:     5 * time.Hour,
+	}
+}
+
+type ReservationService interface {
+	CreateReservation(ctx context.Context, req *models.CreateReservationRequest) (*models.Reservation, error)
+	GetReservation(ctx context.Context, id string) (*models.Reservation, error)
+	ListReservations(ctx context.Context, filter models.ReservationFilter) ([]models.Reservation, error)
+	CancelReservation(ctx context.Context, id string) (*models.Reservation, error)
+}
+
+type reservationService struct {
+	repo        reservation.ReservationRepository
+	stations    station.StationRepository
+	profileRepo gamer.GamerProfileRepository
+	audit       AuditRecorder
+	config      ReservationServiceConfig
+}
+
+func NewReservationService(repo reservation.ReservationRepository, stations station.StationRepository, profileRepo gamer.GamerProfileRepository, audit AuditRecorder) ReservationService {
+	return NewReservationServiceWithConfig(repo, stations, profileRepo, audit, DefaultReservationServiceConfig())
+}
+
+func NewReservationServiceWithConfig(repo reservation.ReservationRepository, stations station.StationRepository, profileRepo gamer.GamerProfileRepository, audit AuditRecorder, config ReservationServiceConfig) *reservationService {
+	return &reservationService{
+		repo:        repo,
+		stations:    stations,
+		profileRepo: profileRepo,
+		audit:       audit,
+		config:      config,
+	}
+}
+
+func (s *reservationService) CreateReservation(ctx context.Context, req *models.CreateReservationRequest) (*models.Reservation, error) {
+	res := &models.Reservation{
+		TeamName: trimOptional(req.TeamName),
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+		Note:     trimOptional(req.Note),
+	}
+
+	if err := s.validateWindow(res, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if len(req.StationNumbers) == 0 {
+		return nil, errors.NewValidationError("station_numbers", "is required")
+	}
+	for _, number := range req.StationNumbers {
+		if slices.Contains(res.StationNumbers, number) {
+			continue
+		}
+		if err := checkStationAvailable(ctx, s.stations, "station_numbers", number); err != nil {
+			return nil, err
+		}
+		res.StationNumbers = append(res.StationNumbers, number)
+	}
+
+	for _, studentNumber := range req.StudentNumbers {
+		studentNumber = strings.TrimSpace(studentNumber)
+		if slices.Contains(res.StudentNumbers, studentNumber) {
+			continue
+		}
+		if err := s.checkMember(ctx, studentNumber); err != nil {
+			return nil, err
+		}
+		res.StudentNumbers = append(res.StudentNumbers, studentNumber)
+	}
+	if len(res.StudentNumbers) == 0 {
+		return nil, errors.NewValidationError("student_numbers", "is required")
+	}
+	if res.TeamName == nil && len(res.StudentNumbers) > 1 {
+		return nil, errors.NewValidationError("team_name", "is required to reserve for more than one member")
+	}
+
+	created, err := s.repo.Create(ctx, res)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditReservationCreate, "reservation", created.ID, nil, created)
+	return created, nil
+}
+
+func (s *reservationService) GetReservation(ctx context.Context, id string) (*models.Reservation, error) {
+	return s.repo.Get(ctx, id)
+}
+
+func (s *reservationService) ListReservations(ctx context.Context, filter models.ReservationFilter) ([]models.Reservation, error) {
+	if filter.Page < 1 {
+		return nil, errors.NewValidationError("page", "must be >= 1")
+	}
+
+	if filter.Limit < 1 || filter.Limit > MaxReservationsPerPage {
+		return nil, errors.NewValidationError("limit", fmt.Sprintf("must be between 1 and %d", MaxReservationsPerPage))
+	}
+
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, errors.NewValidationError("status", "must be booked, checked_in, cancelled or no_show")
+	}
+	if filter.StudentNumber != "" {
+		if err := validateStudentNumber(filter.StudentNumber); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repo.List(ctx, filter)
+}
+
+func (s *reservationService) CancelReservation(ctx context.Context, id string) (*models.Reservation, error) {
+	before, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	cancelled, err := s.repo.Cancel(ctx, id, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditReservationCancel, "reservation", id, before, cancelled)
+	return cancelled, nil
+}
+
+// ReleaseNoShows gives up the stations of reservations nobody has checked in
+// to by NoShowGrace after they started. It returns how many were released.
+func (s *reservationService) ReleaseNoShows(ctx context.Context, now time.Time) (int, error) {
+	noShows, err := s.repo.ListNoShows(ctx, now.Add(-s.config.NoShowGrace))
+	if err != nil {
+		return 0, err
+	}
+
+	released := 0
+	for i := range noShows {
+		res, err := s.repo.ReleaseNoShow(ctx, noShows[i].ID, now)
+		if err != nil {
+			return released, err
+		}
+		// Checked in to or cancelled since it was listed
+		if res == nil {
+			continue
+		}
+		s.audit.Record(ctx, AuditReservationNoShow, "reservation", res.ID, &noShows[i], res)
+		released++
+	}
+	return released, nil
+}
+
+// RunNoShowReleaser releases no-show reservations every ReleaseInterval until
+// ctx is cancelled
+func (s *reservationService) RunNoShowReleaser(ctx context.Context) {
+	interval := s.config.ReleaseInterval
+	if interval <= 0 {
+		interval = DefaultReservationServiceConfig().ReleaseInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			released, err := s.ReleaseNoShows(ctx, time.Now())
+			if err != nil {
+				log.Printf("failed to release no-show reservations: %v", err)
+			}
+			if released > 0 {
+				log.Printf("released %d no-show reservations", released)
+			}
+		}
+	}
+}
+
+func (s *reservationService) validateWindow(res *models.Reservation, now time.Time) error {
+	if res.StartsAt.IsZero() {
+		return errors.NewValidationError("starts_at", "is required")
+	}
+	if res.EndsAt.IsZero() {
+		return errors.NewValidationError("ends_at", "is required")
+	}
+	// The current minute still counts so a station can be booked on the spot
+	if res.StartsAt.Before(now.Truncate(time.Minute)) {
+		return errors.NewValidationError("starts_at", "can't be in the past")
+	}
+	if !res.EndsAt.After(res.StartsAt) {
+		return errors.NewValidationError("ends_at", "must be after starts_at")
+	}
+	if s.config.MaxDuration > 0 && res.EndsAt.Sub(res.StartsAt) > s.config.MaxDuration {
+		return errors.NewValidationError("ends_at", fmt.Sprintf("can't be more than %s after starts_at", s.config.MaxDuration))
+	}
+	return nil
+}
+
+// checkMember makes sure a reservation is only held for members who could
+// play
+func (s *reservationService) checkMember(ctx context.Context, studentNumber string) error {
+	if err := validateStudentNumber(studentNumber); err != nil {
+		return errors.NewValidationError("student_numbers", fmt.Sprintf("%s must be exactly 8 digits", studentNumber))
+	}
+
+	if _, err := s.profileRepo.GetByStudentNumber(ctx, studentNumber); err != nil {
+		return err
+	}
+
+	ban, err := s.profileRepo.GetActiveBan(ctx, studentNumber)
+	if err != nil {
+		return err
+	}
+	if ban != nil {
+		return errors.NewForbiddenError(banMessage(ban))
+	}
+	return nil
+}
+
+// reservationMessage describes who holds a reservation and until when
+func reservationMessage(stationNumber int, res *models.Reservation) string {
+	holder := "another member"
+	if res.TeamName != nil {
+		holder = *res.TeamName
+	}
+	return fmt.Sprintf("Station %d is reserved for %s from %s to %s", stationNumber, holder,
+		res.StartsAt.In(utils.LoungeLocation).Format("15:04"),
+		res.EndsAt.In(utils.LoungeLocation).Format("15:04"))
+}
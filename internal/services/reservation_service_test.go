@@ -0,0 +1,348 @@
+package services
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+type mockReservationRepository struct {
+	reservations []*models.Reservation
+}
+
+func newMockReservationRepository() *mockReservationRepository {
+	return &mockReservationRepository{}
+}
+
+func (m *mockReservationRepository) Create(ctx context.Context, res *models.Reservation) (*models.Reservation, error) {
+	overlapping, _ := m.GetOverlapping(ctx, res.StationNumbers, res.StartsAt, res.EndsAt)
+	if len(overlapping) > 0 {
+		return nil, errors.NewConflictError("the stations are already reserved at that time")
+	}
+	created := *res
+	created.ID = fmt.Sprintf("reservation-%d", len(m.reservations)+1)
+	created.Status = models.ReservationStatusBooked
+	created.CreatedAt = time.Now()
+	m.reservations = append(m.reservations, &created)
+	return &created, nil
+}
+
+func (m *mockReservationRepository) Get(ctx context.Context, id string) (*models.Reservation, error) {
+	for _, r := range m.reservations {
+		if r.ID == id {
+			found := *r
+			return &found, nil
+		}
+	}
+	return nil, errors.NewNotFoundError("reservation", id)
+}
+
+func (m *mockReservationRepository) List(ctx context.Context, filter models.ReservationFilter) ([]models.Reservation, error) {
+	var reservations []models.Reservation
+	for _, r := range m.reservations {
+		if filter.StudentNumber != "" && !r.IsHeldFor(filter.StudentNumber) {
+			continue
+		}
+		if filter.Status != "" && r.Status != filter.Status {
+			continue
+		}
+		reservations = append(reservations, *r)
+	}
+	return reservations, nil
+}
+
+func (m *mockReservationRepository) GetOverlapping(ctx context.Context, stationNumbers []int, windowStart, windowEnd time.Time) ([]models.Reservation, error) {
+	var overlapping []models.Reservation
+	for _, r := range m.reservations {
+		if !r.Status.IsActive() || !r.StartsAt.Before(windowEnd) || !r.EndsAt.After(windowStart) {
+			continue
+		}
+		for _, n := range stationNumbers {
+			if slices.Contains(r.StationNumbers, n) {
+				overlapping = append(overlapping, *r)
+				break
+			}
+		}
+	}
+	return overlapping, nil
+}
+
+func (m *mockReservationRepository) ListNoShows(ctx context.Context, startedBefore time.Time) ([]models.Reservation, error) {
+	var noShows []models.Reservation
+	for _, r := range m.reservations {
+		if r.Status == models.ReservationStatusBooked && r.StartsAt.Before(startedBefore) {
+			noShows = append(noShows, *r)
+		}
+	}
+	return noShows, nil
+}
+
+func (m *mockReservationRepository) CheckIn(ctx context.Context, id string, checkedInAt time.Time) (bool, error) {
+	for _, r := range m.reservations {
+		if r.ID == id && r.Status == models.ReservationStatusBooked {
+			r.Status = models.ReservationStatusCheckedIn
+			r.CheckedInAt = &checkedInAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockReservationRepository) Cancel(ctx context.Context, id string, cancelledAt time.Time) (*models.Reservation, error) {
+	for _, r := range m.reservations {
+		if r.ID != id {
+			continue
+		}
+		if !r.Status.IsActive() {
+			return nil, errors.NewConflictError(fmt.Sprintf("reservation is already %s", r.Status))
+		}
+		r.Status = models.ReservationStatusCancelled
+		r.CancelledAt = &cancelledAt
+		cancelled := *r
+		return &cancelled, nil
+	}
+	return nil, errors.NewNotFoundError("reservation", id)
+}
+
+func (m *mockReservationRepository) ReleaseNoShow(ctx context.Context, id string, releasedAt time.Time) (*models.Reservation, error) {
+	for _, r := range m.reservations {
+		if r.ID == id && r.Status == models.ReservationStatusBooked {
+			r.Status = models.ReservationStatusNoShow
+			r.ReleasedAt = &releasedAt
+			released := *r
+			return &released, nil
+		}
+	}
+	return nil, nil
+}
+
+func newReservationProfiles() *mockGamerProfileRepository {
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	return &mockGamerProfileRepository{
+		profiles: map[string]*models.GamerProfile{
+			"12345678": {StudentNumber: "12345678", MembershipTier: 2, MembershipExpiryDate: &tomorrow},
+			"23456789": {StudentNumber: "23456789", MembershipTier: 2, MembershipExpiryDate: &tomorrow},
+			"34567890": {StudentNumber: "34567890", MembershipTier: 1, MembershipExpiryDate: &tomorrow},
+		},
+		bans: []models.Ban{
+			{StudentNumber: "34567890", Reason: "Cheating", StartsAt: time.Now().Add(-time.Hour)},
+		},
+	}
+}
+
+func TestCreateReservation(t *testing.T) {
+	start := time.Now().Add(time.Hour).Truncate(time.Minute)
+	team := "UBC Valorant"
+
+	tests := []struct {
+		name      string
+		req       models.CreateReservationRequest
+		wantField string
+	}{
+		{"member", models.CreateReservationRequest{StationNumbers: []int{1}, StudentNumbers: []string{"12345678"}, StartsAt: start, EndsAt: start.Add(time.Hour)}, ""},
+		{"team", models.CreateReservationRequest{StationNumbers: []int{1, 2, 2}, StudentNumbers: []string{"12345678", "23456789"}, TeamName: &team, StartsAt: start, EndsAt: start.Add(2 * time.Hour)}, ""},
+		{"no stations", models.CreateReservationRequest{StudentNumbers: []string{"12345678"}, StartsAt: start, EndsAt: start.Add(time.Hour)}, "station_numbers"},
+		{"unknown station", models.CreateReservationRequest{StationNumbers: []int{99}, StudentNumbers: []string{"12345678"}, StartsAt: start, EndsAt: start.Add(time.Hour)}, "station_numbers"},
+		{"no members", models.CreateReservationRequest{StationNumbers: []int{1}, StartsAt: start, EndsAt: start.Add(time.Hour)}, "student_numbers"},
+		{"several members without a team", models.CreateReservationRequest{StationNumbers: []int{1}, StudentNumbers: []string{"12345678", "23456789"}, StartsAt: start, EndsAt: start.Add(time.Hour)}, "team_name"},
+		{"in the past", models.CreateReservationRequest{StationNumbers: []int{1}, StudentNumbers: []string{"12345678"}, StartsAt: start.Add(-3 * time.Hour), EndsAt: start.Add(-2 * time.Hour)}, "starts_at"},
+		{"ends before it starts", models.CreateReservationRequest{StationNumbers: []int{1}, StudentNumbers: []string{"12345678"}, StartsAt: start, EndsAt: start}, "ends_at"},
+		{"too long", models.CreateReservationRequest{StationNumbers: []int{1}, StudentNumbers: []string{"12345678"}, StartsAt: start, EndsAt: start.Add(6 * time.Hour)}, "ends_at"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewReservationService(newMockReservationRepository(), newMockStationRepository(), newReservationProfiles(), &mockAuditRecorder{})
+			created, err := service.CreateReservation(context.Background(), &tt.req)
+			if tt.wantField != "" {
+				var validationErr *errors.ValidationError
+				if assert.True(t, goerrors.As(err, &validationErr), "expected ValidationError, got %v", err) {
+					assert.Equal(t, tt.wantField, validationErr.Field)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, models.ReservationStatusBooked, created.Status)
+			assert.Len(t, created.StationNumbers, len(slices.Compact(slices.Clone(tt.req.StationNumbers))))
+		})
+	}
+
+	t.Run("banned member", func(t *testing.T) {
+		service := NewReservationService(newMockReservationRepository(), newMockStationRepository(), newReservationProfiles(), &mockAuditRecorder{})
+		_, err := service.CreateReservation(context.Background(), &models.CreateReservationRequest{
+			StationNumbers: []int{1}, StudentNumbers: []string{"34567890"}, StartsAt: start, EndsAt: start.Add(time.Hour),
+		})
+		var forbiddenErr *errors.ForbiddenError
+		assert.True(t, goerrors.As(err, &forbiddenErr), "expected ForbiddenError, got %v", err)
+	})
+
+	t.Run("station under maintenance", func(t *testing.T) {
+		stations := newMockStationRepository()
+		stations.stations[1].Status = models.StationStatusMaintenance
+		service := NewReservationService(newMockReservationRepository(), stations, newReservationProfiles(), &mockAuditRecorder{})
+		_, err := service.CreateReservation(context.Background(), &models.CreateReservationRequest{
+			StationNumbers: []int{1}, StudentNumbers: []string{"12345678"}, StartsAt: start, EndsAt: start.Add(time.Hour),
+		})
+		var conflictErr *errors.ConflictError
+		assert.True(t, goerrors.As(err, &conflictErr), "expected ConflictError, got %v", err)
+	})
+}
+
+func TestListReservations(t *testing.T) {
+	service := NewReservationService(newMockReservationRepository(), newMockStationRepository(), newReservationProfiles(), &mockAuditRecorder{})
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		filter    models.ReservationFilter
+		wantField string
+	}{
+		{"first page", models.ReservationFilter{Page: 1, Limit: 20}, ""},
+		{"page too low", models.ReservationFilter{Page: 0, Limit: 20}, "page"},
+		{"limit too low", models.ReservationFilter{Page: 1, Limit: 0}, "limit"},
+		{"limit too high", models.ReservationFilter{Page: 1, Limit: MaxReservationsPerPage + 1}, "limit"},
+		{"bad status", models.ReservationFilter{Page: 1, Limit: 20, Status: "lost"}, "status"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.ListReservations(ctx, tt.filter)
+			if tt.wantField == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *errors.ValidationError
+			if assert.True(t, goerrors.As(err, &validationErr), "expected ValidationError, got %v", err) {
+				assert.Equal(t, tt.wantField, validationErr.Field)
+			}
+		})
+	}
+}
+
+func TestCancelReservation(t *testing.T) {
+	repo := newMockReservationRepository()
+	recorder := &mockAuditRecorder{}
+	service := NewReservationService(repo, newMockStationRepository(), newReservationProfiles(), recorder)
+	ctx := context.Background()
+	start := time.Now().Add(time.Hour)
+
+	req := &models.CreateReservationRequest{StationNumbers: []int{1}, StudentNumbers: []string{"12345678"}, StartsAt: start, EndsAt: start.Add(time.Hour)}
+	created, err := service.CreateReservation(ctx, req)
+	assert.NoError(t, err)
+
+	// The same slot is taken until the reservation is cancelled
+	req.StudentNumbers = []string{"23456789"}
+	_, err = service.CreateReservation(ctx, req)
+	var conflictErr *errors.ConflictError
+	assert.True(t, goerrors.As(err, &conflictErr), "expected ConflictError, got %v", err)
+
+	cancelled, err := service.CancelReservation(ctx, created.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ReservationStatusCancelled, cancelled.Status)
+
+	_, err = service.CancelReservation(ctx, created.ID)
+	assert.True(t, goerrors.As(err, &conflictErr), "expected ConflictError cancelling twice, got %v", err)
+
+	_, err = service.CreateReservation(ctx, req)
+	assert.NoError(t, err)
+
+	actions := []string{}
+	for _, entry := range recorder.entries {
+		actions = append(actions, entry.Action)
+	}
+	assert.Equal(t, []string{AuditReservationCreate, AuditReservationCancel, AuditReservationCreate}, actions)
+}
+
+func TestReleaseNoShows(t *testing.T) {
+	now := time.Now()
+	repo := &mockReservationRepository{
+		reservations: []*models.Reservation{
+			// Past the grace period
+			{ID: "1", StationNumbers: []int{1}, StudentNumbers: []string{"12345678"}, StartsAt: now.Add(-20 * time.Minute), EndsAt: now.Add(time.Hour), Status: models.ReservationStatusBooked},
+			// Within the grace period
+			{ID: "2", StationNumbers: []int{2}, StudentNumbers: []string{"12345678"}, StartsAt: now.Add(-5 * time.Minute), EndsAt: now.Add(time.Hour), Status: models.ReservationStatusBooked},
+			{ID: "3", StationNumbers: []int{3}, StudentNumbers: []string{"23456789"}, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour), Status: models.ReservationStatusCheckedIn},
+		},
+	}
+	recorder := &mockAuditRecorder{}
+	service := NewReservationServiceWithConfig(repo, newMockStationRepository(), newReservationProfiles(), recorder, DefaultReservationServiceConfig())
+
+	released, err := service.ReleaseNoShows(context.Background(), now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, released)
+	assert.Equal(t, models.ReservationStatusNoShow, repo.reservations[0].Status)
+	assert.Equal(t, models.ReservationStatusBooked, repo.reservations[1].Status)
+	assert.Equal(t, models.ReservationStatusCheckedIn, repo.reservations[2].Status)
+	if assert.Len(t, recorder.entries, 1) {
+		assert.Equal(t, AuditReservationNoShow, recorder.entries[0].Action)
+	}
+}
+
+func TestStartActivityReservation(t *testing.T) {
+	now := time.Now()
+	team := "UBC Overwatch"
+	newService := func() (GamerActivityService, *mockReservationRepository) {
+		reservations := &mockReservationRepository{
+			reservations: []*models.Reservation{
+				// Station 1 is reserved for 12345678 right now
+				{ID: "1", StationNumbers: []int{1}, StudentNumbers: []string{"12345678"}, StartsAt: now.Add(-5 * time.Minute), EndsAt: now.Add(time.Hour), Status: models.ReservationStatusBooked},
+				// Station 2 is reserved for a team in half an hour
+				{ID: "2", StationNumbers: []int{2}, StudentNumbers: []string{"12345678"}, TeamName: &team, StartsAt: now.Add(30 * time.Minute), EndsAt: now.Add(2 * time.Hour), Status: models.ReservationStatusBooked},
+			},
+		}
+		service := NewGamerActivityService(&mockGamerActivityRepository{}, newReservationProfiles(), newMockStationRepository(), reservations, &mockAuditRecorder{})
+		return service, reservations
+	}
+	ctx := context.Background()
+
+	t.Run("holder checks in", func(t *testing.T) {
+		service, reservations := newService()
+		_, err := service.StartActivity(ctx, &models.CreateActivityRequest{StudentNumber: "12345678", PCNumber: 1, Game: "Valorant"})
+		assert.NoError(t, err)
+		assert.Equal(t, models.ReservationStatusCheckedIn, reservations.reservations[0].Status)
+	})
+
+	t.Run("someone else is turned away", func(t *testing.T) {
+		service, _ := newService()
+		_, err := service.StartActivity(ctx, &models.CreateActivityRequest{StudentNumber: "23456789", PCNumber: 1, Game: "Valorant"})
+		var conflictErr *errors.ConflictError
+		assert.True(t, goerrors.As(err, &conflictErr), "expected ConflictError, got %v", err)
+	})
+
+	t.Run("session runs out when the reservation begins", func(t *testing.T) {
+		service, reservations := newService()
+		created, err := service.StartActivity(ctx, &models.CreateActivityRequest{StudentNumber: "23456789", PCNumber: 2, Game: "Valorant"})
+		assert.NoError(t, err)
+		if assert.NotNil(t, created.ExpiresAt) {
+			assert.True(t, created.ExpiresAt.Equal(reservations.reservations[1].StartsAt), "expected expires_at %v, got %v", reservations.reservations[1].StartsAt, created.ExpiresAt)
+		}
+		assert.Equal(t, models.ReservationStatusBooked, reservations.reservations[1].Status)
+	})
+
+	t.Run("holder's later reservation stays booked", func(t *testing.T) {
+		service, reservations := newService()
+		created, err := service.StartActivity(ctx, &models.CreateActivityRequest{StudentNumber: "12345678", PCNumber: 2, Game: "Valorant"})
+		assert.NoError(t, err)
+		assert.Equal(t, models.ReservationStatusBooked, reservations.reservations[1].Status)
+		assert.Nil(t, reservations.reservations[1].CheckedInAt)
+		if assert.NotNil(t, created.ExpiresAt) {
+			assert.WithinDuration(t, now.Add(2*time.Hour), *created.ExpiresAt, time.Minute)
+		}
+	})
+
+	t.Run("free station", func(t *testing.T) {
+		service, _ := newService()
+		created, err := service.StartActivity(ctx, &models.CreateActivityRequest{StudentNumber: "23456789", PCNumber: 3, Game: "Valorant"})
+		assert.NoError(t, err)
+		if assert.NotNil(t, created.ExpiresAt) {
+			assert.WithinDuration(t, now.Add(2*time.Hour), *created.ExpiresAt, time.Minute)
+		}
+	})
+}
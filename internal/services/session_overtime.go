@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+// applySessionLimit fills in when an active session runs out, how long is
+// left and whether it has run over. Sessions started before their expiry was
+// stored run out after their tier's session length.
+func applySessionLimit(session *models.GamerActivity, now time.Time) {
+	if session.ExpiresAt == nil {
+		tier, err := models.NewMembershipTier(session.MembershipTier)
+		if err != nil || tier.GetSessionDurationMs() <= 0 {
+			return
+		}
+		expiresAt := session.StartedAt.Add(time.Duration(tier.GetSessionDurationMs()) * time.Millisecond)
+		session.ExpiresAt = &expiresAt
+	}
+
+	expiresAt := *session.ExpiresAt
+	remaining := max(expiresAt.Sub(now).Milliseconds(), 0)
+	overdue := now.After(expiresAt)
+
+	session.TimeRemainingMs = &remaining
+	session.Overdue = &overdue
+}
+
+// ReapOverdueSessions acts on sessions that have run past their tier's
+// allowance by more than the grace period. They are flagged once, or ended
+// by the system when AutoEndOverdue is set. It returns how many
+// sessions were acted on.
+func (s *gamerActivityService) ReapOverdueSessions(ctx context.Context, now time.Time) (int, error) {
+	sessions, err := s.activityRepo.GetActiveSessions(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	reaped := 0
+	for i := range sessions {
+		session := &sessions[i]
+		applySessionLimit(session, now)
+		if session.ExpiresAt == nil || !now.After(session.ExpiresAt.Add(s.config.OvertimeGrace)) {
+			continue
+		}
+
+		if s.config.AutoEndOverdue {
+			ended, err := s.activityRepo.EndOverdue(ctx, session.ID, now)
+			if err != nil {
+				return reaped, err
+			}
+			if ended == nil {
+				continue
+			}
+			s.audit.Record(ctx, AuditSessionEnd, "session", ended.ID, session, ended)
+			reaped++
+			continue
+		}
+
+		if session.OverdueFlaggedAt != nil {
+			continue
+		}
+		flagged, err := s.activityRepo.FlagOverdue(ctx, session.ID, now)
+		if err != nil {
+			return reaped, err
+		}
+		if !flagged {
+			continue
+		}
+		before := *session
+		session.OverdueFlaggedAt = &now
+		s.audit.Record(ctx, AuditSessionOverdue, "session", session.ID, &before, session)
+		reaped++
+	}
+	return reaped, nil
+}
+
+// RunOvertimeReaper reaps overdue sessions every ReapInterval until ctx is
+// cancelled
+func (s *gamerActivityService) RunOvertimeReaper(ctx context.Context) {
+	interval := s.config.ReapInterval
+	if interval <= 0 {
+		interval = DefaultGamerActivityServiceConfig().ReapInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reaped, err := s.ReapOverdueSessions(ctx, time.Now())
+			if err != nil {
+				log.Printf("failed to reap overdue sessions: %v", err)
+			}
+			if reaped > 0 {
+				log.Printf("reaped %d overdue sessions", reaped)
+			}
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+func TestApplySessionLimit(t *testing.T) {
+	start := time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name          string
+		tier          int
+		now           time.Time
+		wantExpiresAt time.Time
+		wantRemaining int64
+		wantOverdue   bool
+	}{
+		{"tier 1 in time", 1, start.Add(15 * time.Minute), start.Add(time.Hour), 45 * 60 * 1000, false},
+		{"tier 1 over", 1, start.Add(90 * time.Minute), start.Add(time.Hour), 0, true},
+		{"tier 2", 2, start.Add(90 * time.Minute), start.Add(2 * time.Hour), 30 * 60 * 1000, false},
+		{"premier", 3, start.Add(90 * time.Minute), start.Add(5 * time.Hour), 210 * 60 * 1000, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := models.GamerActivity{MembershipTier: tt.tier, StartedAt: start}
+			applySessionLimit(&session, tt.now)
+			if session.ExpiresAt == nil || !session.ExpiresAt.Equal(tt.wantExpiresAt) {
+				t.Errorf("expires_at = %v, want %v", session.ExpiresAt, tt.wantExpiresAt)
+			}
+			if session.TimeRemainingMs == nil || *session.TimeRemainingMs != tt.wantRemaining {
+				t.Errorf("time_remaining_ms = %v, want %d", session.TimeRemainingMs, tt.wantRemaining)
+			}
+			if session.Overdue == nil || *session.Overdue != tt.wantOverdue {
+				t.Errorf("overdue = %v, want %t", session.Overdue, tt.wantOverdue)
+			}
+		})
+	}
+
+	t.Run("no membership has no limit", func(t *testing.T) {
+		session := models.GamerActivity{MembershipTier: 0, StartedAt: start}
+		applySessionLimit(&session, start.Add(10*time.Hour))
+		if session.ExpiresAt != nil || session.TimeRemainingMs != nil || session.Overdue != nil {
+			t.Errorf("expected no limit, got %+v", session)
+		}
+	})
+}
+
+func TestReapOverdueSessions(t *testing.T) {
+	now := time.Now()
+	newRepo := func() *mockGamerActivityRepository {
+		return &mockGamerActivityRepository{
+			activities: []models.GamerActivity{
+				// Past the hour and the grace period
+				{ID: "1", StudentNumber: "12345678", PCNumber: 1, MembershipTier: 1, StartedAt: now.Add(-80 * time.Minute)},
+				// Past the hour but within the grace period
+				{ID: "2", StudentNumber: "23456789", PCNumber: 2, MembershipTier: 1, StartedAt: now.Add(-65 * time.Minute)},
+				{ID: "3", StudentNumber: "34567890", PCNumber: 3, MembershipTier: 2, StartedAt: now.Add(-80 * time.Minute)},
+				{ID: "4", StudentNumber: "45678901", PCNumber: 4, MembershipTier: 0, StartedAt: now.Add(-10 * time.Hour)},
+			},
+		}
+	}
+	profileRepo := &mockGamerProfileRepository{profiles: make(map[string]*models.GamerProfile)}
+
+	t.Run("flag", func(t *testing.T) {
+		activityRepo := newRepo()
+		recorder := &mockAuditRecorder{}
+		service := NewGamerActivityServiceWithConfig(activityRepo, profileRepo, newMockStationRepository(), newMockReservationRepository(), recorder, DefaultGamerActivityServiceConfig())
+
+		for range 2 {
+			if _, err := service.ReapOverdueSessions(context.Background(), now); err != nil {
+				t.Fatalf("ReapOverdueSessions() error = %v", err)
+			}
+		}
+		if activityRepo.activities[0].OverdueFlaggedAt == nil || activityRepo.activities[0].EndedAt != nil {
+			t.Errorf("expected the overdue session to be flagged and left running, got %+v", activityRepo.activities[0])
+		}
+		for _, a := range activityRepo.activities[1:] {
+			if a.OverdueFlaggedAt != nil {
+				t.Errorf("expected session %s not to be flagged", a.ID)
+			}
+		}
+		if len(recorder.entries) != 1 || recorder.entries[0].Action != AuditSessionOverdue {
+			t.Errorf("expected the session to be flagged once, got %+v", recorder.entries)
+		}
+	})
+
+	t.Run("auto end", func(t *testing.T) {
+		activityRepo := newRepo()
+		recorder := &mockAuditRecorder{}
+		config := DefaultGamerActivityServiceConfig()
+		config.AutoEndOverdue = true
+		service := NewGamerActivityServiceWithConfig(activityRepo, profileRepo, newMockStationRepository(), newMockReservationRepository(), recorder, config)
+
+		reaped, err := service.ReapOverdueSessions(context.Background(), now)
+		if err != nil {
+			t.Fatalf("ReapOverdueSessions() error = %v", err)
+		}
+		if reaped != 1 {
+			t.Errorf("expected 1 session to be ended, got %d", reaped)
+		}
+		ended := activityRepo.activities[0]
+		if ended.EndedAt == nil || !ended.EndedBySystem {
+			t.Errorf("expected the session to be ended by the system, got %+v", ended)
+		}
+		if ended.ExecID != nil || ended.ExecName != nil {
+			t.Errorf("expected no exec, got %+v", ended)
+		}
+		if len(recorder.entries) != 1 || recorder.entries[0].Action != AuditSessionEnd {
+			t.Errorf("expected the end to be audited, got %+v", recorder.entries)
+		}
+	})
+
+	t.Run("active sessions", func(t *testing.T) {
+		service := NewGamerActivityService(newRepo(), profileRepo, newMockStationRepository(), newMockReservationRepository(), &mockAuditRecorder{})
+		sessions, err := service.GetActiveSessions(context.Background())
+		if err != nil {
+			t.Fatalf("GetActiveSessions() error = %v", err)
+		}
+		if sessions[0].Overdue == nil || !*sessions[0].Overdue {
+			t.Errorf("expected session 1 to be overdue, got %v", sessions[0].Overdue)
+		}
+		if sessions[2].Overdue == nil || *sessions[2].Overdue {
+			t.Errorf("expected session 3 to be in time, got %v", sessions[2].Overdue)
+		}
+	})
+}
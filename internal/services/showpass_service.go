@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+// ShowpassSignatureHeader carries the signature of a Showpass webhook
+// delivery, the hex HMAC-SHA256 of the body keyed with the webhook secret
+const ShowpassSignatureHeader = "X-Showpass-Signature"
+
+// ShowpassAppName is who memberships from the webhook are recorded by
+const ShowpassAppName = "showpass"
+
+type ShowpassService interface {
+	HandleWebhook(ctx context.Context, body []byte, signature string) (*models.ShowpassWebhookResult, error)
+}
+
+type ShowpassServiceConfig struct {
+	// WebhookSecret signs webhook deliveries. If empty every delivery is
+	// rejected.
+	WebhookSecret []byte
+	// Tiers maps ticket types to membership tiers. Tickets of other types
+	// are ignored.
+	Tiers map[string]int
+}
+
+func DefaultShowpassServiceConfig() ShowpassServiceConfig {
+	return ShowpassServiceConfig{
+		Tiers: models.ShowpassTicketTiers(),
+	}
+}
+
+type showpassService struct {
+	profiles GamerProfileService
+	config   ShowpassServiceConfig
+}
+
+func NewShowpassService(profiles GamerProfileService, config ShowpassServiceConfig) ShowpassService {
+	if len(config.WebhookSecret) == 0 {
+		log.Printf("no showpass webhook secret configured, showpass webhooks will be rejected")
+	}
+	return &showpassService{profiles: profiles, config: config}
+}
+
+// HandleWebhook verifies a webhook delivery and provisions the membership
+// that was bought. Deliveries are retried until they succeed, so a purchase
+// that was already recorded is reported as a duplicate rather than an error.
+func (s *showpassService) HandleWebhook(ctx context.Context, body []byte, signature string) (*models.ShowpassWebhookResult, error) {
+	if !VerifyShowpassSignature(s.config.WebhookSecret, body, signature) {
+		return nil, errors.NewUnauthorizedError("invalid showpass signature")
+	}
+
+	var webhook models.ShowpassWebhook
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		return nil, errors.NewValidationError("body", "must be a showpass webhook")
+	}
+
+	if webhook.Event != models.ShowpassEventPurchase {
+		return &models.ShowpassWebhookResult{Status: models.ShowpassWebhookIgnored}, nil
+	}
+
+	purchase := webhook.Purchase
+	tier, ok := s.tierOf(purchase.TicketType)
+	if !ok {
+		return &models.ShowpassWebhookResult{Status: models.ShowpassWebhookIgnored}, nil
+	}
+
+	purchasedAt := time.Now()
+	if purchase.PurchasedAt != nil {
+		purchasedAt = *purchase.PurchasedAt
+	}
+
+	ctx = auth.WithAppName(ctx, ShowpassAppName)
+	membership, created, err := s.profiles.ProvisionMembership(ctx, &models.ProvisionMembershipRequest{
+		ExternalRef:    strings.TrimSpace(purchase.ID),
+		StudentNumber:  strings.TrimSpace(purchase.StudentNumber),
+		FirstName:      strings.TrimSpace(purchase.FirstName),
+		LastName:       strings.TrimSpace(purchase.LastName),
+		MembershipTier: tier,
+		Source:         models.MembershipSourceShowpass,
+		PurchasedAt:    purchasedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	status := models.ShowpassWebhookProvisioned
+	if !created {
+		status = models.ShowpassWebhookDuplicate
+	}
+	return &models.ShowpassWebhookResult{Status: status, Membership: membership}, nil
+}
+
+func (s *showpassService) tierOf(ticketType string) (int, bool) {
+	for name, tier := range s.config.Tiers {
+		if strings.EqualFold(name, strings.TrimSpace(ticketType)) {
+			return tier, true
+		}
+	}
+	return 0, false
+}
+
+// SignShowpassPayload returns the signature Showpass sends with body
+func SignShowpassPayload(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyShowpassSignature checks signature against body. Nothing verifies
+// without a secret.
+func VerifyShowpassSignature(secret, body []byte, signature string) bool {
+	if len(secret) == 0 {
+		return false
+	}
+
+	got, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signature), "sha256="))
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	goerrors "errors"
+	"testing"
+
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/interfaces/gamer"
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+var testShowpassSecret = []byte("showpass-secret")
+
+func newShowpassTestService() (*mockGamerProfileRepository, *mockAuditRecorder, ShowpassService) {
+	repo := &mockGamerProfileRepository{
+		profiles: map[string]*models.GamerProfile{
+			"22222222": {StudentNumber: "22222222", FirstName: "Johnny", LastName: "Doe"},
+		},
+	}
+	audit := &mockAuditRecorder{}
+	config := DefaultShowpassServiceConfig()
+	config.WebhookSecret = testShowpassSecret
+	return repo, audit, NewShowpassService(NewGamerProfileService(repo, audit), config)
+}
+
+func deliver(service ShowpassService, body string) (*models.ShowpassWebhookResult, error) {
+	signature := SignShowpassPayload(testShowpassSecret, []byte(body))
+	return service.HandleWebhook(context.Background(), []byte(body), signature)
+}
+
+func TestShowpassWebhookSignature(t *testing.T) {
+	body := []byte(`{"event":"purchase.completed"}`)
+	signature := SignShowpassPayload(testShowpassSecret, body)
+
+	tests := []struct {
+		name      string
+		secret    []byte
+		body      []byte
+		signature string
+		want      bool
+	}{
+		{"valid", testShowpassSecret, body, signature, true},
+		{"valid with prefix", testShowpassSecret, body, "sha256=" + signature, true},
+		{"tampered body", testShowpassSecret, []byte(`{"event":"refund"}`), signature, false},
+		{"wrong secret", []byte("other"), body, signature, false},
+		{"no secret", nil, body, SignShowpassPayload(nil, body), false},
+		{"not hex", testShowpassSecret, body, "not-a-signature", false},
+		{"missing", testShowpassSecret, body, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyShowpassSignature(tt.secret, tt.body, tt.signature); got != tt.want {
+				t.Errorf("VerifyShowpassSignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	_, _, service := newShowpassTestService()
+	_, err := service.HandleWebhook(context.Background(), body, "deadbeef")
+	var unauthorizedErr *errors.UnauthorizedError
+	if !goerrors.As(err, &unauthorizedErr) {
+		t.Errorf("expected an unsigned delivery to be unauthorized, got %v", err)
+	}
+}
+
+func TestShowpassWebhookProvisionsMembership(t *testing.T) {
+	repo, audit, service := newShowpassTestService()
+	body := `{"event":"purchase.completed","purchase":{"id":"SP-1","ticket_type":"tier 2 membership","student_number":"11111111","first_name":"Jane","last_name":"Smith","purchased_at":"2026-09-01T10:00:00Z"}}`
+
+	result, err := deliver(service, body)
+	if err != nil {
+		t.Fatalf("HandleWebhook() error = %v", err)
+	}
+	if result.Status != models.ShowpassWebhookProvisioned {
+		t.Errorf("expected provisioned, got %s", result.Status)
+	}
+
+	profile := repo.profiles["11111111"]
+	if profile == nil || profile.FirstName != "Jane" || profile.MembershipTier != 2 {
+		t.Fatalf("expected a tier 2 profile for the new member, got %+v", profile)
+	}
+
+	m := result.Membership
+	if m.Source != models.MembershipSourceShowpass || m.ExternalRef == nil || *m.ExternalRef != "SP-1" {
+		t.Errorf("unexpected membership %+v", m)
+	}
+	if m.RecordedByApp == nil || *m.RecordedByApp != ShowpassAppName {
+		t.Errorf("expected the membership to be recorded by showpass, got %v", m.RecordedByApp)
+	}
+	if m.PurchasedAt.Format("2006-01-02") != "2026-09-01" {
+		t.Errorf("expected the showpass purchase time, got %v", m.PurchasedAt)
+	}
+	if len(audit.entries) != 2 {
+		t.Errorf("expected the profile and membership to be audited, got %d entries", len(audit.entries))
+	}
+
+	t.Run("redelivery is a duplicate", func(t *testing.T) {
+		result, err := deliver(service, body)
+		if err != nil {
+			t.Fatalf("HandleWebhook() error = %v", err)
+		}
+		if result.Status != models.ShowpassWebhookDuplicate || result.Membership.ID != m.ID {
+			t.Errorf("expected the original membership as a duplicate, got %+v", result)
+		}
+		if len(repo.memberships) != 1 || len(audit.entries) != 2 {
+			t.Error("expected a redelivery to record nothing")
+		}
+	})
+}
+
+func TestShowpassWebhookKeepsExistingMember(t *testing.T) {
+	repo, _, service := newShowpassTestService()
+	body := `{"event":"purchase.completed","purchase":{"id":"SP-2","ticket_type":"Premier Membership","student_number":"22222222","first_name":"JOHNNY","last_name":"doe"}}`
+
+	if _, err := deliver(service, body); err != nil {
+		t.Fatalf("HandleWebhook() error = %v", err)
+	}
+
+	profile := repo.profiles["22222222"]
+	if profile.FirstName != "Johnny" || profile.LastName != "Doe" {
+		t.Errorf("expected the existing name to be kept, got %s %s", profile.FirstName, profile.LastName)
+	}
+	if profile.MembershipTier != 3 {
+		t.Errorf("expected the member to become premier, got tier %d", profile.MembershipTier)
+	}
+}
+
+func TestShowpassWebhookRejectsAnotherName(t *testing.T) {
+	repo, _, service := newShowpassTestService()
+	body := `{"event":"purchase.completed","purchase":{"id":"SP-3","ticket_type":"Premier Membership","student_number":"22222222","first_name":"Someone","last_name":"Else"}}`
+
+	_, err := deliver(service, body)
+	var conflictErr *errors.ConflictError
+	if !goerrors.As(err, &conflictErr) {
+		t.Fatalf("expected a ConflictError, got %v", err)
+	}
+	if len(repo.memberships) != 0 || repo.profiles["22222222"].FirstName != "Johnny" {
+		t.Error("expected nothing to be recorded")
+	}
+}
+
+func TestShowpassWebhookLeavesDeletedMemberDeleted(t *testing.T) {
+	repo, _, service := newShowpassTestService()
+	repo.Delete(context.Background(), "22222222")
+	body := `{"event":"purchase.completed","purchase":{"id":"SP-4","ticket_type":"Tier 1 Membership","student_number":"22222222","first_name":"Johnny","last_name":"Doe"}}`
+
+	result, err := deliver(service, body)
+	if err != nil {
+		t.Fatalf("HandleWebhook() error = %v", err)
+	}
+	if result.Status != models.ShowpassWebhookProvisioned {
+		t.Errorf("expected provisioned, got %s", result.Status)
+	}
+	if _, exists := repo.profiles["22222222"]; exists {
+		t.Error("expected the profile to stay deleted")
+	}
+}
+
+// racingProfileRepository hides a purchase from the lookup, as if another
+// delivery recorded it after the lookup, and then fails the insert
+type racingProfileRepository struct {
+	*mockGamerProfileRepository
+	lookups int
+}
+
+func (r *racingProfileRepository) GetMembershipByExternalRef(ctx context.Context, externalRef string) (*models.Membership, error) {
+	r.lookups++
+	if r.lookups == 1 {
+		return nil, nil
+	}
+	return r.mockGamerProfileRepository.GetMembershipByExternalRef(ctx, externalRef)
+}
+
+func (r *racingProfileRepository) AddMembership(ctx context.Context, membership *models.Membership) (*models.Membership, error) {
+	return nil, errors.NewConflictError("purchase " + *membership.ExternalRef + " is already recorded")
+}
+
+func (r *racingProfileRepository) WithTx(ctx context.Context, fn func(repo gamer.GamerProfileRepository) error) error {
+	return fn(r)
+}
+
+func TestShowpassWebhookConcurrentDeliveryIsDuplicate(t *testing.T) {
+	mock, _, _ := newShowpassTestService()
+	ref := "SP-5"
+	mock.memberships = append(mock.memberships, models.Membership{ID: "membership-1", StudentNumber: "22222222", ExternalRef: &ref})
+	repo := &racingProfileRepository{mockGamerProfileRepository: mock}
+
+	config := DefaultShowpassServiceConfig()
+	config.WebhookSecret = testShowpassSecret
+	service := NewShowpassService(NewGamerProfileService(repo, &mockAuditRecorder{}), config)
+
+	body := `{"event":"purchase.completed","purchase":{"id":"SP-5","ticket_type":"Tier 1 Membership","student_number":"22222222","first_name":"Johnny","last_name":"Doe"}}`
+	result, err := deliver(service, body)
+	if err != nil {
+		t.Fatalf("HandleWebhook() error = %v", err)
+	}
+	if result.Status != models.ShowpassWebhookDuplicate || result.Membership.ID != "membership-1" {
+		t.Errorf("expected the recorded membership as a duplicate, got %+v", result)
+	}
+}
+
+func TestShowpassWebhookIgnoresOtherPurchases(t *testing.T) {
+	repo, _, service := newShowpassTestService()
+
+	bodies := []string{
+		`{"event":"purchase.refunded","purchase":{"id":"SP-3","ticket_type":"Tier 1 Membership","student_number":"11111111","first_name":"Jane","last_name":"Smith"}}`,
+		`{"event":"purchase.completed","purchase":{"id":"SP-4","ticket_type":"LAN Party Entry","student_number":"11111111","first_name":"Jane","last_name":"Smith"}}`,
+	}
+	for _, body := range bodies {
+		result, err := deliver(service, body)
+		if err != nil {
+			t.Fatalf("HandleWebhook() error = %v", err)
+		}
+		if result.Status != models.ShowpassWebhookIgnored {
+			t.Errorf("expected ignored, got %s", result.Status)
+		}
+	}
+	if len(repo.memberships) != 0 {
+		t.Error("expected no memberships to be recorded")
+	}
+
+	_, err := deliver(service, `{"event":"purchase.completed","purchase":{"id":"SP-5","ticket_type":"Tier 1 Membership","student_number":"123","first_name":"Jane","last_name":"Smith"}}`)
+	var validationErr *errors.ValidationError
+	if !goerrors.As(err, &validationErr) {
+		t.Errorf("expected an invalid student number to be rejected, got %v", err)
+	}
+}
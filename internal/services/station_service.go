@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/interfaces/station"
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+const (
+	AuditStationCreate = "station.create"
+	AuditStationUpdate = "station.update"
+	AuditStationDelete = "station.delete"
+)
+
+type StationService interface {
+	CreateStation(ctx context.Context, req *models.CreateStationRequest) (*models.Station, error)
+	GetStation(ctx context.Context, number int) (*models.Station, error)
+	ListStations(ctx context.Context) ([]models.Station, error)
+	UpdateStation(ctx context.Context, number int, req *models.UpdateStationRequest) (*models.Station, error)
+	SetStationStatus(ctx context.Context, number int, req *models.UpdateStationStatusRequest) (*models.Station, error)
+	DeleteStation(ctx context.Context, number int) error
+	GetStationOverview(ctx context.Context) ([]models.StationView, error)
+}
+
+type stationService struct {
+	repo       station.StationRepository
+	activities GamerActivityService
+	audit      AuditRecorder
+}
+
+func NewStationService(repo station.StationRepository, activities GamerActivityService, audit AuditRecorder) StationService {
+	return &stationService{repo: repo, activities: activities, audit: audit}
+}
+
+func (s *stationService) CreateStation(ctx context.Context, req *models.CreateStationRequest) (*models.Station, error) {
+	if req.Number < 1 {
+		return nil, errors.NewValidationError("number", "must be >= 1")
+	}
+
+	st := &models.Station{
+		Number:       req.Number,
+		Zone:         trimOptional(req.Zone),
+		Type:         req.Type,
+		Specs:        req.Specs,
+		Status:       req.Status,
+		StatusReason: trimOptional(req.StatusReason),
+	}
+	if st.Type == "" {
+		st.Type = models.StationTypePC
+	}
+	if st.Status == "" {
+		st.Status = models.StationStatusAvailable
+	}
+	if err := validateStation(st); err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.Create(ctx, st)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditStationCreate, "station", strconv.Itoa(created.Number), nil, created)
+	return created, nil
+}
+
+func (s *stationService) GetStation(ctx context.Context, number int) (*models.Station, error) {
+	return s.repo.Get(ctx, number)
+}
+
+func (s *stationService) ListStations(ctx context.Context) ([]models.Station, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *stationService) UpdateStation(ctx context.Context, number int, req *models.UpdateStationRequest) (*models.Station, error) {
+	before, err := s.repo.Get(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+
+	st := *before
+	if req.Zone.Set {
+		st.Zone = trimOptional(req.Zone.Value)
+	}
+	if req.Type.Set {
+		if req.Type.Value == nil {
+			return nil, errors.NewValidationError("type", "can't be cleared")
+		}
+		st.Type = *req.Type.Value
+	}
+	if req.Specs.Set {
+		st.Specs = nil
+		if req.Specs.Value != nil {
+			st.Specs = *req.Specs.Value
+		}
+	}
+	if req.Status.Set {
+		if req.Status.Value == nil {
+			return nil, errors.NewValidationError("status", "can't be cleared")
+		}
+		st.Status = *req.Status.Value
+		if st.Status == models.StationStatusAvailable {
+			st.StatusReason = nil
+		}
+	}
+	if req.StatusReason.Set {
+		st.StatusReason = trimOptional(req.StatusReason.Value)
+	}
+	if err := validateStation(&st); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.repo.Update(ctx, &st)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, AuditStationUpdate, "station", strconv.Itoa(number), before, updated)
+	return updated, nil
+}
+
+// SetStationStatus switches a station between available and maintenance.
+// Retiring a station, or bringing a retired one back, changes the inventory
+// and is done through UpdateStation.
+func (s *stationService) SetStationStatus(ctx context.Context, number int, req *models.UpdateStationStatusRequest) (*models.Station, error) {
+	if req.Status == "" {
+		return nil, errors.NewValidationError("status", "is required")
+	}
+
+	current, err := s.repo.Get(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if req.Status == models.StationStatusRetired || current.Status == models.StationStatusRetired {
+		return nil, errors.NewForbiddenError(fmt.Sprintf("station %d can only be retired or brought back with an admin key", number))
+	}
+
+	return s.UpdateStation(ctx, number, &models.UpdateStationRequest{
+		Status:       models.PatchField[models.StationStatus]{Set: true, Value: &req.Status},
+		StatusReason: models.PatchField[string]{Set: req.StatusReason != nil, Value: req.StatusReason},
+	})
+}
+
+// DeleteStation removes a station added by mistake. Stations that have been
+// played on are retired instead, so their sessions still make sense.
+func (s *stationService) DeleteStation(ctx context.Context, number int) error {
+	before, err := s.repo.Get(ctx, number)
+	if err != nil {
+		return err
+	}
+
+	used, err := s.repo.HasSessions(ctx, number)
+	if err != nil {
+		return err
+	}
+	if used {
+		return errors.NewConflictError(fmt.Sprintf("station %d has been played on, retire it instead", number))
+	}
+
+	if err := s.repo.Delete(ctx, number); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, AuditStationDelete, "station", strconv.Itoa(number), before, nil)
+	return nil
+}
+
+// GetStationOverview shows every station that isn't retired with whether it
+// is free, occupied or out of order. A station under maintenance that still
+// has someone on it shows as occupied.
+func (s *stationService) GetStationOverview(ctx context.Context) ([]models.StationView, error) {
+	stations, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.activities.GetActiveSessions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byStation := make(map[int]*models.GamerActivity, len(sessions))
+	for i := range sessions {
+		byStation[sessions[i].PCNumber] = &sessions[i]
+	}
+
+	overview := make([]models.StationView, 0, len(stations))
+	for _, st := range stations {
+		if st.Status == models.StationStatusRetired {
+			continue
+		}
+
+		view := models.StationView{Station: st, State: models.StationStateFree}
+		switch session := byStation[st.Number]; {
+		case session != nil:
+			view.State = models.StationStateOccupied
+			view.Session = session
+		case st.Status == models.StationStatusMaintenance:
+			view.State = models.StationStateOutOfOrder
+		}
+		overview = append(overview, view)
+	}
+	return overview, nil
+}
+
+func validateStation(st *models.Station) error {
+	if !st.Type.IsValid() {
+		return errors.NewValidationError("type", "must be pc, console or vr")
+	}
+	if !st.Status.IsValid() {
+		return errors.NewValidationError("status", "must be available, maintenance or retired")
+	}
+	if st.Specs == nil {
+		st.Specs = map[string]string{}
+	}
+	for key := range st.Specs {
+		if strings.TrimSpace(key) == "" {
+			return errors.NewValidationError("specs", "can't have a blank name")
+		}
+	}
+	return nil
+}
+
+// trimOptional trims an optional string, treating blank as not set
+func trimOptional(v *string) *string {
+	if v == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*v)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
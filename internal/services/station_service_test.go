@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	goerrors "errors"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ubcesports/echo-base/internal/errors"
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+type mockStationRepository struct {
+	stations map[int]*models.Station
+	played   map[int]bool
+}
+
+// newMockStationRepository has stations 1 to 20 available
+func newMockStationRepository() *mockStationRepository {
+	m := &mockStationRepository{stations: make(map[int]*models.Station), played: make(map[int]bool)}
+	for n := 1; n <= 20; n++ {
+		m.stations[n] = &models.Station{Number: n, Type: models.StationTypePC, Specs: map[string]string{}, Status: models.StationStatusAvailable}
+	}
+	return m
+}
+
+func (m *mockStationRepository) Create(ctx context.Context, s *models.Station) (*models.Station, error) {
+	if _, ok := m.stations[s.Number]; ok {
+		return nil, errors.NewConflictError("station " + strconv.Itoa(s.Number) + " already exists")
+	}
+	created := *s
+	m.stations[s.Number] = &created
+	return &created, nil
+}
+
+func (m *mockStationRepository) Get(ctx context.Context, number int) (*models.Station, error) {
+	s, ok := m.stations[number]
+	if !ok {
+		return nil, errors.NewNotFoundError("station", strconv.Itoa(number))
+	}
+	found := *s
+	return &found, nil
+}
+
+func (m *mockStationRepository) List(ctx context.Context) ([]models.Station, error) {
+	stations := make([]models.Station, 0, len(m.stations))
+	for _, s := range m.stations {
+		stations = append(stations, *s)
+	}
+	sort.Slice(stations, func(i, j int) bool { return stations[i].Number < stations[j].Number })
+	return stations, nil
+}
+
+func (m *mockStationRepository) Update(ctx context.Context, s *models.Station) (*models.Station, error) {
+	if _, ok := m.stations[s.Number]; !ok {
+		return nil, errors.NewNotFoundError("station", strconv.Itoa(s.Number))
+	}
+	updated := *s
+	m.stations[s.Number] = &updated
+	return &updated, nil
+}
+
+func (m *mockStationRepository) Delete(ctx context.Context, number int) error {
+	if _, ok := m.stations[number]; !ok {
+		return errors.NewNotFoundError("station", strconv.Itoa(number))
+	}
+	delete(m.stations, number)
+	return nil
+}
+
+func (m *mockStationRepository) HasSessions(ctx context.Context, number int) (bool, error) {
+	return m.played[number], nil
+}
+
+func TestCreateStation(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       models.CreateStationRequest
+		wantField string
+	}{
+		{"defaults", models.CreateStationRequest{Number: 21}, ""},
+		{"console", models.CreateStationRequest{Number: 22, Type: models.StationTypeConsole, Specs: map[string]string{"model": "PS5"}}, ""},
+		{"number too low", models.CreateStationRequest{Number: 0}, "number"},
+		{"bad type", models.CreateStationRequest{Number: 23, Type: "arcade"}, "type"},
+		{"bad status", models.CreateStationRequest{Number: 23, Status: "broken"}, "status"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewStationService(newMockStationRepository(), nil, &mockAuditRecorder{})
+			created, err := service.CreateStation(context.Background(), &tt.req)
+			if tt.wantField != "" {
+				var validationErr *errors.ValidationError
+				if assert.True(t, goerrors.As(err, &validationErr), "expected ValidationError, got %v", err) {
+					assert.Equal(t, tt.wantField, validationErr.Field)
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, models.StationStatusAvailable, created.Status)
+			assert.NotNil(t, created.Specs)
+			if tt.req.Type == "" {
+				assert.Equal(t, models.StationTypePC, created.Type)
+			}
+		})
+	}
+
+	t.Run("number taken", func(t *testing.T) {
+		service := NewStationService(newMockStationRepository(), nil, &mockAuditRecorder{})
+		_, err := service.CreateStation(context.Background(), &models.CreateStationRequest{Number: 1})
+		var conflictErr *errors.ConflictError
+		assert.True(t, goerrors.As(err, &conflictErr), "expected ConflictError, got %v", err)
+	})
+}
+
+func TestUpdateStation(t *testing.T) {
+	repo := newMockStationRepository()
+	recorder := &mockAuditRecorder{}
+	service := NewStationService(repo, nil, recorder)
+	ctx := context.Background()
+
+	maintenance := models.StationStatusMaintenance
+	reason := "  GPU fan is loud  "
+	updated, err := service.UpdateStation(ctx, 3, &models.UpdateStationRequest{
+		Status:       models.PatchField[models.StationStatus]{Set: true, Value: &maintenance},
+		StatusReason: models.PatchField[string]{Set: true, Value: &reason},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, models.StationStatusMaintenance, updated.Status)
+	if assert.NotNil(t, updated.StatusReason) {
+		assert.Equal(t, "GPU fan is loud", *updated.StatusReason)
+	}
+
+	available := models.StationStatusAvailable
+	updated, err = service.UpdateStation(ctx, 3, &models.UpdateStationRequest{
+		Status: models.PatchField[models.StationStatus]{Set: true, Value: &available},
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, updated.StatusReason, "expected the reason to be cleared once the station is available")
+
+	_, err = service.UpdateStation(ctx, 3, &models.UpdateStationRequest{
+		Type: models.PatchField[models.StationType]{Set: true},
+	})
+	var validationErr *errors.ValidationError
+	assert.True(t, goerrors.As(err, &validationErr), "expected ValidationError, got %v", err)
+
+	_, err = service.UpdateStation(ctx, 99, &models.UpdateStationRequest{})
+	var notFoundErr *errors.NotFoundError
+	assert.True(t, goerrors.As(err, &notFoundErr), "expected NotFoundError, got %v", err)
+
+	assert.Len(t, recorder.entries, 2)
+}
+
+func TestSetStationStatus(t *testing.T) {
+	repo := newMockStationRepository()
+	recorder := &mockAuditRecorder{}
+	service := NewStationService(repo, nil, recorder)
+	ctx := context.Background()
+
+	reason := "Sticky keyboard"
+	updated, err := service.SetStationStatus(ctx, 3, &models.UpdateStationStatusRequest{Status: models.StationStatusMaintenance, StatusReason: &reason})
+	assert.NoError(t, err)
+	assert.Equal(t, models.StationStatusMaintenance, updated.Status)
+
+	updated, err = service.SetStationStatus(ctx, 3, &models.UpdateStationStatusRequest{Status: models.StationStatusAvailable})
+	assert.NoError(t, err)
+	assert.Nil(t, updated.StatusReason)
+
+	// Retiring a station, or bringing it back, is an inventory change
+	var forbiddenErr *errors.ForbiddenError
+	_, err = service.SetStationStatus(ctx, 3, &models.UpdateStationStatusRequest{Status: models.StationStatusRetired})
+	assert.True(t, goerrors.As(err, &forbiddenErr), "expected ForbiddenError, got %v", err)
+
+	repo.stations[4].Status = models.StationStatusRetired
+	_, err = service.SetStationStatus(ctx, 4, &models.UpdateStationStatusRequest{Status: models.StationStatusAvailable})
+	assert.True(t, goerrors.As(err, &forbiddenErr), "expected ForbiddenError, got %v", err)
+	assert.Equal(t, models.StationStatusRetired, repo.stations[4].Status)
+
+	_, err = service.SetStationStatus(ctx, 3, &models.UpdateStationStatusRequest{})
+	var validationErr *errors.ValidationError
+	assert.True(t, goerrors.As(err, &validationErr), "expected ValidationError, got %v", err)
+
+	assert.Len(t, recorder.entries, 2)
+}
+
+func TestDeleteStation(t *testing.T) {
+	repo := newMockStationRepository()
+	repo.played[1] = true
+	service := NewStationService(repo, nil, &mockAuditRecorder{})
+	ctx := context.Background()
+
+	err := service.DeleteStation(ctx, 1)
+	var conflictErr *errors.ConflictError
+	assert.True(t, goerrors.As(err, &conflictErr), "expected ConflictError, got %v", err)
+	assert.Contains(t, repo.stations, 1)
+
+	assert.NoError(t, service.DeleteStation(ctx, 2))
+	assert.NotContains(t, repo.stations, 2)
+}
+
+func TestGetStationOverview(t *testing.T) {
+	repo := newMockStationRepository()
+	repo.stations[2].Status = models.StationStatusMaintenance
+	repo.stations[3].Status = models.StationStatusRetired
+	repo.stations[4].Status = models.StationStatusMaintenance
+
+	activityRepo := &mockGamerActivityRepository{
+		activities: []models.GamerActivity{
+			{ID: "1", StudentNumber: "12345678", PCNumber: 1, StartedAt: time.Now()},
+			// Someone is still finishing up on a station sent for maintenance
+			{ID: "2", StudentNumber: "23456789", PCNumber: 4, StartedAt: time.Now()},
+		},
+	}
+	activities := NewGamerActivityService(activityRepo, &mockGamerProfileRepository{}, repo, newMockReservationRepository(), &mockAuditRecorder{})
+	service := NewStationService(repo, activities, &mockAuditRecorder{})
+
+	overview, err := service.GetStationOverview(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, overview, 19, "expected retired stations to be left out")
+
+	states := make(map[int]models.StationState)
+	for _, view := range overview {
+		states[view.Number] = view.State
+		if view.State == models.StationStateOccupied {
+			assert.NotNil(t, view.Session)
+		}
+	}
+	assert.Equal(t, models.StationStateOccupied, states[1])
+	assert.Equal(t, models.StationStateOutOfOrder, states[2])
+	assert.Equal(t, models.StationStateOccupied, states[4])
+	assert.Equal(t, models.StationStateFree, states[5])
+}
+
+func TestStartActivityStation(t *testing.T) {
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	mockProfileRepo := &mockGamerProfileRepository{
+		profiles: map[string]*models.GamerProfile{
+			"12345678": {StudentNumber: "12345678", MembershipTier: 2, MembershipExpiryDate: &tomorrow},
+		},
+	}
+	stations := newMockStationRepository()
+	reason := "monitor cracked"
+	stations.stations[5].Status = models.StationStatusMaintenance
+	stations.stations[5].StatusReason = &reason
+	service := NewGamerActivityService(&mockGamerActivityRepository{}, mockProfileRepo, stations, newMockReservationRepository(), &mockAuditRecorder{})
+	ctx := context.Background()
+
+	_, err := service.StartActivity(ctx, &models.CreateActivityRequest{StudentNumber: "12345678", PCNumber: 99, Game: "Valorant"})
+	var validationErr *errors.ValidationError
+	if assert.True(t, goerrors.As(err, &validationErr), "expected ValidationError, got %v", err) {
+		assert.Equal(t, "pc_number", validationErr.Field)
+	}
+
+	_, err = service.StartActivity(ctx, &models.CreateActivityRequest{StudentNumber: "12345678", PCNumber: 5, Game: "Valorant"})
+	var conflictErr *errors.ConflictError
+	if assert.True(t, goerrors.As(err, &conflictErr), "expected ConflictError, got %v", err) {
+		assert.Contains(t, conflictErr.Error(), reason)
+	}
+
+	_, err = service.StartActivity(ctx, &models.CreateActivityRequest{StudentNumber: "12345678", PCNumber: 6, Game: "Valorant"})
+	assert.NoError(t, err)
+}
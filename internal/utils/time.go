@@ -2,8 +2,30 @@ package utils
 
 import (
 	"time"
+	// Embedded so the lounge time zone loads in containers without tzdata
+	_ "time/tzdata"
 )
 
+// LoungeLocation is the lounge's time zone. Daily play limits reset at
+// midnight here.
+var LoungeLocation = mustLoadLocation("America/Los_Angeles")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// LoungeDay returns the start and end of the lounge's day containing t. Days
+// are not always 24 hours long because of daylight saving time.
+func LoungeDay(t time.Time) (time.Time, time.Time) {
+	local := t.In(LoungeLocation)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, LoungeLocation)
+	return start, start.AddDate(0, 0, 1)
+}
+
 // GetNextMayFirst calculates the next May 1st
 // If current month >= May, returns May 1st of next year
 // Otherwise returns May 1st of current year
@@ -0,0 +1,8 @@
+-- +migrate Up
+ALTER TABLE application ADD COLUMN scopes TEXT[] NOT NULL DEFAULT '{}';
+
+-- Keys issued before scopes existed could call every route, so keep them working
+UPDATE application SET scopes = ARRAY['admin'];
+
+-- +migrate Down
+ALTER TABLE application DROP COLUMN scopes;
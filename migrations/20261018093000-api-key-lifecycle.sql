@@ -0,0 +1,13 @@
+-- +migrate Up
+ALTER TABLE application
+    ADD COLUMN expires_at TIMESTAMPTZ,
+    ADD COLUMN revoked_at TIMESTAMPTZ,
+    ADD COLUMN previous_hashed_key BYTEA,
+    ADD COLUMN previous_key_expires_at TIMESTAMPTZ;
+
+-- +migrate Down
+ALTER TABLE application
+    DROP COLUMN previous_key_expires_at,
+    DROP COLUMN previous_hashed_key,
+    DROP COLUMN revoked_at,
+    DROP COLUMN expires_at;
@@ -0,0 +1,9 @@
+-- +migrate Up
+ALTER TABLE application
+    ADD COLUMN rate_limit_per_minute INTEGER CHECK (rate_limit_per_minute > 0),
+    ADD COLUMN rate_limit_burst INTEGER CHECK (rate_limit_burst > 0);
+
+-- +migrate Down
+ALTER TABLE application
+    DROP COLUMN rate_limit_burst,
+    DROP COLUMN rate_limit_per_minute;
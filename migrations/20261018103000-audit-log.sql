@@ -0,0 +1,21 @@
+-- +migrate Up
+CREATE TABLE audit_log
+(
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  actor_app TEXT,
+  method TEXT NOT NULL,
+  route TEXT NOT NULL,
+  action TEXT NOT NULL,
+  entity_type TEXT NOT NULL,
+  entity_id TEXT NOT NULL,
+  before JSONB NOT NULL DEFAULT 'null',
+  after JSONB NOT NULL DEFAULT 'null',
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX audit_log_created_at_idx ON audit_log(created_at);
+CREATE INDEX audit_log_entity_idx ON audit_log(entity_type, entity_id);
+CREATE INDEX audit_log_actor_app_idx ON audit_log(actor_app);
+
+-- +migrate Down
+DROP TABLE audit_log;
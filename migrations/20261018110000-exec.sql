@@ -0,0 +1,25 @@
+-- +migrate Up
+CREATE TABLE exec
+(
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  name TEXT NOT NULL,
+  email TEXT NOT NULL,
+  role TEXT NOT NULL DEFAULT 'exec' CHECK (role IN ('exec', 'director')),
+  password_hash TEXT NOT NULL,
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX exec_email_key ON exec(LOWER(email));
+
+-- exec_name is kept as a snapshot of who signed the session out, and for
+-- sessions ended before exec accounts existed
+ALTER TABLE gamer_activity ADD COLUMN exec_id UUID REFERENCES exec(id);
+CREATE INDEX gamer_activity_exec_id_idx ON gamer_activity(exec_id);
+
+ALTER TABLE audit_log ADD COLUMN actor_exec_id UUID;
+
+-- +migrate Down
+ALTER TABLE audit_log DROP COLUMN actor_exec_id;
+ALTER TABLE gamer_activity DROP COLUMN exec_id;
+DROP TABLE exec;
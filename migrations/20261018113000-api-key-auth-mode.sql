@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE application
+    ADD COLUMN auth_mode TEXT NOT NULL DEFAULT 'bearer' CHECK (auth_mode IN ('bearer', 'hmac'));
+
+-- +migrate Down
+ALTER TABLE application
+    DROP COLUMN auth_mode;
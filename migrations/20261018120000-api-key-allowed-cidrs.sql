@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE application
+    ADD COLUMN allowed_cidrs CIDR[] NOT NULL DEFAULT '{}';
+
+-- +migrate Down
+ALTER TABLE application
+    DROP COLUMN allowed_cidrs;
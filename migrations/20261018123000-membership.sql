@@ -0,0 +1,27 @@
+-- +migrate Up
+CREATE TABLE membership
+(
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  student_number VARCHAR(8) NOT NULL REFERENCES gamer_profile(student_number) ON DELETE CASCADE,
+  membership_tier INTEGER NOT NULL,
+  source TEXT NOT NULL CHECK (source IN ('showpass', 'cash', 'comp', 'unknown')),
+  purchased_at TIMESTAMPTZ NOT NULL,
+  starts_at TIMESTAMPTZ NOT NULL,
+  expires_at TIMESTAMPTZ,
+  recorded_by_app TEXT,
+  recorded_by_exec_id UUID REFERENCES exec(id),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX membership_student_number_idx ON membership(student_number, starts_at);
+
+-- Every existing paid membership becomes the first entry in its member's
+-- history. gamer_profile keeps membership_tier and membership_expiry_date
+-- as a copy of the current membership for listings.
+INSERT INTO membership (student_number, membership_tier, source, purchased_at, starts_at, expires_at)
+SELECT student_number, membership_tier, 'unknown', COALESCE(created_at, NOW()), COALESCE(created_at, NOW()), membership_expiry_date
+FROM gamer_profile
+WHERE membership_tier > 0;
+
+-- +migrate Down
+DROP TABLE membership;
@@ -0,0 +1,30 @@
+-- +migrate Up
+CREATE TABLE ban
+(
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  student_number VARCHAR(8) NOT NULL REFERENCES gamer_profile(student_number) ON DELETE CASCADE,
+  reason TEXT NOT NULL,
+  starts_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  -- NULL for a permanent ban
+  ends_at TIMESTAMPTZ,
+  issued_by_app TEXT,
+  issued_by_exec_id UUID REFERENCES exec(id),
+  lifted_at TIMESTAMPTZ,
+  lifted_by_app TEXT,
+  lifted_by_exec_id UUID REFERENCES exec(id),
+  lift_reason TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CHECK (ends_at IS NULL OR ends_at > starts_at)
+);
+
+CREATE INDEX ban_student_number_idx ON ban(student_number, starts_at);
+
+-- The banned flag on gamer_profile is superseded by ban records. Profiles
+-- flagged as banned get a permanent ban so they stay blocked.
+INSERT INTO ban (student_number, reason, starts_at)
+SELECT student_number, 'Banned before ban records were kept', COALESCE(created_at, NOW())
+FROM gamer_profile
+WHERE banned;
+
+-- +migrate Down
+DROP TABLE ban;
@@ -0,0 +1,50 @@
+-- +migrate Up
+ALTER TABLE gamer_profile ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE gamer_profile ADD COLUMN erased_at TIMESTAMPTZ;
+
+-- Erasing a profile replaces its student number with a pseudonym, which
+-- has to carry over to the rows kept for statistics
+ALTER TABLE gamer_activity DROP CONSTRAINT gamer_activity_student_number_fkey;
+ALTER TABLE gamer_activity
+    ADD CONSTRAINT gamer_activity_student_number_fkey
+        FOREIGN KEY (student_number)
+        REFERENCES gamer_profile (student_number)
+        ON UPDATE CASCADE;
+
+ALTER TABLE membership DROP CONSTRAINT membership_student_number_fkey;
+ALTER TABLE membership
+    ADD CONSTRAINT membership_student_number_fkey
+        FOREIGN KEY (student_number)
+        REFERENCES gamer_profile (student_number)
+        ON DELETE CASCADE ON UPDATE CASCADE;
+
+ALTER TABLE ban DROP CONSTRAINT ban_student_number_fkey;
+ALTER TABLE ban
+    ADD CONSTRAINT ban_student_number_fkey
+        FOREIGN KEY (student_number)
+        REFERENCES gamer_profile (student_number)
+        ON DELETE CASCADE ON UPDATE CASCADE;
+
+-- +migrate Down
+ALTER TABLE ban DROP CONSTRAINT ban_student_number_fkey;
+ALTER TABLE ban
+    ADD CONSTRAINT ban_student_number_fkey
+        FOREIGN KEY (student_number)
+        REFERENCES gamer_profile (student_number)
+        ON DELETE CASCADE;
+
+ALTER TABLE membership DROP CONSTRAINT membership_student_number_fkey;
+ALTER TABLE membership
+    ADD CONSTRAINT membership_student_number_fkey
+        FOREIGN KEY (student_number)
+        REFERENCES gamer_profile (student_number)
+        ON DELETE CASCADE;
+
+ALTER TABLE gamer_activity DROP CONSTRAINT gamer_activity_student_number_fkey;
+ALTER TABLE gamer_activity
+    ADD CONSTRAINT gamer_activity_student_number_fkey
+        FOREIGN KEY (student_number)
+        REFERENCES gamer_profile (student_number);
+
+ALTER TABLE gamer_profile DROP COLUMN erased_at;
+ALTER TABLE gamer_profile DROP COLUMN deleted_at;
@@ -0,0 +1,11 @@
+-- +migrate Up
+-- external_ref is the id of the purchase a membership came from, such as a
+-- Showpass purchase id. A purchase is only ever recorded once, however many
+-- times it is delivered.
+ALTER TABLE membership ADD COLUMN external_ref TEXT;
+
+CREATE UNIQUE INDEX membership_external_ref_idx ON membership(external_ref) WHERE external_ref IS NOT NULL;
+
+-- +migrate Down
+DROP INDEX membership_external_ref_idx;
+ALTER TABLE membership DROP COLUMN external_ref;
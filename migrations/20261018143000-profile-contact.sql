@@ -0,0 +1,16 @@
+-- +migrate Up
+ALTER TABLE gamer_profile ADD COLUMN email TEXT;
+ALTER TABLE gamer_profile ADD COLUMN discord_handle TEXT;
+-- Marketing needs members to opt in. Notices about their own membership,
+-- such as expiry or bans, are sent unless they opt out.
+ALTER TABLE gamer_profile ADD COLUMN marketing_consent BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE gamer_profile ADD COLUMN notification_consent BOOLEAN NOT NULL DEFAULT TRUE;
+
+CREATE UNIQUE INDEX gamer_profile_email_key ON gamer_profile (LOWER(email));
+
+-- +migrate Down
+DROP INDEX gamer_profile_email_key;
+ALTER TABLE gamer_profile DROP COLUMN notification_consent;
+ALTER TABLE gamer_profile DROP COLUMN marketing_consent;
+ALTER TABLE gamer_profile DROP COLUMN discord_handle;
+ALTER TABLE gamer_profile DROP COLUMN email;
@@ -0,0 +1,32 @@
+-- +migrate Up
+CREATE TABLE member_note
+(
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  student_number VARCHAR(8) NOT NULL REFERENCES gamer_profile(student_number) ON DELETE CASCADE ON UPDATE CASCADE,
+  body TEXT NOT NULL,
+  category TEXT NOT NULL DEFAULT 'info' CHECK (category IN ('info', 'warning', 'incident')),
+  pinned BOOLEAN NOT NULL DEFAULT FALSE,
+  author_app TEXT,
+  author_exec_id UUID REFERENCES exec(id),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  archived_at TIMESTAMPTZ,
+  archived_by_app TEXT,
+  archived_by_exec_id UUID REFERENCES exec(id)
+);
+
+CREATE INDEX member_note_student_number_idx ON member_note(student_number, created_at);
+
+-- Existing notes become the first pinned note of their member.
+-- gamer_profile keeps notes as a copy of the latest pinned note for clients
+-- that read it, so it has to fit any note.
+INSERT INTO member_note (student_number, body, pinned, created_at)
+SELECT student_number, notes, TRUE, COALESCE(created_at, NOW())
+FROM gamer_profile
+WHERE notes IS NOT NULL
+  AND notes <> '';
+
+ALTER TABLE gamer_profile ALTER COLUMN notes TYPE TEXT;
+
+-- +migrate Down
+DROP TABLE member_note;
+ALTER TABLE gamer_profile ALTER COLUMN notes TYPE VARCHAR(250) USING LEFT(notes, 250);
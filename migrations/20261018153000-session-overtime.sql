@@ -0,0 +1,7 @@
+-- +migrate Up
+-- Set once the overtime reaper has reported a session for running past its
+-- tier's allowance, so it is only reported once
+ALTER TABLE gamer_activity ADD COLUMN overdue_flagged_at TIMESTAMPTZ;
+
+-- +migrate Down
+ALTER TABLE gamer_activity DROP COLUMN overdue_flagged_at;
@@ -0,0 +1,7 @@
+-- +migrate Up
+-- When a session runs out. Usually the tier's session length, but sessions
+-- started with less of the daily allowance left are cut short.
+ALTER TABLE gamer_activity ADD COLUMN expires_at TIMESTAMPTZ;
+
+-- +migrate Down
+ALTER TABLE gamer_activity DROP COLUMN expires_at;
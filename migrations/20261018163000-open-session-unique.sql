@@ -0,0 +1,20 @@
+-- +migrate Up
+-- Only the latest open session on each PC and for each student is kept open.
+-- Older ones were left open by mistake and are ended now.
+UPDATE gamer_activity ga
+SET ended_at = NOW(), exec_name = 'echo-base'
+WHERE ga.ended_at IS NULL
+AND EXISTS (
+  SELECT 1 FROM gamer_activity newer
+  WHERE newer.ended_at IS NULL
+  AND newer.id <> ga.id
+  AND (newer.pc_number = ga.pc_number OR newer.student_number = ga.student_number)
+  AND (newer.started_at, newer.id) > (ga.started_at, ga.id)
+);
+
+CREATE UNIQUE INDEX gamer_activity_open_pc_key ON gamer_activity (pc_number) WHERE ended_at IS NULL;
+CREATE UNIQUE INDEX gamer_activity_open_student_key ON gamer_activity (student_number) WHERE ended_at IS NULL;
+
+-- +migrate Down
+DROP INDEX gamer_activity_open_student_key;
+DROP INDEX gamer_activity_open_pc_key;
@@ -0,0 +1,20 @@
+-- +migrate Up
+CREATE TABLE station (
+  number INTEGER PRIMARY KEY CHECK (number > 0),
+  zone TEXT,
+  type TEXT NOT NULL DEFAULT 'pc' CHECK (type IN ('pc', 'console', 'vr')),
+  specs JSONB NOT NULL DEFAULT '{}',
+  status TEXT NOT NULL DEFAULT 'available' CHECK (status IN ('available', 'maintenance', 'retired')),
+  status_reason TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Every PC played on so far, so sessions can still be started on them
+INSERT INTO station (number)
+SELECT DISTINCT pc_number
+FROM gamer_activity
+WHERE pc_number > 0;
+
+-- +migrate Down
+DROP TABLE station;
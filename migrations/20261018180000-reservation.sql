@@ -0,0 +1,51 @@
+-- +migrate Up
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TABLE reservation
+(
+  id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+  team_name TEXT,
+  starts_at TIMESTAMPTZ NOT NULL,
+  ends_at TIMESTAMPTZ NOT NULL,
+  status TEXT NOT NULL DEFAULT 'booked' CHECK (status IN ('booked', 'checked_in', 'cancelled', 'no_show')),
+  note TEXT,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  checked_in_at TIMESTAMPTZ,
+  cancelled_at TIMESTAMPTZ,
+  released_at TIMESTAMPTZ,
+  CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX reservation_starts_at_idx ON reservation(starts_at);
+
+-- The members a reservation is held for: one for a member's booking, or
+-- everyone on the team
+CREATE TABLE reservation_member
+(
+  reservation_id UUID NOT NULL REFERENCES reservation(id) ON DELETE CASCADE,
+  student_number VARCHAR(8) NOT NULL REFERENCES gamer_profile(student_number) ON DELETE CASCADE ON UPDATE CASCADE,
+  PRIMARY KEY (reservation_id, student_number)
+);
+
+CREATE INDEX reservation_member_student_number_idx ON reservation_member(student_number);
+
+-- Each reserved station holds the reservation's window. Cancelled and no-show
+-- reservations stop holding theirs, so only active windows can't overlap.
+CREATE TABLE reservation_station
+(
+  reservation_id UUID NOT NULL REFERENCES reservation(id) ON DELETE CASCADE,
+  station_number INTEGER NOT NULL REFERENCES station(number),
+  starts_at TIMESTAMPTZ NOT NULL,
+  ends_at TIMESTAMPTZ NOT NULL,
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  PRIMARY KEY (reservation_id, station_number),
+  CONSTRAINT reservation_station_no_overlap EXCLUDE USING gist (
+    station_number WITH =,
+    tstzrange(starts_at, ends_at) WITH &&
+  ) WHERE (active)
+);
+
+-- +migrate Down
+DROP TABLE reservation_station;
+DROP TABLE reservation_member;
+DROP TABLE reservation;
@@ -0,0 +1,17 @@
+-- +migrate Up
+-- Set on sessions the server ended, such as overdue sessions ended by the
+-- reaper, which have no exec. Those were recorded under the system exec name
+-- before.
+ALTER TABLE gamer_activity ADD COLUMN ended_by_system BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE gamer_activity
+SET ended_by_system = TRUE, exec_name = NULL
+WHERE exec_id IS NULL
+AND exec_name = 'echo-base';
+
+-- +migrate Down
+UPDATE gamer_activity
+SET exec_name = 'echo-base'
+WHERE ended_by_system;
+
+ALTER TABLE gamer_activity DROP COLUMN ended_by_system;
@@ -0,0 +1,161 @@
+//go:build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ubcesports/echo-base/internal/handlers"
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
+)
+
+func TestAPIKeyEndpoints(t *testing.T) {
+	var kioskKey auth.APIKey
+
+	t.Run("generate scoped key", func(t *testing.T) {
+		req := handlers.GenerateKeyRequest{
+			AppName: "integration-kiosk",
+			Scopes:  []auth.Scope{auth.ScopeActivityRead, auth.ScopeActivityWrite},
+		}
+
+		rr := makeRequest(t, http.MethodPost, "/admin/generate-key", req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		if err := json.NewDecoder(rr.Body).Decode(&kioskKey); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+	})
+
+	t.Run("scoped key is limited to its scopes", func(t *testing.T) {
+		rr := makeRequestWithKey(t, kioskKey.APIKey, http.MethodGet, "/v1/api/activity/all/get-active-pcs", nil)
+		if rr.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		rr = makeRequestWithKey(t, kioskKey.APIKey, http.MethodDelete, "/v1/api/gamer/12345678", nil)
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("expected status %d, got %d", http.StatusForbidden, rr.Code)
+		}
+
+		rr = makeRequestWithKey(t, kioskKey.APIKey, http.MethodPost, "/admin/generate-key", handlers.GenerateKeyRequest{AppName: "escalation"})
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("expected status %d, got %d", http.StatusForbidden, rr.Code)
+		}
+	})
+
+	t.Run("list keys", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodGet, "/admin/keys", nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		var keys []auth.KeyInfo
+		if err := json.NewDecoder(rr.Body).Decode(&keys); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+
+		found := false
+		for _, key := range keys {
+			if key.KeyId == kioskKey.KeyId {
+				found = true
+				if key.AppName != "integration-kiosk" {
+					t.Errorf("expected app_name integration-kiosk, got %s", key.AppName)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("expected key %s to be listed", kioskKey.KeyId)
+		}
+	})
+
+	t.Run("rotate key keeps old secret during overlap", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodPost, "/admin/keys/"+kioskKey.KeyId+"/rotate", map[string]int{"overlap_hours": 1})
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		var rotated auth.APIKey
+		if err := json.NewDecoder(rr.Body).Decode(&rotated); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+
+		for _, key := range []string{kioskKey.APIKey, rotated.APIKey} {
+			rr = makeRequestWithKey(t, key, http.MethodGet, "/v1/api/activity/all/get-active-pcs", nil)
+			if rr.Code != http.StatusOK {
+				t.Errorf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+			}
+		}
+		kioskKey = rotated
+	})
+
+	t.Run("revoked key is rejected", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodPost, "/admin/keys/"+kioskKey.KeyId+"/revoke", nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		rr = makeRequestWithKey(t, kioskKey.APIKey, http.MethodGet, "/v1/api/activity/all/get-active-pcs", nil)
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("expected status %d, got %d", http.StatusUnauthorized, rr.Code)
+		}
+	})
+
+	t.Run("revoke unknown key", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodPost, "/admin/keys/doesnotexist/revoke", nil)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, rr.Code)
+		}
+	})
+}
+
+func TestAPIKeyAllowedCIDRs(t *testing.T) {
+	var loungeKey auth.APIKey
+
+	// httptest requests come from 192.0.2.1
+	req := handlers.GenerateKeyRequest{
+		AppName:      "integration-lounge",
+		Scopes:       []auth.Scope{auth.ScopeActivityRead},
+		AllowedCIDRs: []string{"203.0.113.0/24"},
+	}
+	rr := makeRequest(t, http.MethodPost, "/admin/generate-key", req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&loungeKey); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	path := "/v1/api/activity/all/get-active-pcs"
+
+	t.Run("refused outside the allowlist", func(t *testing.T) {
+		rr := makeRequestWithKey(t, loungeKey.APIKey, http.MethodGet, path, nil)
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("expected status %d, got %d", http.StatusForbidden, rr.Code)
+		}
+	})
+
+	t.Run("accepted once the network is allowed", func(t *testing.T) {
+		update := handlers.UpdateAllowedCIDRsRequest{AllowedCIDRs: []string{"203.0.113.0/24", "192.0.2.0/24"}}
+		rr := makeRequest(t, http.MethodPut, "/admin/keys/"+loungeKey.KeyId+"/allowed-cidrs", update)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		rr = makeRequestWithKey(t, loungeKey.APIKey, http.MethodGet, path, nil)
+		if rr.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		update := handlers.UpdateAllowedCIDRsRequest{AllowedCIDRs: []string{"the lounge"}}
+		rr := makeRequest(t, http.MethodPut, "/admin/keys/"+loungeKey.KeyId+"/allowed-cidrs", update)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+		}
+	})
+}
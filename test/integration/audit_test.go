@@ -0,0 +1,85 @@
+//go:build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+func TestAuditLogEndpoints(t *testing.T) {
+	cleanupTestData(t)
+
+	profile := models.CreateGamerProfileRequest{
+		StudentNumber:  "44445555",
+		FirstName:      "Audit",
+		LastName:       "Trail",
+		MembershipTier: 1,
+		Banned:         ptrBool(false),
+	}
+	if rr := makeRequest(t, http.MethodPost, "/v1/api/gamer", profile); rr.Code != http.StatusCreated {
+		t.Fatalf("failed to create profile: %d %s", rr.Code, rr.Body.String())
+	}
+
+	profile.Banned = ptrBool(true)
+	if rr := makeRequest(t, http.MethodPost, "/v1/api/gamer", profile); rr.Code != http.StatusCreated {
+		t.Fatalf("failed to update profile: %d %s", rr.Code, rr.Body.String())
+	}
+
+	listAudit := func(t *testing.T, query string) []models.AuditEntry {
+		rr := makeRequest(t, http.MethodGet, "/v1/api/audit"+query, nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+		var entries []models.AuditEntry
+		if err := json.NewDecoder(rr.Body).Decode(&entries); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return entries
+	}
+
+	t.Run("filter by entity", func(t *testing.T) {
+		entries := listAudit(t, "?entity_type=profile&entity_id=44445555")
+		if len(entries) != 2 {
+			t.Fatalf("expected 2 entries, got %d", len(entries))
+		}
+
+		// Newest first
+		ban := entries[0]
+		if ban.ActorApp == nil || *ban.ActorApp != "integration-test" {
+			t.Errorf("expected actor integration-test, got %v", ban.ActorApp)
+		}
+		if ban.Route != "POST /v1/api/gamer" {
+			t.Errorf("expected route POST /v1/api/gamer, got %s", ban.Route)
+		}
+
+		var before, after map[string]any
+		json.Unmarshal(ban.Before, &before)
+		json.Unmarshal(ban.After, &after)
+		if before["banned"] != false || after["banned"] != true {
+			t.Errorf("expected banned to change false -> true, got %v -> %v", before["banned"], after["banned"])
+		}
+	})
+
+	t.Run("filter by actor", func(t *testing.T) {
+		if entries := listAudit(t, "?actor=someone-else"); len(entries) != 0 {
+			t.Errorf("expected no entries, got %d", len(entries))
+		}
+	})
+
+	t.Run("filter by date range", func(t *testing.T) {
+		if entries := listAudit(t, "?entity_type=profile&from=2000-01-01&to=2000-01-02"); len(entries) != 0 {
+			t.Errorf("expected no entries, got %d", len(entries))
+		}
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodGet, "/v1/api/audit?from=yesterday", nil)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+		}
+	})
+}
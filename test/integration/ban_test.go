@@ -0,0 +1,104 @@
+//go:build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+func TestBans(t *testing.T) {
+	cleanupTestData(t)
+
+	req := models.CreateGamerProfileRequest{
+		StudentNumber:  "66666666",
+		FirstName:      "Banned",
+		LastName:       "Member",
+		MembershipTier: 2,
+	}
+	if rr := makeRequest(t, http.MethodPost, "/v1/api/gamer", req); rr.Code != http.StatusCreated {
+		t.Fatalf("failed to create test profile: %s", rr.Body.String())
+	}
+
+	checkIn := models.CreateActivityRequest{StudentNumber: "66666666", PCNumber: 4, Game: "Test"}
+	var ban models.Ban
+
+	t.Run("issue suspension", func(t *testing.T) {
+		ends := time.Now().Add(24 * time.Hour)
+		rr := makeRequest(t, http.MethodPost, "/v1/api/gamer/66666666/bans", models.IssueBanRequest{Reason: "Unplugged a PC", EndsAt: &ends})
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rr.Code, rr.Body.String())
+		}
+		if err := json.NewDecoder(rr.Body).Decode(&ban); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if ban.Reason != "Unplugged a PC" || ban.EndsAt == nil || ban.IssuedByApp == nil {
+			t.Errorf("unexpected ban %+v", ban)
+		}
+	})
+
+	t.Run("banned member can't check in", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodPost, "/v1/api/activity", checkIn)
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusForbidden, rr.Code, rr.Body.String())
+		}
+		if !strings.Contains(rr.Body.String(), "Unplugged a PC") {
+			t.Errorf("expected the ban reason in the error, got %s", rr.Body.String())
+		}
+	})
+
+	t.Run("profile shows the ban", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodGet, "/v1/api/gamer/66666666", nil)
+		var profile models.GamerProfile
+		if err := json.NewDecoder(rr.Body).Decode(&profile); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if profile.Banned == nil || !*profile.Banned {
+			t.Errorf("expected the profile to be banned, got %v", profile.Banned)
+		}
+	})
+
+	t.Run("lift ban", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodPost, "/v1/api/gamer/66666666/bans/"+ban.ID+"/lift", models.LiftBanRequest{Reason: "Apologised"})
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		rr = makeRequest(t, http.MethodPost, "/v1/api/gamer/66666666/bans/"+ban.ID+"/lift", nil)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected lifting twice to be status %d, got %d", http.StatusBadRequest, rr.Code)
+		}
+
+		rr = makeRequest(t, http.MethodPost, "/v1/api/activity", checkIn)
+		if rr.Code != http.StatusCreated {
+			t.Errorf("expected status %d, got %d: %s", http.StatusCreated, rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("ban history", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodGet, "/v1/api/gamer/66666666/bans", nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		var bans []models.Ban
+		if err := json.NewDecoder(rr.Body).Decode(&bans); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if len(bans) != 1 || bans[0].LiftedAt == nil || bans[0].LiftReason == nil {
+			t.Errorf("unexpected history %+v", bans)
+		}
+	})
+
+	t.Run("unknown ban", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodPost, "/v1/api/gamer/66666666/bans/not-a-ban/lift", nil)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, rr.Code)
+		}
+	})
+}
@@ -0,0 +1,121 @@
+//go:build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+func TestExecEndpoints(t *testing.T) {
+	cleanupTestData(t)
+
+	var created models.Exec
+	var loginToken string
+
+	t.Run("create exec", func(t *testing.T) {
+		req := models.CreateExecRequest{
+			Name:     "Login Exec",
+			Email:    "Login.Exec@Example.com",
+			Password: "correct horse battery",
+		}
+
+		rr := makeRequest(t, http.MethodPost, "/admin/execs", req)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rr.Code, rr.Body.String())
+		}
+
+		if err := json.NewDecoder(rr.Body).Decode(&created); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if created.Email != "login.exec@example.com" {
+			t.Errorf("expected normalized email, got %s", created.Email)
+		}
+		if created.Role != models.ExecRoleExec {
+			t.Errorf("expected default role exec, got %s", created.Role)
+		}
+	})
+
+	t.Run("duplicate email", func(t *testing.T) {
+		req := models.CreateExecRequest{
+			Name:     "Someone Else",
+			Email:    "login.exec@example.com",
+			Password: "another long password",
+		}
+
+		rr := makeRequest(t, http.MethodPost, "/admin/execs", req)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d: %s", http.StatusBadRequest, rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("login", func(t *testing.T) {
+		req := models.ExecLoginRequest{Email: "login.exec@example.com", Password: "correct horse battery"}
+
+		rr := makeRequest(t, http.MethodPost, "/v1/api/exec/login", req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		var token models.ExecToken
+		if err := json.NewDecoder(rr.Body).Decode(&token); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if token.Token == "" || token.Exec.ID != created.ID {
+			t.Errorf("unexpected login response %+v", token)
+		}
+		loginToken = token.Token
+	})
+
+	t.Run("login with wrong password", func(t *testing.T) {
+		req := models.ExecLoginRequest{Email: "login.exec@example.com", Password: "wrong password"}
+
+		rr := makeRequest(t, http.MethodPost, "/v1/api/exec/login", req)
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("expected status %d, got %d", http.StatusUnauthorized, rr.Code)
+		}
+	})
+
+	t.Run("deactivated exec cannot log in", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodPatch, "/admin/execs/"+created.ID, models.UpdateExecRequest{Active: ptrBool(false)})
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		req := models.ExecLoginRequest{Email: "login.exec@example.com", Password: "correct horse battery"}
+		rr = makeRequest(t, http.MethodPost, "/v1/api/exec/login", req)
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("expected status %d, got %d", http.StatusUnauthorized, rr.Code)
+		}
+		// Tokens issued before deactivation stop working too
+		rr = sendRequest(t, testAPIKey, loginToken, http.MethodGet, "/v1/api/activity/all/get-active-pcs", nil)
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("expected status %d, got %d", http.StatusUnauthorized, rr.Code)
+		}
+	})
+
+	t.Run("invalid exec token", func(t *testing.T) {
+		rr := sendRequest(t, testAPIKey, "not-a-token", http.MethodGet, "/v1/api/activity/all/get-active-pcs", nil)
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("expected status %d, got %d", http.StatusUnauthorized, rr.Code)
+		}
+	})
+
+	t.Run("list execs", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodGet, "/admin/execs", nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		var execs []models.Exec
+		if err := json.NewDecoder(rr.Body).Decode(&execs); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if len(execs) < 3 {
+			t.Errorf("expected at least 3 execs, got %d", len(execs))
+		}
+	})
+}
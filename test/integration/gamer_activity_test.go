@@ -3,10 +3,13 @@
 package integration
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
+	"github.com/ubcesports/echo-base/internal/database"
 	"github.com/ubcesports/echo-base/internal/models"
 )
 
@@ -64,17 +67,54 @@ func TestGamerActivityEndpoints(t *testing.T) {
 		}
 
 		if len(activities) != 1 {
-			t.Errorf("expected 1 active session, got %d", len(activities))
+			t.Fatalf("expected 1 active session, got %d", len(activities))
+		}
+		session := activities[0]
+		if session.ExpiresAt == nil || !session.ExpiresAt.Equal(session.StartedAt.Add(time.Hour)) {
+			t.Errorf("expected a Tier 1 session to expire after an hour, got %v", session.ExpiresAt)
+		}
+		if session.Overdue == nil || *session.Overdue {
+			t.Errorf("expected a new session not to be overdue, got %v", session.Overdue)
+		}
+	})
+
+	t.Run("get play quota", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodGet, "/v1/api/gamer/22222222/quota", nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		var quota models.PlayQuota
+		if err := json.NewDecoder(rr.Body).Decode(&quota); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if !quota.DailyLimit || quota.LimitMs == nil || *quota.LimitMs != time.Hour.Milliseconds() {
+			t.Errorf("expected a daily limit of an hour, got %+v", quota)
+		}
+		if quota.RemainingMs == nil || *quota.RemainingMs > *quota.LimitMs-quota.UsedMs {
+			t.Errorf("expected the running session to count towards the limit, got %+v", quota)
+		}
+		if !quota.ResetsAt.After(time.Now()) {
+			t.Errorf("expected the quota to reset in the future, got %v", quota.ResetsAt)
+		}
+
+		rr = makeRequest(t, http.MethodGet, "/v1/api/gamer/99999999/quota", nil)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected status %d for a missing member, got %d", http.StatusNotFound, rr.Code)
 		}
 	})
 
 	t.Run("end activity", func(t *testing.T) {
 		req := models.UpdateActivityRequest{
 			PCNumber: 1,
-			ExecName: "TestExec",
 		}
 
 		rr := makeRequest(t, http.MethodPatch, "/v1/api/activity/update/22222222", req)
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("expected status %d without an exec token, got %d", http.StatusUnauthorized, rr.Code)
+		}
+
+		rr = makeExecRequest(t, "TestExec", http.MethodPatch, "/v1/api/activity/update/22222222", req)
 
 		if rr.Code != http.StatusCreated {
 			t.Errorf("expected status %d, got %d: %s", http.StatusCreated, rr.Code, rr.Body.String())
@@ -91,6 +131,9 @@ func TestGamerActivityEndpoints(t *testing.T) {
 		if activity.ExecName == nil || *activity.ExecName != "TestExec" {
 			t.Errorf("expected exec_name TestExec, got %v", activity.ExecName)
 		}
+		if activity.ExecID == nil {
+			t.Error("expected exec_id to be set")
+		}
 	})
 
 	t.Run("get exec leaderboard", func(t *testing.T) {
@@ -120,10 +163,9 @@ func TestGamerActivityEndpoints(t *testing.T) {
 
 		endReq := models.UpdateActivityRequest{
 			PCNumber: 2,
-			ExecName: "TestExec",
 		}
 
-		rr = makeRequest(t, http.MethodPatch, "/v1/api/activity/update/33333333", endReq)
+		rr = makeExecRequest(t, "TestExec", http.MethodPatch, "/v1/api/activity/update/33333333", endReq)
 		if rr.Code != http.StatusCreated {
 			t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rr.Code, rr.Body.String())
 		}
@@ -141,10 +183,9 @@ func TestGamerActivityEndpoints(t *testing.T) {
 
 		endReq = models.UpdateActivityRequest{
 			PCNumber: 3,
-			ExecName: "AnotherExec",
 		}
 
-		rr = makeRequest(t, http.MethodPatch, "/v1/api/activity/update/33333333", endReq)
+		rr = makeExecRequest(t, "AnotherExec", http.MethodPatch, "/v1/api/activity/update/33333333", endReq)
 		if rr.Code != http.StatusCreated {
 			t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rr.Code, rr.Body.String())
 		}
@@ -168,6 +209,52 @@ func TestGamerActivityEndpoints(t *testing.T) {
 		}
 	})
 
+	t.Run("sessions ended by the system stay off the leaderboard", func(t *testing.T) {
+		req := models.CreateActivityRequest{
+			StudentNumber: "33333333",
+			PCNumber:      4,
+			Game:          "VALORANT",
+		}
+
+		rr := makeRequest(t, http.MethodPost, "/v1/api/activity", req)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rr.Code, rr.Body.String())
+		}
+		var activity models.GamerActivity
+		if err := json.NewDecoder(rr.Body).Decode(&activity); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+
+		ended, err := database.NewGamerActivityRepository(database.DB).EndOverdue(context.Background(), activity.ID, time.Now())
+		if err != nil {
+			t.Fatalf("failed to end session: %v", err)
+		}
+		if ended == nil || !ended.EndedBySystem || ended.ExecName != nil {
+			t.Fatalf("expected the session to be ended by the system, got %+v", ended)
+		}
+
+		rr = makeRequest(t, http.MethodGet, "/v1/api/activity/all/leaderboard", nil)
+		var leaderboard []models.ExecLeaderboardEntry
+		if err := json.NewDecoder(rr.Body).Decode(&leaderboard); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		total := 0
+		for _, entry := range leaderboard {
+			if entry.ExecID == nil && entry.ExecName == "" {
+				t.Errorf("unexpected leaderboard entry %+v", entry)
+			}
+			total += entry.SignoutCount
+		}
+		var execEnded int
+		err = database.DB.QueryRow("SELECT COUNT(*) FROM gamer_activity WHERE ended_at IS NOT NULL AND NOT ended_by_system AND (exec_id IS NOT NULL OR exec_name IS NOT NULL)").Scan(&execEnded)
+		if err != nil {
+			t.Fatalf("failed to count sessions: %v", err)
+		}
+		if total != execEnded {
+			t.Errorf("expected %d sign outs on the leaderboard, got %d", execEnded, total)
+		}
+	})
+
 	t.Run("get activities by student", func(t *testing.T) {
 		rr := makeRequest(t, http.MethodGet, "/v1/api/activity/22222222", nil)
 
@@ -4,7 +4,9 @@ package integration
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"sync"
 	"testing"
 	"time"
 
@@ -107,6 +109,102 @@ func TestGamerProfileEndpoints(t *testing.T) {
 		}
 	})
 
+	t.Run("patch profile", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodGet, "/v1/api/gamer/12345678", nil)
+		var before models.GamerProfile
+		if err := json.NewDecoder(rr.Body).Decode(&before); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+
+		rr = makeRequest(t, http.MethodPatch, "/v1/api/gamer/12345678", map[string]any{"last_name": "Dough", "notes": "fixed typo"})
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		var profile models.GamerProfile
+		if err := json.NewDecoder(rr.Body).Decode(&profile); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if profile.LastName != "Dough" || profile.FirstName != "Jane" || profile.MembershipTier != 2 {
+			t.Errorf("unexpected profile %+v", profile)
+		}
+		if !profile.CreatedAt.Equal(before.CreatedAt) {
+			t.Errorf("created_at changed from %v to %v", before.CreatedAt, profile.CreatedAt)
+		}
+		if !profile.MembershipExpiryDate.Equal(*before.MembershipExpiryDate) {
+			t.Errorf("expiry changed from %v to %v", before.MembershipExpiryDate, profile.MembershipExpiryDate)
+		}
+
+		rr = makeRequest(t, http.MethodPatch, "/v1/api/gamer/12345678", map[string]any{"notes": nil})
+		if err := json.NewDecoder(rr.Body).Decode(&profile); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if profile.Notes != nil {
+			t.Errorf("expected notes to be cleared, got %q", *profile.Notes)
+		}
+	})
+
+	t.Run("concurrent patches keep each other's fields", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			first, last := fmt.Sprintf("Jane%d", i), fmt.Sprintf("Dough%d", i)
+
+			var wg sync.WaitGroup
+			for _, body := range []map[string]any{{"first_name": first}, {"last_name": last}} {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					makeRequest(t, http.MethodPatch, "/v1/api/gamer/12345678", body)
+				}()
+			}
+			wg.Wait()
+
+			rr := makeRequest(t, http.MethodGet, "/v1/api/gamer/12345678", nil)
+			var profile models.GamerProfile
+			if err := json.NewDecoder(rr.Body).Decode(&profile); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if profile.FirstName != first || profile.LastName != last {
+				t.Fatalf("expected %s %s, got %s %s", first, last, profile.FirstName, profile.LastName)
+			}
+		}
+
+		rr := makeRequest(t, http.MethodPatch, "/v1/api/gamer/12345678", map[string]any{"first_name": "Jane", "last_name": "Dough"})
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("patch non-existent profile", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodPatch, "/v1/api/gamer/99999999", map[string]any{"last_name": "Nobody"})
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, rr.Code)
+		}
+	})
+
+	t.Run("re-registering keeps join date", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodGet, "/v1/api/gamer/12345678", nil)
+		var before models.GamerProfile
+		if err := json.NewDecoder(rr.Body).Decode(&before); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+
+		req := models.CreateGamerProfileRequest{
+			StudentNumber:  "12345678",
+			FirstName:      "Jane",
+			LastName:       "Doe",
+			MembershipTier: 1,
+		}
+		rr = makeRequest(t, http.MethodPost, "/v1/api/gamer", req)
+
+		var profile models.GamerProfile
+		if err := json.NewDecoder(rr.Body).Decode(&profile); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if !profile.CreatedAt.Equal(before.CreatedAt) {
+			t.Errorf("created_at changed from %v to %v", before.CreatedAt, profile.CreatedAt)
+		}
+	})
+
 	t.Run("invalid student number", func(t *testing.T) {
 		req := models.CreateGamerProfileRequest{
 			StudentNumber:  "123",
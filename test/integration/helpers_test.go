@@ -10,17 +10,27 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ubcesports/echo-base/config"
 	"github.com/ubcesports/echo-base/internal"
 	"github.com/ubcesports/echo-base/internal/database"
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
+	"github.com/ubcesports/echo-base/internal/middleware"
+	"github.com/ubcesports/echo-base/internal/models"
 	"github.com/ubcesports/echo-base/internal/services"
 )
 
 var (
 	testServer http.Handler
 	testAPIKey string
+
+	testShowpassSecret = "integration-test-showpass-secret"
+
+	// testExecTokens maps an exec's name to a signed-in exec token
+	testExecTokens = map[string]string{}
 )
 
 func TestMain(m *testing.M) {
@@ -29,26 +39,116 @@ func TestMain(m *testing.M) {
 	defer database.Close()
 
 	authRepo := database.NewAuthRepository(database.DB)
-	authService := services.NewAuthService(authRepo)
 	gamerProfileRepo := database.NewGamerProfileRepository(database.DB)
 	gamerActivityRepo := database.NewGamerActivityRepository(database.DB)
-	gamerProfileService := services.NewGamerProfileService(gamerProfileRepo)
-	gamerActivityService := services.NewGamerActivityService(gamerActivityRepo, gamerProfileRepo)
+	stationRepo := database.NewStationRepository(database.DB)
+	reservationRepo := database.NewReservationRepository(database.DB)
+	execRepo := database.NewExecRepository(database.DB)
+	auditService := services.NewAuditService(database.NewAuditRepository(database.DB))
+	authConfig := services.DefaultAuthServiceConfig()
+	authConfig.SigningPepper = []byte("integration-test-pepper")
+	authService := services.NewAuthServiceWithConfig(authRepo, auditService, authConfig)
+	gamerProfileService := services.NewGamerProfileService(gamerProfileRepo, auditService)
+	gamerActivityService := services.NewGamerActivityService(gamerActivityRepo, gamerProfileRepo, stationRepo, reservationRepo, auditService)
+
+	execService := services.NewExecService(execRepo, auditService, services.ExecServiceConfig{
+		TokenSecret: []byte("integration-test-secret"),
+		TokenTTL:    time.Hour,
+	})
 
-	testServer = internal.NewServer(authService, gamerProfileService, gamerActivityService)
+	showpassService := services.NewShowpassService(gamerProfileService, services.ShowpassServiceConfig{
+		WebhookSecret: []byte(testShowpassSecret),
+		Tiers:         models.ShowpassTicketTiers(),
+	})
 
-	apiKey, err := authService.GenerateAPIKey(context.Background(), "integration-test")
+	memberExportService := services.NewMemberExportService(gamerProfileRepo, gamerActivityService, auditService)
+	stationService := services.NewStationService(stationRepo, gamerActivityService, auditService)
+	reservationService := services.NewReservationService(reservationRepo, stationRepo, gamerProfileRepo, auditService)
+	testServer = internal.NewServer(authService, gamerProfileService, gamerActivityService, auditService, execService, showpassService, memberExportService, stationService, reservationService, nil)
+
+	apiKey, err := authService.GenerateAPIKey(context.Background(), "integration-test", auth.KeyOptions{
+		Scopes: []auth.Scope{auth.ScopeAdmin},
+		// The suite fires requests far faster than any real client
+		RateLimit: &auth.RateLimit{PerMinute: 60000, Burst: 10000},
+	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to generate API key: %v\n", err)
 		os.Exit(1)
 	}
 	testAPIKey = apiKey.APIKey
 
+	if err := createTestExecs(execService, "TestExec", "AnotherExec"); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create test execs: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := createTestStations(30); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create test stations: %v\n", err)
+		os.Exit(1)
+	}
+
 	code := m.Run()
 	os.Exit(code)
 }
 
 func makeRequest(t *testing.T, method, path string, body interface{}) *httptest.ResponseRecorder {
+	return makeRequestWithKey(t, testAPIKey, method, path, body)
+}
+
+// createTestExecs replaces any execs left over from earlier runs and signs
+// each new one in
+func createTestExecs(execService services.ExecService, names ...string) error {
+	if _, err := database.DB.Exec("UPDATE gamer_activity SET exec_id = NULL"); err != nil {
+		return err
+	}
+	if _, err := database.DB.Exec("DELETE FROM exec"); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	for _, name := range names {
+		req := &models.CreateExecRequest{
+			Name:     name,
+			Email:    strings.ToLower(name) + "@example.com",
+			Password: "integration-test-password",
+		}
+		if _, err := execService.CreateExec(ctx, req); err != nil {
+			return err
+		}
+
+		token, err := execService.Login(ctx, &models.ExecLoginRequest{Email: req.Email, Password: req.Password}, "127.0.0.1")
+		if err != nil {
+			return err
+		}
+		testExecTokens[name] = token.Token
+	}
+	return nil
+}
+
+// createTestStations makes stations 1 to n available for sessions to be
+// started on
+func createTestStations(n int) error {
+	_, err := database.DB.Exec(`
+		INSERT INTO station (number)
+		SELECT generate_series(1, $1)
+		ON CONFLICT (number) DO UPDATE SET status = 'available', status_reason = NULL`, n)
+	return err
+}
+
+// makeExecRequest sends a request as the named test exec
+func makeExecRequest(t *testing.T, execName, method, path string, body interface{}) *httptest.ResponseRecorder {
+	token, ok := testExecTokens[execName]
+	if !ok {
+		t.Fatalf("no test exec named %s", execName)
+	}
+	return sendRequest(t, testAPIKey, token, method, path, body)
+}
+
+func makeRequestWithKey(t *testing.T, apiKey, method, path string, body interface{}) *httptest.ResponseRecorder {
+	return sendRequest(t, apiKey, "", method, path, body)
+}
+
+func sendRequest(t *testing.T, apiKey, execToken, method, path string, body interface{}) *httptest.ResponseRecorder {
 	var reqBody []byte
 	if body != nil {
 		var err error
@@ -59,8 +159,11 @@ func makeRequest(t *testing.T, method, path string, body interface{}) *httptest.
 	}
 
 	req := httptest.NewRequest(method, path, bytes.NewReader(reqBody))
-	req.Header.Set("Authorization", "Bearer "+testAPIKey)
+	req.Header.Set("Authorization", "Bearer "+apiKey)
 	req.Header.Set("Content-Type", "application/json")
+	if execToken != "" {
+		req.Header.Set(middleware.ExecTokenHeader, execToken)
+	}
 
 	rr := httptest.NewRecorder()
 	testServer.ServeHTTP(rr, req)
@@ -72,12 +175,24 @@ func cleanupTestData(t *testing.T) {
 	if err != nil {
 		t.Logf("Warning: failed to clean gamer_activity: %v", err)
 	}
+	_, err = database.DB.Exec("DELETE FROM reservation")
+	if err != nil {
+		t.Logf("Warning: failed to clean reservation: %v", err)
+	}
 	_, err = database.DB.Exec("DELETE FROM gamer_profile")
 	if err != nil {
 		t.Logf("Warning: failed to clean gamer_profile: %v", err)
 	}
+	_, err = database.DB.Exec("DELETE FROM audit_log")
+	if err != nil {
+		t.Logf("Warning: failed to clean audit_log: %v", err)
+	}
 }
 
 func ptrBool(b bool) *bool {
 	return &b
 }
+
+func ptrString(s string) *string {
+	return &s
+}
@@ -0,0 +1,92 @@
+//go:build integration
+
+package integration
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+func TestMemberExport(t *testing.T) {
+	cleanupTestData(t)
+
+	req := models.CreateGamerProfileRequest{
+		StudentNumber:  "69999999",
+		FirstName:      "Curious",
+		LastName:       "Member",
+		MembershipTier: 1,
+		Notes:          ptrString("Asked what we store"),
+	}
+	if rr := makeRequest(t, http.MethodPost, "/v1/api/gamer", req); rr.Code != http.StatusCreated {
+		t.Fatalf("failed to create test profile: %s", rr.Body.String())
+	}
+	checkIn := models.CreateActivityRequest{StudentNumber: "69999999", PCNumber: 9, Game: "Test"}
+	if rr := makeRequest(t, http.MethodPost, "/v1/api/activity", checkIn); rr.Code != http.StatusCreated {
+		t.Fatalf("failed to start activity: %s", rr.Body.String())
+	}
+
+	t.Run("json", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodGet, "/v1/api/gamer/69999999/export", nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		var export models.MemberExport
+		if err := json.NewDecoder(rr.Body).Decode(&export); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if export.Profile.StudentNumber != "69999999" || len(export.Memberships) != 1 || len(export.Activity) != 1 || len(export.Notes) != 1 {
+			t.Errorf("unexpected export %+v", export)
+		}
+		if len(export.AuditEntries) == 0 {
+			t.Error("expected audit entries about the member")
+		}
+	})
+
+	t.Run("zip", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodGet, "/v1/api/gamer/69999999/export?format=zip", nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+		if ct := rr.Header().Get("Content-Type"); ct != "application/zip" {
+			t.Errorf("expected a zip, got %s", ct)
+		}
+
+		zr, err := zip.NewReader(bytes.NewReader(rr.Body.Bytes()), int64(rr.Body.Len()))
+		if err != nil {
+			t.Fatalf("failed to read zip: %v", err)
+		}
+		if len(zr.File) != 6 {
+			t.Errorf("expected 6 CSV files, got %d", len(zr.File))
+		}
+	})
+
+	t.Run("deleted member", func(t *testing.T) {
+		if rr := makeRequest(t, http.MethodDelete, "/v1/api/gamer/69999999", nil); rr.Code != http.StatusOK {
+			t.Fatalf("failed to delete profile: %d %s", rr.Code, rr.Body.String())
+		}
+
+		rr := makeRequest(t, http.MethodGet, "/v1/api/gamer/69999999/export", nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+		var export models.MemberExport
+		if err := json.NewDecoder(rr.Body).Decode(&export); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if export.Profile.DeletedAt == nil || len(export.Memberships) != 1 {
+			t.Errorf("expected the deleted member's records, got %+v", export)
+		}
+	})
+
+	t.Run("missing member", func(t *testing.T) {
+		if rr := makeRequest(t, http.MethodGet, "/v1/api/gamer/11111111/export", nil); rr.Code != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, rr.Code)
+		}
+	})
+}
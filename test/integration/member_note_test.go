@@ -0,0 +1,102 @@
+//go:build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+func TestMemberNotes(t *testing.T) {
+	cleanupTestData(t)
+
+	req := models.CreateGamerProfileRequest{
+		StudentNumber:  "68888888",
+		FirstName:      "Noted",
+		LastName:       "Member",
+		MembershipTier: 1,
+		Notes:          ptrString("Prefers PC 3"),
+	}
+	if rr := makeRequest(t, http.MethodPost, "/v1/api/gamer", req); rr.Code != http.StatusCreated {
+		t.Fatalf("failed to create test profile: %s", rr.Body.String())
+	}
+
+	profileNotes := func(t *testing.T) *string {
+		rr := makeRequest(t, http.MethodGet, "/v1/api/gamer/68888888", nil)
+		var profile models.GamerProfile
+		if err := json.NewDecoder(rr.Body).Decode(&profile); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return profile.Notes
+	}
+	listNotes := func(t *testing.T, query string) []models.MemberNote {
+		rr := makeRequest(t, http.MethodGet, "/v1/api/gamer/68888888/notes"+query, nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+		var notes []models.MemberNote
+		if err := json.NewDecoder(rr.Body).Decode(&notes); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return notes
+	}
+
+	t.Run("registration notes become a pinned note", func(t *testing.T) {
+		notes := listNotes(t, "")
+		if len(notes) != 1 || !notes[0].Pinned || notes[0].Body != "Prefers PC 3" {
+			t.Errorf("unexpected notes %+v", notes)
+		}
+	})
+
+	var incident models.MemberNote
+	t.Run("add a pinned incident", func(t *testing.T) {
+		body := models.CreateMemberNoteRequest{Body: "Spilled a drink on PC 5", Category: models.NoteCategoryIncident, Pinned: true}
+		rr := makeRequest(t, http.MethodPost, "/v1/api/gamer/68888888/notes", body)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rr.Code, rr.Body.String())
+		}
+		if err := json.NewDecoder(rr.Body).Decode(&incident); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if incident.AuthorApp == nil {
+			t.Errorf("expected the note to be attributed, got %+v", incident)
+		}
+		if notes := profileNotes(t); notes == nil || *notes != incident.Body {
+			t.Errorf("expected profile notes to be the latest pinned note, got %v", notes)
+		}
+	})
+
+	t.Run("archive", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodPost, "/v1/api/gamer/68888888/notes/"+incident.ID+"/archive", nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+		if notes := profileNotes(t); notes == nil || *notes != "Prefers PC 3" {
+			t.Errorf("expected profile notes to fall back to the earlier pinned note, got %v", notes)
+		}
+		if notes := listNotes(t, ""); len(notes) != 1 {
+			t.Errorf("expected archived notes to be hidden, got %d notes", len(notes))
+		}
+		if notes := listNotes(t, "?archived=true"); len(notes) != 2 {
+			t.Errorf("expected archived notes to be listed on request, got %d notes", len(notes))
+		}
+
+		rr = makeRequest(t, http.MethodPost, "/v1/api/gamer/68888888/notes/"+incident.ID+"/archive", nil)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected archiving twice to be %d, got %d", http.StatusBadRequest, rr.Code)
+		}
+	})
+
+	t.Run("invalid note", func(t *testing.T) {
+		body := models.CreateMemberNoteRequest{Body: "Hi", Category: "gossip"}
+		if rr := makeRequest(t, http.MethodPost, "/v1/api/gamer/68888888/notes", body); rr.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+		}
+		if rr := makeRequest(t, http.MethodPost, "/v1/api/gamer/11111111/notes", models.CreateMemberNoteRequest{Body: "Hi"}); rr.Code != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, rr.Code)
+		}
+	})
+}
@@ -3,6 +3,7 @@
 package integration
 
 import (
+	"encoding/json"
 	"net/http"
 	"testing"
 	"time"
@@ -26,7 +27,7 @@ func TestExpiredMembership(t *testing.T) {
 		t.Fatalf("failed to create test profile: %s", rr.Body.String())
 	}
 
-	_, err := database.DB.Exec("UPDATE gamer_profile SET membership_expiry_date = $1 WHERE student_number = $2",
+	_, err := database.DB.Exec("UPDATE membership SET expires_at = $1 WHERE student_number = $2",
 		time.Now().AddDate(-1, 0, 0).Format("2006-01-02"), "33333333")
 	if err != nil {
 		t.Fatalf("failed to update expiry date: %v", err)
@@ -50,4 +51,65 @@ func TestExpiredMembership(t *testing.T) {
 			t.Error("expected detailed error message for expired membership")
 		}
 	})
+
+	t.Run("renewal allows check-in", func(t *testing.T) {
+		renewal := models.RenewMembershipRequest{MembershipTier: 1, Source: models.MembershipSourceCash}
+		rr := makeRequest(t, http.MethodPost, "/v1/api/gamer/33333333/memberships", renewal)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rr.Code, rr.Body.String())
+		}
+
+		var membership models.Membership
+		if err := json.NewDecoder(rr.Body).Decode(&membership); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if membership.MembershipTier != 1 || membership.Source != models.MembershipSourceCash || membership.RecordedByApp == nil {
+			t.Errorf("unexpected membership %+v", membership)
+		}
+
+		req := models.CreateActivityRequest{
+			StudentNumber: "33333333",
+			PCNumber:      1,
+			Game:          "Test",
+		}
+		rr = makeRequest(t, http.MethodPost, "/v1/api/activity", req)
+		if rr.Code != http.StatusCreated {
+			t.Errorf("expected status %d, got %d: %s", http.StatusCreated, rr.Code, rr.Body.String())
+		}
+
+		rr = makeRequest(t, http.MethodGet, "/v1/api/gamer/33333333", nil)
+		var profile models.GamerProfile
+		if err := json.NewDecoder(rr.Body).Decode(&profile); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if profile.MembershipTier != 1 {
+			t.Errorf("expected the profile to show the renewed tier, got %d", profile.MembershipTier)
+		}
+	})
+
+	t.Run("membership history", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodGet, "/v1/api/gamer/33333333/memberships", nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		var history []models.Membership
+		if err := json.NewDecoder(rr.Body).Decode(&history); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if len(history) != 2 {
+			t.Fatalf("expected 2 memberships, got %d", len(history))
+		}
+		if history[0].MembershipTier != 1 || history[1].MembershipTier != 2 {
+			t.Errorf("expected newest first, got tiers %d, %d", history[0].MembershipTier, history[1].MembershipTier)
+		}
+	})
+
+	t.Run("renewing an unknown student", func(t *testing.T) {
+		renewal := models.RenewMembershipRequest{MembershipTier: 1, Source: models.MembershipSourceCash}
+		rr := makeRequest(t, http.MethodPost, "/v1/api/gamer/99999999/memberships", renewal)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, rr.Code)
+		}
+	})
 }
@@ -30,6 +30,7 @@ func TestPagination(t *testing.T) {
 			Game:          fmt.Sprintf("Game%d", i),
 		}
 		makeRequest(t, http.MethodPost, "/v1/api/activity", actReq)
+		makeExecRequest(t, "TestExec", http.MethodPatch, "/v1/api/activity/update/44444444", models.UpdateActivityRequest{PCNumber: i})
 	}
 
 	t.Run("pagination limit works", func(t *testing.T) {
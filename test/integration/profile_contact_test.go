@@ -0,0 +1,78 @@
+//go:build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+func TestProfileContact(t *testing.T) {
+	cleanupTestData(t)
+
+	req := models.CreateGamerProfileRequest{
+		StudentNumber:    "61111111",
+		FirstName:        "Ada",
+		LastName:         "Lovelace",
+		MembershipTier:   1,
+		Email:            ptrString("Ada@Example.com"),
+		DiscordHandle:    ptrString("@ada.l"),
+		MarketingConsent: ptrBool(true),
+	}
+	rr := makeRequest(t, http.MethodPost, "/v1/api/gamer", req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("failed to create profile: %d %s", rr.Code, rr.Body.String())
+	}
+
+	var profile models.GamerProfile
+	if err := json.NewDecoder(rr.Body).Decode(&profile); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if profile.Email == nil || *profile.Email != "ada@example.com" || profile.DiscordHandle == nil || *profile.DiscordHandle != "ada.l" {
+		t.Errorf("expected normalized contact details, got %v and %v", profile.Email, profile.DiscordHandle)
+	}
+	if !profile.MarketingConsent || !profile.NotificationConsent {
+		t.Errorf("expected both consents, got %t and %t", profile.MarketingConsent, profile.NotificationConsent)
+	}
+
+	t.Run("email is unique", func(t *testing.T) {
+		other := models.CreateGamerProfileRequest{
+			StudentNumber:  "62222222",
+			FirstName:      "Alan",
+			LastName:       "Turing",
+			MembershipTier: 1,
+			Email:          ptrString("ADA@example.com"),
+		}
+		if rr := makeRequest(t, http.MethodPost, "/v1/api/gamer", other); rr.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d: %s", http.StatusBadRequest, rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("search by email and discord", func(t *testing.T) {
+		for _, query := range []string{"?search=ada%40example", "?search=ada.l"} {
+			rr := makeRequest(t, http.MethodGet, "/v1/api/gamer"+query, nil)
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+			}
+
+			var page models.GamerProfilePage
+			if err := json.NewDecoder(rr.Body).Decode(&page); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if page.Total != 1 || page.Profiles[0].StudentNumber != "61111111" {
+				t.Errorf("expected %s to find the member, got %+v", query, page)
+			}
+		}
+	})
+
+	t.Run("invalid contact details", func(t *testing.T) {
+		bad := req
+		bad.Email = ptrString("not an email")
+		if rr := makeRequest(t, http.MethodPost, "/v1/api/gamer", bad); rr.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+//go:build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+func TestGamerProfileDirectory(t *testing.T) {
+	cleanupTestData(t)
+
+	profiles := []models.CreateGamerProfileRequest{
+		{StudentNumber: "51111111", FirstName: "Ada", LastName: "Lovelace", MembershipTier: 1, Banned: ptrBool(false)},
+		{StudentNumber: "52222222", FirstName: "Alan", LastName: "Turing", MembershipTier: 2, Banned: ptrBool(false)},
+		{StudentNumber: "53333333", FirstName: "Grace", LastName: "Hopper", MembershipTier: 1, Banned: ptrBool(false)},
+	}
+	for _, req := range profiles {
+		if rr := makeRequest(t, http.MethodPost, "/v1/api/gamer", req); rr.Code != http.StatusCreated {
+			t.Fatalf("failed to create profile: %d %s", rr.Code, rr.Body.String())
+		}
+	}
+	ban := models.IssueBanRequest{Reason: "Directory test"}
+	if rr := makeRequest(t, http.MethodPost, "/v1/api/gamer/53333333/bans", ban); rr.Code != http.StatusCreated {
+		t.Fatalf("failed to ban profile: %d %s", rr.Code, rr.Body.String())
+	}
+
+	list := func(t *testing.T, query string) models.GamerProfilePage {
+		rr := makeRequest(t, http.MethodGet, "/v1/api/gamer"+query, nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		var page models.GamerProfilePage
+		if err := json.NewDecoder(rr.Body).Decode(&page); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return page
+	}
+
+	t.Run("pages are ordered by name", func(t *testing.T) {
+		page := list(t, "?page=1&limit=2")
+		if page.Total != 3 || len(page.Profiles) != 2 {
+			t.Fatalf("expected 2 of 3 profiles, got %d of %d", len(page.Profiles), page.Total)
+		}
+		if page.Profiles[0].LastName != "Hopper" || page.Profiles[1].LastName != "Lovelace" {
+			t.Errorf("unexpected order %s, %s", page.Profiles[0].LastName, page.Profiles[1].LastName)
+		}
+
+		page = list(t, "?page=2&limit=2")
+		if len(page.Profiles) != 1 || page.Profiles[0].LastName != "Turing" {
+			t.Errorf("unexpected second page %+v", page.Profiles)
+		}
+	})
+
+	t.Run("pages past the end still count matches", func(t *testing.T) {
+		page := list(t, "?page=5&limit=2")
+		if page.Total != 3 || len(page.Profiles) != 0 {
+			t.Errorf("expected no profiles of 3, got %d of %d", len(page.Profiles), page.Total)
+		}
+	})
+
+	t.Run("filters by tier and banned", func(t *testing.T) {
+		page := list(t, "?membership_tier=1&banned=false")
+		if page.Total != 1 || page.Profiles[0].StudentNumber != "51111111" {
+			t.Errorf("expected only 51111111, got %+v", page.Profiles)
+		}
+	})
+
+	t.Run("searches names and student numbers", func(t *testing.T) {
+		if page := list(t, "?search=alan%20tur"); page.Total != 1 || page.Profiles[0].StudentNumber != "52222222" {
+			t.Errorf("expected only 52222222, got %+v", page.Profiles)
+		}
+		if page := list(t, "?search=5333"); page.Total != 1 || page.Profiles[0].StudentNumber != "53333333" {
+			t.Errorf("expected only 53333333, got %+v", page.Profiles)
+		}
+	})
+
+	t.Run("search wildcards are matched literally", func(t *testing.T) {
+		for _, query := range []string{"?search=%25", "?search=_", "?search=a_a"} {
+			if page := list(t, query); page.Total != 0 {
+				t.Errorf("%s: expected no matches, got %+v", query, page.Profiles)
+			}
+		}
+	})
+
+	t.Run("empty result is an empty list", func(t *testing.T) {
+		page := list(t, "?search=nobody")
+		if page.Total != 0 || page.Profiles == nil {
+			t.Errorf("expected an empty list, got %+v", page)
+		}
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodGet, "/v1/api/gamer?limit=1000", nil)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+		}
+	})
+}
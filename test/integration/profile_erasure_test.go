@@ -0,0 +1,120 @@
+//go:build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ubcesports/echo-base/internal/database"
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+func TestProfileDeletion(t *testing.T) {
+	cleanupTestData(t)
+
+	req := models.CreateGamerProfileRequest{
+		StudentNumber:  "77777777",
+		FirstName:      "Played",
+		LastName:       "Before",
+		MembershipTier: 2,
+	}
+	if rr := makeRequest(t, http.MethodPost, "/v1/api/gamer", req); rr.Code != http.StatusCreated {
+		t.Fatalf("failed to create test profile: %s", rr.Body.String())
+	}
+
+	checkIn := models.CreateActivityRequest{StudentNumber: "77777777", PCNumber: 7, Game: "Test"}
+	if rr := makeRequest(t, http.MethodPost, "/v1/api/activity", checkIn); rr.Code != http.StatusCreated {
+		t.Fatalf("failed to start activity: %s", rr.Body.String())
+	}
+
+	t.Run("delete member with activity", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodDelete, "/v1/api/gamer/77777777", nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		if rr := makeRequest(t, http.MethodGet, "/v1/api/gamer/77777777", nil); rr.Code != http.StatusNotFound {
+			t.Errorf("expected deleted profile to be hidden, got %d", rr.Code)
+		}
+		if rr := makeRequest(t, http.MethodPost, "/v1/api/activity", checkIn); rr.Code != http.StatusNotFound {
+			t.Errorf("expected deleted member to be unable to check in, got %d", rr.Code)
+		}
+		if rr := makeRequest(t, http.MethodPost, "/v1/api/gamer", req); rr.Code != http.StatusConflict {
+			t.Errorf("expected registering a deleted member to be %d, got %d: %s", http.StatusConflict, rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodPost, "/v1/api/gamer/77777777/restore", nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		if rr := makeRequest(t, http.MethodGet, "/v1/api/gamer/77777777", nil); rr.Code != http.StatusOK {
+			t.Errorf("expected restored profile to be visible, got %d", rr.Code)
+		}
+		if rr := makeRequest(t, http.MethodPost, "/v1/api/gamer/77777777/restore", nil); rr.Code != http.StatusNotFound {
+			t.Errorf("expected restoring an active profile to be %d, got %d", http.StatusNotFound, rr.Code)
+		}
+	})
+
+	t.Run("erase keeps activity", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodPost, "/v1/api/gamer/77777777/bans", models.IssueBanRequest{Reason: "Private ban reason"})
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("failed to ban profile: %d %s", rr.Code, rr.Body.String())
+		}
+		var ban models.Ban
+		if err := json.NewDecoder(rr.Body).Decode(&ban); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		lift := models.LiftBanRequest{Reason: "Private lift reason"}
+		if rr := makeRequest(t, http.MethodPost, "/v1/api/gamer/77777777/bans/"+ban.ID+"/lift", lift); rr.Code != http.StatusOK {
+			t.Fatalf("failed to lift ban: %d %s", rr.Code, rr.Body.String())
+		}
+		note := models.CreateMemberNoteRequest{Body: "Private note body"}
+		if rr := makeRequest(t, http.MethodPost, "/v1/api/gamer/77777777/notes", note); rr.Code != http.StatusCreated {
+			t.Fatalf("failed to add note: %d %s", rr.Code, rr.Body.String())
+		}
+
+		rr = makeRequest(t, http.MethodPost, "/v1/api/gamer/77777777/erase", nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		if rr := makeRequest(t, http.MethodGet, "/v1/api/gamer/77777777", nil); rr.Code != http.StatusNotFound {
+			t.Errorf("expected erased profile to be gone, got %d", rr.Code)
+		}
+		if rr := makeRequest(t, http.MethodPost, "/v1/api/gamer/77777777/restore", nil); rr.Code != http.StatusNotFound {
+			t.Errorf("expected erased profile to be unrestorable, got %d", rr.Code)
+		}
+
+		var remaining int
+		err := database.DB.QueryRow("SELECT COUNT(*) FROM gamer_profile WHERE first_name = 'Played' OR student_number = '77777777'").Scan(&remaining)
+		if err != nil {
+			t.Fatalf("failed to query profiles: %v", err)
+		}
+		if remaining != 0 {
+			t.Errorf("expected personal fields to be erased, found %d profiles", remaining)
+		}
+
+		var sessions int
+		err = database.DB.QueryRow("SELECT COUNT(*) FROM gamer_activity ga JOIN gamer_profile gp ON ga.student_number = gp.student_number WHERE gp.erased_at IS NOT NULL").Scan(&sessions)
+		if err != nil {
+			t.Fatalf("failed to query activity: %v", err)
+		}
+		if sessions != 1 {
+			t.Errorf("expected the session to be kept under the pseudonym, got %d", sessions)
+		}
+
+		var leaked int
+		err = database.DB.QueryRow("SELECT COUNT(*) FROM audit_log WHERE before::TEXT LIKE '%Private%' OR after::TEXT LIKE '%Private%'").Scan(&leaked)
+		if err != nil {
+			t.Fatalf("failed to query audit log: %v", err)
+		}
+		if leaked != 0 {
+			t.Errorf("expected ban reasons and note bodies to be erased from the audit log, found %d entries", leaked)
+		}
+	})
+}
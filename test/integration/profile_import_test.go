@@ -0,0 +1,140 @@
+//go:build integration
+
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+const importCSV = "First Name,Last Name,Student Number,Ticket Type\n" +
+	"Import,One,81111111,Tier 1 Membership\n" +
+	"Import,Two,82222222,Premier Membership\n"
+
+func makeImportRequest(t *testing.T, csv string, fields map[string]string) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for name, value := range fields {
+		form.WriteField(name, value)
+	}
+	part, err := form.CreateFormFile("file", "members.csv")
+	if err != nil {
+		t.Fatalf("failed to create form file: %v", err)
+	}
+	part.Write([]byte(csv))
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/api/gamer/import", &body)
+	req.Header.Set("Authorization", "Bearer "+testAPIKey)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	rr := httptest.NewRecorder()
+	testServer.ServeHTTP(rr, req)
+	return rr
+}
+
+func decodeImportReport(t *testing.T, rr *httptest.ResponseRecorder) models.ImportReport {
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	var report models.ImportReport
+	if err := json.NewDecoder(rr.Body).Decode(&report); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+	return report
+}
+
+func TestImportProfiles(t *testing.T) {
+	cleanupTestData(t)
+
+	t.Run("dry run writes nothing", func(t *testing.T) {
+		report := decodeImportReport(t, makeImportRequest(t, importCSV, map[string]string{"dry_run": "true"}))
+
+		if report.Created != 2 || report.Committed {
+			t.Errorf("unexpected report %+v", report)
+		}
+		if rr := makeRequest(t, http.MethodGet, "/v1/api/gamer/81111111", nil); rr.Code != http.StatusNotFound {
+			t.Errorf("expected dry run not to create a profile, got %d", rr.Code)
+		}
+	})
+
+	t.Run("atomic import with a conflict writes nothing", func(t *testing.T) {
+		csv := importCSV + "Someone,Else,81111111,Tier 1 Membership\n"
+		report := decodeImportReport(t, makeImportRequest(t, csv, map[string]string{"atomic": "true"}))
+
+		if report.Conflicts != 1 || report.Committed {
+			t.Errorf("unexpected report %+v", report)
+		}
+		if rr := makeRequest(t, http.MethodGet, "/v1/api/gamer/81111111", nil); rr.Code != http.StatusNotFound {
+			t.Errorf("expected abandoned import not to create a profile, got %d", rr.Code)
+		}
+	})
+
+	t.Run("atomic import", func(t *testing.T) {
+		report := decodeImportReport(t, makeImportRequest(t, importCSV, map[string]string{"atomic": "true"}))
+
+		if report.Created != 2 || !report.Committed {
+			t.Errorf("unexpected report %+v", report)
+		}
+
+		rr := makeRequest(t, http.MethodGet, "/v1/api/gamer/82222222/memberships", nil)
+		var memberships []models.Membership
+		json.NewDecoder(rr.Body).Decode(&memberships)
+		if len(memberships) != 1 || memberships[0].MembershipTier != 3 || memberships[0].Source != models.MembershipSourceShowpass {
+			t.Errorf("unexpected memberships %+v", memberships)
+		}
+	})
+
+	t.Run("re-import skips current memberships", func(t *testing.T) {
+		report := decodeImportReport(t, makeImportRequest(t, importCSV, nil))
+
+		if report.Skipped != 2 || report.Updated != 0 {
+			t.Errorf("unexpected report %+v", report)
+		}
+
+		rr := makeRequest(t, http.MethodGet, "/v1/api/gamer/82222222/memberships", nil)
+		var memberships []models.Membership
+		json.NewDecoder(rr.Body).Decode(&memberships)
+		if len(memberships) != 1 {
+			t.Errorf("expected 1 membership, got %d", len(memberships))
+		}
+	})
+
+	t.Run("dry run reports emails taken in another case", func(t *testing.T) {
+		patch := map[string]any{"email": "import.two@example.com"}
+		if rr := makeRequest(t, http.MethodPatch, "/v1/api/gamer/82222222", patch); rr.Code != http.StatusOK {
+			t.Fatalf("failed to set email: %d %s", rr.Code, rr.Body.String())
+		}
+
+		csv := "First Name,Last Name,Student Number,Ticket Type,Email\nImport,Three,83333333,1,Import.Two@Example.com\n"
+		columns := `{"email":"Email"}`
+		report := decodeImportReport(t, makeImportRequest(t, csv, map[string]string{"dry_run": "true", "columns": columns}))
+
+		if report.Conflicts != 1 || report.Rows[0].Field != "email" {
+			t.Errorf("unexpected report %+v", report)
+		}
+	})
+
+	t.Run("custom columns", func(t *testing.T) {
+		csv := "sid,given,family,level\n81111111,Import,One,2\n"
+		columns := `{"student_number":"sid","first_name":"given","last_name":"family","membership_tier":"level"}`
+		report := decodeImportReport(t, makeImportRequest(t, csv, map[string]string{"columns": columns}))
+
+		if report.Updated != 1 || !report.Committed {
+			t.Errorf("unexpected report %+v", report)
+		}
+	})
+
+	t.Run("missing column", func(t *testing.T) {
+		rr := makeImportRequest(t, "Name,Student Number\nA,81111111\n", nil)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+		}
+	})
+}
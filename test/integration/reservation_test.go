@@ -0,0 +1,259 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ubcesports/echo-base/internal/database"
+	"github.com/ubcesports/echo-base/internal/models"
+	"github.com/ubcesports/echo-base/internal/services"
+)
+
+func TestReservations(t *testing.T) {
+	cleanupTestData(t)
+
+	for i := range 5 {
+		req := models.CreateGamerProfileRequest{
+			StudentNumber:  fmt.Sprintf("7777777%d", i),
+			FirstName:      "Booker",
+			LastName:       fmt.Sprint(i),
+			MembershipTier: 2,
+		}
+		if rr := makeRequest(t, http.MethodPost, "/v1/api/gamer", req); rr.Code != http.StatusCreated {
+			t.Fatalf("failed to create test profile: %s", rr.Body.String())
+		}
+	}
+
+	now := time.Now().Truncate(time.Second)
+	team := "UBC Rocket League"
+
+	// reserve books stations and returns the response
+	reserve := func(t *testing.T, req models.CreateReservationRequest) (*models.Reservation, int) {
+		rr := makeRequest(t, http.MethodPost, "/v1/api/reservations", req)
+		if rr.Code != http.StatusCreated {
+			return nil, rr.Code
+		}
+		var created models.Reservation
+		if err := json.NewDecoder(rr.Body).Decode(&created); err != nil {
+			t.Fatalf("failed to decode reservation: %v", err)
+		}
+		return &created, rr.Code
+	}
+
+	var held, teamRes *models.Reservation
+
+	t.Run("reserve", func(t *testing.T) {
+		var code int
+		held, code = reserve(t, models.CreateReservationRequest{
+			StationNumbers: []int{21},
+			StudentNumbers: []string{"77777770"},
+			StartsAt:       time.Now(),
+			EndsAt:         now.Add(time.Hour),
+		})
+		if code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d", http.StatusCreated, code)
+		}
+
+		teamRes, code = reserve(t, models.CreateReservationRequest{
+			StationNumbers: []int{22, 23},
+			StudentNumbers: []string{"77777772", "77777773"},
+			TeamName:       &team,
+			StartsAt:       now.Add(time.Hour),
+			EndsAt:         now.Add(3 * time.Hour),
+		})
+		if code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d", http.StatusCreated, code)
+		}
+		if len(teamRes.StationNumbers) != 2 || len(teamRes.StudentNumbers) != 2 {
+			t.Errorf("expected both stations and members, got %+v", teamRes)
+		}
+	})
+
+	t.Run("overlap", func(t *testing.T) {
+		_, code := reserve(t, models.CreateReservationRequest{
+			StationNumbers: []int{24, 21},
+			StudentNumbers: []string{"77777771"},
+			StartsAt:       now.Add(30 * time.Minute),
+			EndsAt:         now.Add(90 * time.Minute),
+		})
+		if code != http.StatusConflict {
+			t.Errorf("expected status %d, got %d", http.StatusConflict, code)
+		}
+
+		// Back to back reservations don't overlap
+		next, code := reserve(t, models.CreateReservationRequest{
+			StationNumbers: []int{21},
+			StudentNumbers: []string{"77777771"},
+			StartsAt:       now.Add(time.Hour),
+			EndsAt:         now.Add(2 * time.Hour),
+		})
+		if code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d", http.StatusCreated, code)
+		}
+		rr := makeRequest(t, http.MethodPost, "/v1/api/reservations/"+next.ID+"/cancel", nil)
+		if rr.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("concurrent reservations", func(t *testing.T) {
+		codes := make([]int, 5)
+		var wg sync.WaitGroup
+		for i := range codes {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, codes[i] = reserve(t, models.CreateReservationRequest{
+					StationNumbers: []int{25},
+					StudentNumbers: []string{fmt.Sprintf("7777777%d", i)},
+					StartsAt:       now.Add(4*time.Hour + time.Duration(i)*time.Minute),
+					EndsAt:         now.Add(5 * time.Hour),
+				})
+			}()
+		}
+		wg.Wait()
+
+		counts := map[int]int{}
+		for _, code := range codes {
+			counts[code]++
+		}
+		if counts[http.StatusCreated] != 1 || counts[http.StatusConflict] != 4 {
+			t.Errorf("expected one reservation and four conflicts, got %v", counts)
+		}
+	})
+
+	t.Run("only the holder can start", func(t *testing.T) {
+		req := models.CreateActivityRequest{StudentNumber: "77777771", PCNumber: 21, Game: "Rocket League"}
+		rr := makeRequest(t, http.MethodPost, "/v1/api/activity", req)
+		if rr.Code != http.StatusConflict {
+			t.Errorf("expected status %d, got %d: %s", http.StatusConflict, rr.Code, rr.Body.String())
+		}
+
+		req.StudentNumber = "77777770"
+		rr = makeRequest(t, http.MethodPost, "/v1/api/activity", req)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rr.Code, rr.Body.String())
+		}
+
+		rr = makeRequest(t, http.MethodGet, "/v1/api/reservations/"+held.ID, nil)
+		var checkedIn models.Reservation
+		json.NewDecoder(rr.Body).Decode(&checkedIn)
+		if checkedIn.Status != models.ReservationStatusCheckedIn || checkedIn.CheckedInAt == nil {
+			t.Errorf("expected the reservation to be checked in, got %+v", checkedIn)
+		}
+	})
+
+	t.Run("walk-ins make way", func(t *testing.T) {
+		req := models.CreateActivityRequest{StudentNumber: "77777771", PCNumber: 22, Game: "Rocket League"}
+		rr := makeRequest(t, http.MethodPost, "/v1/api/activity", req)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rr.Code, rr.Body.String())
+		}
+
+		var session models.GamerActivity
+		json.NewDecoder(rr.Body).Decode(&session)
+		if session.ExpiresAt == nil || !session.ExpiresAt.Equal(teamRes.StartsAt) {
+			t.Errorf("expected the session to run out at %v, got %v", teamRes.StartsAt, session.ExpiresAt)
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodGet, "/v1/api/reservations?student_number=77777772&station=22", nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+		var reservations []models.Reservation
+		json.NewDecoder(rr.Body).Decode(&reservations)
+		if len(reservations) != 1 || reservations[0].ID != teamRes.ID {
+			t.Errorf("expected the team reservation, got %+v", reservations)
+		}
+
+		rr = makeRequest(t, http.MethodGet, "/v1/api/reservations?station=21&status=cancelled", nil)
+		json.NewDecoder(rr.Body).Decode(&reservations)
+		if len(reservations) != 1 || reservations[0].Status != models.ReservationStatusCancelled {
+			t.Errorf("expected the cancelled reservation, got %+v", reservations)
+		}
+
+		rr = makeRequest(t, http.MethodGet, "/v1/api/reservations?limit=1", nil)
+		json.NewDecoder(rr.Body).Decode(&reservations)
+		if len(reservations) != 1 {
+			t.Errorf("expected a page of 1 reservation, got %d", len(reservations))
+		}
+
+		if rr := makeRequest(t, http.MethodGet, "/v1/api/reservations?limit=101", nil); rr.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, rr.Code)
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodPost, "/v1/api/reservations/"+teamRes.ID+"/cancel", nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		rr = makeRequest(t, http.MethodPost, "/v1/api/reservations/"+teamRes.ID+"/cancel", nil)
+		if rr.Code != http.StatusConflict {
+			t.Errorf("expected status %d, got %d: %s", http.StatusConflict, rr.Code, rr.Body.String())
+		}
+
+		_, code := reserve(t, models.CreateReservationRequest{
+			StationNumbers: []int{23},
+			StudentNumbers: []string{"77777774"},
+			StartsAt:       teamRes.StartsAt,
+			EndsAt:         teamRes.EndsAt,
+		})
+		if code != http.StatusCreated {
+			t.Errorf("expected the cancelled slot to be free, got %d", code)
+		}
+	})
+
+	t.Run("no-shows are released", func(t *testing.T) {
+		noShow, code := reserve(t, models.CreateReservationRequest{
+			StationNumbers: []int{26},
+			StudentNumbers: []string{"77777774"},
+			StartsAt:       time.Now(),
+			EndsAt:         now.Add(time.Hour),
+		})
+		if code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d", http.StatusCreated, code)
+		}
+
+		service := services.NewReservationServiceWithConfig(
+			database.NewReservationRepository(database.DB),
+			database.NewStationRepository(database.DB),
+			database.NewGamerProfileRepository(database.DB),
+			services.NewAuditService(database.NewAuditRepository(database.DB)),
+			services.DefaultReservationServiceConfig(),
+		)
+		if _, err := service.ReleaseNoShows(context.Background(), now.Add(30*time.Minute)); err != nil {
+			t.Fatalf("ReleaseNoShows() error = %v", err)
+		}
+
+		rr := makeRequest(t, http.MethodGet, "/v1/api/reservations/"+noShow.ID, nil)
+		var released models.Reservation
+		json.NewDecoder(rr.Body).Decode(&released)
+		if released.Status != models.ReservationStatusNoShow || released.ReleasedAt == nil {
+			t.Errorf("expected the reservation to be released, got %+v", released)
+		}
+
+		req := models.CreateActivityRequest{StudentNumber: "77777773", PCNumber: 26, Game: "Rocket League"}
+		if rr := makeRequest(t, http.MethodPost, "/v1/api/activity", req); rr.Code != http.StatusCreated {
+			t.Errorf("expected the released station to be free, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		// The checked in reservation is kept
+		rr = makeRequest(t, http.MethodGet, "/v1/api/reservations/"+held.ID, nil)
+		var kept models.Reservation
+		json.NewDecoder(rr.Body).Decode(&kept)
+		if kept.Status != models.ReservationStatusCheckedIn {
+			t.Errorf("expected the checked in reservation to be kept, got %s", kept.Status)
+		}
+	})
+}
@@ -0,0 +1,134 @@
+//go:build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+func TestSessionConflicts(t *testing.T) {
+	cleanupTestData(t)
+
+	for i := range 10 {
+		req := models.CreateGamerProfileRequest{
+			StudentNumber:  fmt.Sprintf("5555555%d", i),
+			FirstName:      "Racer",
+			LastName:       fmt.Sprint(i),
+			MembershipTier: 2,
+		}
+		if rr := makeRequest(t, http.MethodPost, "/v1/api/gamer", req); rr.Code != http.StatusCreated {
+			t.Fatalf("failed to create test profile: %s", rr.Body.String())
+		}
+	}
+
+	// startAll starts every session at once and returns the response codes
+	startAll := func(reqs []models.CreateActivityRequest) map[int]int {
+		codes := make([]int, len(reqs))
+		var wg sync.WaitGroup
+		for i, req := range reqs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				codes[i] = makeRequest(t, http.MethodPost, "/v1/api/activity", req).Code
+			}()
+		}
+		wg.Wait()
+
+		counts := map[int]int{}
+		for _, code := range codes {
+			counts[code]++
+		}
+		return counts
+	}
+
+	t.Run("concurrent starts on one PC", func(t *testing.T) {
+		var reqs []models.CreateActivityRequest
+		for i := range 5 {
+			reqs = append(reqs, models.CreateActivityRequest{StudentNumber: fmt.Sprintf("5555555%d", i), PCNumber: 1, Game: "Test"})
+		}
+
+		counts := startAll(reqs)
+		if counts[http.StatusCreated] != 1 || counts[http.StatusConflict] != 4 {
+			t.Errorf("expected one session to start and four conflicts, got %v", counts)
+		}
+	})
+
+	t.Run("concurrent starts for one student", func(t *testing.T) {
+		var reqs []models.CreateActivityRequest
+		for pc := 10; pc < 15; pc++ {
+			reqs = append(reqs, models.CreateActivityRequest{StudentNumber: "55555559", PCNumber: pc, Game: "Test"})
+		}
+
+		counts := startAll(reqs)
+		if counts[http.StatusCreated] != 1 || counts[http.StatusConflict] != 4 {
+			t.Errorf("expected one session to start and four conflicts, got %v", counts)
+		}
+	})
+
+	// activePCs maps each signed in student to their PC
+	activePCs := func(t *testing.T) map[string]int {
+		rr := makeRequest(t, http.MethodGet, "/v1/api/activity/all/get-active-pcs", nil)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, rr.Code)
+		}
+		var sessions []models.GamerActivity
+		if err := json.NewDecoder(rr.Body).Decode(&sessions); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+
+		pcs := map[string]int{}
+		for _, session := range sessions {
+			pcs[session.StudentNumber] = session.PCNumber
+		}
+		return pcs
+	}
+
+	t.Run("conflict names the occupant", func(t *testing.T) {
+		occupant := ""
+		for sn, pc := range activePCs(t) {
+			if pc == 1 {
+				occupant = sn
+			}
+		}
+		if occupant == "" {
+			t.Fatal("expected PC 1 to be in use")
+		}
+
+		rr := makeRequest(t, http.MethodPost, "/v1/api/activity", models.CreateActivityRequest{StudentNumber: "55555558", PCNumber: 1, Game: "Test"})
+		if rr.Code != http.StatusConflict {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusConflict, rr.Code, rr.Body.String())
+		}
+		if !strings.Contains(rr.Body.String(), "PC 1 is in use by") || !strings.Contains(rr.Body.String(), occupant) {
+			t.Errorf("expected the conflict to name %s, got %s", occupant, rr.Body.String())
+		}
+
+		rr = makeRequest(t, http.MethodPost, "/v1/api/activity", models.CreateActivityRequest{StudentNumber: occupant, PCNumber: 2, Game: "Test"})
+		if rr.Code != http.StatusConflict || !strings.Contains(rr.Body.String(), "already signed in on PC 1") {
+			t.Errorf("expected a conflict naming PC 1, got %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("ending frees the student", func(t *testing.T) {
+		pc, ok := activePCs(t)["55555559"]
+		if !ok {
+			t.Fatal("expected 55555559 to be signed in")
+		}
+
+		rr := makeExecRequest(t, "TestExec", http.MethodPatch, "/v1/api/activity/update/55555559", models.UpdateActivityRequest{PCNumber: pc})
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rr.Code, rr.Body.String())
+		}
+
+		rr = makeRequest(t, http.MethodPost, "/v1/api/activity", models.CreateActivityRequest{StudentNumber: "55555559", PCNumber: 20, Game: "Test"})
+		if rr.Code != http.StatusCreated {
+			t.Errorf("expected status %d, got %d: %s", http.StatusCreated, rr.Code, rr.Body.String())
+		}
+	})
+}
@@ -0,0 +1,121 @@
+//go:build integration
+
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ubcesports/echo-base/internal/models"
+	"github.com/ubcesports/echo-base/internal/services"
+)
+
+func deliverShowpassWebhook(t *testing.T, webhook models.ShowpassWebhook, signature string) *httptest.ResponseRecorder {
+	body, err := json.Marshal(webhook)
+	if err != nil {
+		t.Fatalf("failed to marshal webhook: %v", err)
+	}
+	if signature == "" {
+		signature = services.SignShowpassPayload([]byte(testShowpassSecret), body)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/showpass", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(services.ShowpassSignatureHeader, signature)
+
+	rr := httptest.NewRecorder()
+	testServer.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestShowpassWebhook(t *testing.T) {
+	cleanupTestData(t)
+
+	webhook := models.ShowpassWebhook{
+		Event: models.ShowpassEventPurchase,
+		Purchase: models.ShowpassPurchase{
+			ID:            "integration-purchase-1",
+			TicketType:    "Tier 2 Membership",
+			StudentNumber: "91111111",
+			FirstName:     "Web",
+			LastName:      "Hook",
+		},
+	}
+
+	t.Run("rejects bad signatures", func(t *testing.T) {
+		rr := deliverShowpassWebhook(t, webhook, "00")
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("expected status %d, got %d", http.StatusUnauthorized, rr.Code)
+		}
+		if rr := makeRequest(t, http.MethodGet, "/v1/api/gamer/91111111", nil); rr.Code != http.StatusNotFound {
+			t.Errorf("expected no profile from an unsigned delivery, got %d", rr.Code)
+		}
+	})
+
+	t.Run("provisions the member", func(t *testing.T) {
+		rr := deliverShowpassWebhook(t, webhook, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		var result models.ShowpassWebhookResult
+		json.NewDecoder(rr.Body).Decode(&result)
+		if result.Status != models.ShowpassWebhookProvisioned {
+			t.Errorf("expected provisioned, got %s", result.Status)
+		}
+
+		rr = makeRequest(t, http.MethodGet, "/v1/api/gamer/91111111", nil)
+		var profile models.GamerProfile
+		json.NewDecoder(rr.Body).Decode(&profile)
+		if profile.MembershipTier != 2 || profile.FirstName != "Web" {
+			t.Errorf("unexpected profile %+v", profile)
+		}
+	})
+
+	t.Run("redelivery records nothing", func(t *testing.T) {
+		rr := deliverShowpassWebhook(t, webhook, "")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		var result models.ShowpassWebhookResult
+		json.NewDecoder(rr.Body).Decode(&result)
+		if result.Status != models.ShowpassWebhookDuplicate {
+			t.Errorf("expected duplicate, got %s", result.Status)
+		}
+
+		rr = makeRequest(t, http.MethodGet, "/v1/api/gamer/91111111/memberships", nil)
+		var memberships []models.Membership
+		json.NewDecoder(rr.Body).Decode(&memberships)
+		if len(memberships) != 1 {
+			t.Errorf("expected 1 membership, got %d", len(memberships))
+		}
+	})
+
+	t.Run("another name is a conflict", func(t *testing.T) {
+		other := webhook
+		other.Purchase.ID = "integration-purchase-2"
+		other.Purchase.FirstName = "Someone"
+		if rr := deliverShowpassWebhook(t, other, ""); rr.Code != http.StatusConflict {
+			t.Errorf("expected status %d, got %d: %s", http.StatusConflict, rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("deleted member stays deleted", func(t *testing.T) {
+		if rr := makeRequest(t, http.MethodDelete, "/v1/api/gamer/91111111", nil); rr.Code != http.StatusOK {
+			t.Fatalf("failed to delete profile: %d %s", rr.Code, rr.Body.String())
+		}
+
+		renewal := webhook
+		renewal.Purchase.ID = "integration-purchase-3"
+		if rr := deliverShowpassWebhook(t, renewal, ""); rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+		if rr := makeRequest(t, http.MethodGet, "/v1/api/gamer/91111111", nil); rr.Code != http.StatusNotFound {
+			t.Errorf("expected the profile to stay deleted, got %d", rr.Code)
+		}
+	})
+}
@@ -0,0 +1,135 @@
+//go:build integration
+
+package integration
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ubcesports/echo-base/internal/handlers"
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
+)
+
+// makeSignedRequest sends a request authenticated with an HMAC signature
+// made with the signing key of apiKey rather than the key itself
+func makeSignedRequest(t *testing.T, apiKey *auth.APIKey, method, path string, body []byte, signedAt time.Time, nonce string) *httptest.ResponseRecorder {
+	signed := &auth.SignedRequest{
+		KeyId:     apiKey.KeyId,
+		Timestamp: signedAt.Unix(),
+		Nonce:     nonce,
+		Method:    method,
+		Path:      path,
+		BodyHash:  auth.HashBody(body),
+	}
+
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	req.Header.Set("Authorization", fmt.Sprintf("%s KeyId=%s, Timestamp=%d, Nonce=%s, Signature=%s",
+		auth.HMACScheme, signed.KeyId, signed.Timestamp, signed.Nonce, signed.Sign([]byte(apiKey.SigningKey))))
+	req.Header.Set("Content-Type", "application/json")
+
+	rr := httptest.NewRecorder()
+	testServer.ServeHTTP(rr, req)
+	return rr
+}
+
+func randomNonce(t *testing.T) string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatalf("failed to generate nonce: %v", err)
+	}
+	return hex.EncodeToString(b)
+}
+
+func TestSignedRequests(t *testing.T) {
+	var kioskKey auth.APIKey
+
+	req := handlers.GenerateKeyRequest{
+		AppName:  "integration-signed-kiosk",
+		Scopes:   []auth.Scope{auth.ScopeActivityRead},
+		AuthMode: auth.AuthModeHMAC,
+	}
+	rr := makeRequest(t, http.MethodPost, "/admin/generate-key", req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&kioskKey); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	path := "/v1/api/activity/all/get-active-pcs"
+
+	t.Run("signed request is accepted", func(t *testing.T) {
+		rr := makeSignedRequest(t, &kioskKey, http.MethodGet, path, nil, time.Now(), randomNonce(t))
+		if rr.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("bearer token is rejected", func(t *testing.T) {
+		rr := makeRequestWithKey(t, kioskKey.APIKey, http.MethodGet, path, nil)
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("expected status %d, got %d", http.StatusUnauthorized, rr.Code)
+		}
+	})
+
+	t.Run("replayed nonce is rejected", func(t *testing.T) {
+		nonce := randomNonce(t)
+		signedAt := time.Now()
+
+		rr := makeSignedRequest(t, &kioskKey, http.MethodGet, path, nil, signedAt, nonce)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		rr = makeSignedRequest(t, &kioskKey, http.MethodGet, path, nil, signedAt, nonce)
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("expected status %d, got %d", http.StatusUnauthorized, rr.Code)
+		}
+	})
+
+	t.Run("stale timestamp is rejected", func(t *testing.T) {
+		rr := makeSignedRequest(t, &kioskKey, http.MethodGet, path, nil, time.Now().Add(-time.Hour), randomNonce(t))
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("expected status %d, got %d", http.StatusUnauthorized, rr.Code)
+		}
+	})
+
+	t.Run("switch back to bearer", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodPut, "/admin/keys/"+kioskKey.KeyId+"/auth-mode", handlers.UpdateAuthModeRequest{AuthMode: auth.AuthModeBearer})
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		rr = makeRequestWithKey(t, kioskKey.APIKey, http.MethodGet, path, nil)
+		if rr.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("switch to hmac returns the signing key", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodPut, "/admin/keys/"+kioskKey.KeyId+"/auth-mode", handlers.UpdateAuthModeRequest{AuthMode: auth.AuthModeHMAC})
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		var resp handlers.UpdateAuthModeResponse
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if resp.SigningKey != kioskKey.SigningKey {
+			t.Errorf("expected the signing key to be unchanged, got %q", resp.SigningKey)
+		}
+
+		rr = makeSignedRequest(t, &kioskKey, http.MethodGet, path, nil, time.Now(), randomNonce(t))
+		if rr.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+	})
+}
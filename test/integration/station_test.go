@@ -0,0 +1,196 @@
+//go:build integration
+
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ubcesports/echo-base/internal/database"
+	"github.com/ubcesports/echo-base/internal/handlers"
+	"github.com/ubcesports/echo-base/internal/interfaces/auth"
+	"github.com/ubcesports/echo-base/internal/models"
+)
+
+func TestStationEndpoints(t *testing.T) {
+	cleanupTestData(t)
+	if _, err := database.DB.Exec("DELETE FROM station WHERE number > 100"); err != nil {
+		t.Fatalf("failed to clean stations: %v", err)
+	}
+	t.Cleanup(func() {
+		database.DB.Exec("DELETE FROM gamer_activity WHERE pc_number > 100")
+		database.DB.Exec("DELETE FROM station WHERE number > 100")
+	})
+
+	profile := models.CreateGamerProfileRequest{
+		StudentNumber:  "66666666",
+		FirstName:      "Station",
+		LastName:       "User",
+		MembershipTier: 2,
+	}
+	if rr := makeRequest(t, http.MethodPost, "/v1/api/gamer", profile); rr.Code != http.StatusCreated {
+		t.Fatalf("failed to create test profile: %s", rr.Body.String())
+	}
+
+	t.Run("create station", func(t *testing.T) {
+		req := models.CreateStationRequest{
+			Number: 101,
+			Zone:   ptrString("Back row"),
+			Specs:  map[string]string{"gpu": "RTX 4070", "monitor": "240Hz"},
+		}
+		rr := makeRequest(t, http.MethodPost, "/v1/api/stations", req)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rr.Code, rr.Body.String())
+		}
+
+		var created models.Station
+		if err := json.NewDecoder(rr.Body).Decode(&created); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if created.Type != models.StationTypePC || created.Status != models.StationStatusAvailable {
+			t.Errorf("expected an available pc, got %s %s", created.Status, created.Type)
+		}
+		if created.Specs["gpu"] != "RTX 4070" {
+			t.Errorf("expected specs to be kept, got %v", created.Specs)
+		}
+
+		rr = makeRequest(t, http.MethodPost, "/v1/api/stations", req)
+		if rr.Code != http.StatusConflict {
+			t.Errorf("expected duplicate number to be rejected, got %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("unknown station", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodGet, "/v1/api/stations/199", nil)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d: %s", http.StatusNotFound, rr.Code, rr.Body.String())
+		}
+
+		req := models.CreateActivityRequest{StudentNumber: "66666666", PCNumber: 199, Game: "Valorant"}
+		rr = makeRequest(t, http.MethodPost, "/v1/api/activity", req)
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("expected a session on a missing station to be rejected, got %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("maintenance blocks sessions", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodPatch, "/v1/api/stations/101", map[string]any{
+			"status":        "maintenance",
+			"status_reason": "Reimaging",
+		})
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+
+		req := models.CreateActivityRequest{StudentNumber: "66666666", PCNumber: 101, Game: "Valorant"}
+		rr = makeRequest(t, http.MethodPost, "/v1/api/activity", req)
+		if rr.Code != http.StatusConflict {
+			t.Errorf("expected status %d, got %d: %s", http.StatusConflict, rr.Code, rr.Body.String())
+		}
+
+		overview := getStationOverview(t)
+		if overview[101].State != models.StationStateOutOfOrder {
+			t.Errorf("expected station 101 to be out of order, got %s", overview[101].State)
+		}
+	})
+
+	t.Run("overview shows occupied stations", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodPatch, "/v1/api/stations/101", map[string]any{"status": "available"})
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+		var updated models.Station
+		json.NewDecoder(rr.Body).Decode(&updated)
+		if updated.StatusReason != nil {
+			t.Errorf("expected the status reason to be cleared, got %s", *updated.StatusReason)
+		}
+
+		req := models.CreateActivityRequest{StudentNumber: "66666666", PCNumber: 101, Game: "Valorant"}
+		rr = makeRequest(t, http.MethodPost, "/v1/api/activity", req)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rr.Code, rr.Body.String())
+		}
+
+		overview := getStationOverview(t)
+		view := overview[101]
+		if view.State != models.StationStateOccupied || view.Session == nil || view.Session.StudentNumber != "66666666" {
+			t.Errorf("expected station 101 to be occupied by 66666666, got %+v", view)
+		}
+	})
+
+	t.Run("front desk keys only change the status", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodPost, "/admin/generate-key", handlers.GenerateKeyRequest{
+			AppName: "integration-front-desk",
+			Scopes:  []auth.Scope{auth.ScopeActivityRead, auth.ScopeActivityWrite},
+		})
+		if rr.Code != http.StatusOK {
+			t.Fatalf("failed to generate key: %d %s", rr.Code, rr.Body.String())
+		}
+		var key auth.APIKey
+		if err := json.NewDecoder(rr.Body).Decode(&key); err != nil {
+			t.Fatalf("failed to decode key: %v", err)
+		}
+
+		rr = makeRequestWithKey(t, key.APIKey, http.MethodPatch, "/v1/api/stations/101", map[string]any{"zone": "Front row"})
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("expected status %d, got %d: %s", http.StatusForbidden, rr.Code, rr.Body.String())
+		}
+		rr = makeRequestWithKey(t, key.APIKey, http.MethodPost, "/v1/api/stations/101/status", map[string]any{"status": "retired"})
+		if rr.Code != http.StatusForbidden {
+			t.Errorf("expected retiring to be %d, got %d: %s", http.StatusForbidden, rr.Code, rr.Body.String())
+		}
+
+		rr = makeRequestWithKey(t, key.APIKey, http.MethodPost, "/v1/api/stations/101/status", map[string]any{
+			"status":        "maintenance",
+			"status_reason": "Sticky keyboard",
+		})
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+		var updated models.Station
+		json.NewDecoder(rr.Body).Decode(&updated)
+		if updated.Status != models.StationStatusMaintenance || updated.Zone == nil || *updated.Zone != "Back row" {
+			t.Errorf("expected only the status to change, got %+v", updated)
+		}
+
+		rr = makeRequestWithKey(t, key.APIKey, http.MethodPost, "/v1/api/stations/101/status", map[string]any{"status": "available"})
+		if rr.Code != http.StatusOK {
+			t.Fatalf("failed to make station available: %d %s", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		rr := makeRequest(t, http.MethodDelete, "/v1/api/stations/101", nil)
+		if rr.Code != http.StatusConflict {
+			t.Errorf("expected a played on station to be kept, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		rr = makeRequest(t, http.MethodPost, "/v1/api/stations", models.CreateStationRequest{Number: 102, Type: models.StationTypeVR})
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusCreated, rr.Code, rr.Body.String())
+		}
+		rr = makeRequest(t, http.MethodDelete, "/v1/api/stations/102", nil)
+		if rr.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+		}
+	})
+}
+
+// getStationOverview fetches the overview keyed by station number
+func getStationOverview(t *testing.T) map[int]models.StationView {
+	rr := makeRequest(t, http.MethodGet, "/v1/api/stations", nil)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var views []models.StationView
+	if err := json.NewDecoder(rr.Body).Decode(&views); err != nil {
+		t.Fatalf("failed to decode overview: %v", err)
+	}
+	overview := make(map[int]models.StationView, len(views))
+	for _, view := range views {
+		overview[view.Number] = view
+	}
+	return overview
+}